@@ -155,4 +155,5 @@ export interface FeatureToggles {
   alertmanagerRemotePrimary?: boolean;
   alertmanagerRemoteOnly?: boolean;
   annotationPermissionUpdate?: boolean;
+  sqlExpressions?: boolean;
 }
@@ -130,6 +130,11 @@ type UnauthorisedPublicError PublicErrorResponse
 // swagger:response forbiddenPublicError
 type ForbiddenPublicError PublicErrorResponse
 
+// TooManyRequestsPublicError is returned if the client sent too many requests in a given amount of time.
+//
+// swagger:response tooManyRequestsPublicError
+type TooManyRequestsPublicError PublicErrorResponse
+
 // InternalServerPublicError is a general error indicating something went wrong internally.
 //
 // swagger:response internalServerPublicError
@@ -0,0 +1,120 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const defaultAnomalySensitivity = 3
+
+// AnomalyCommand is an expression that detects anomalies in time series locally, without an external service.
+type AnomalyCommand struct {
+	VarToDetect string
+	Options     mathexp.AnomalyOptions
+	// Bands adds the lower and upper bounds of the band of each series to the results.
+	Bands bool
+	refID string
+}
+
+// AnomalyCommandConfig is the model of an anomaly expression in Grafana's frontend query.
+type AnomalyCommandConfig struct {
+	Expression  string   `json:"expression"`
+	Algorithm   string   `json:"algorithm"`
+	Window      string   `json:"window"`
+	Period      string   `json:"period"`
+	Sensitivity *float64 `json:"sensitivity"`
+	Bands       *bool    `json:"bands"`
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToDetect string, opts mathexp.AnomalyOptions, bands bool) (*AnomalyCommand, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &AnomalyCommand{
+		VarToDetect: varToDetect,
+		Options:     opts,
+		Bands:       bands,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	cfg := AnomalyCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	if cfg.Expression == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+
+	opts := mathexp.AnomalyOptions{
+		Algorithm:   cfg.Algorithm,
+		Sensitivity: defaultAnomalySensitivity,
+	}
+	if cfg.Sensitivity != nil {
+		opts.Sensitivity = *cfg.Sensitivity
+	}
+
+	var err error
+	if cfg.Window != "" {
+		if opts.Window, err = gtime.ParseDuration(cfg.Window); err != nil {
+			return nil, fmt.Errorf("failed to parse window: %w", err)
+		}
+	}
+	if cfg.Period != "" {
+		if opts.Period, err = gtime.ParseDuration(cfg.Period); err != nil {
+			return nil, fmt.Errorf("failed to parse period: %w", err)
+		}
+	}
+
+	// the bands are returned unless they are turned off, e.g. in alert rules where every series is evaluated
+	bands := cfg.Bands == nil || *cfg.Bands
+
+	return NewAnomalyCommand(rn.RefID, strings.TrimPrefix(cfg.Expression, "$"), opts, bands)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToDetect}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("algorithm", ac.Options.Algorithm))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToDetect].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			s, bands, err := v.DetectAnomalies(ac.refID, ac.Options)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, s)
+			if ac.Bands {
+				newRes.Values = append(newRes.Values, bands.Lower, bands.Upper)
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
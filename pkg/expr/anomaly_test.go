@@ -0,0 +1,160 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	var tests = []struct {
+		name     string
+		query    string
+		expected mathexp.AnomalyOptions
+		bands    bool
+		isError  bool
+	}{
+		{
+			name:  "rolling stddev with default sensitivity",
+			query: `{ "expression": "$A", "algorithm": "rolling_stddev", "window": "10m" }`,
+			expected: mathexp.AnomalyOptions{
+				Algorithm:   mathexp.AnomalyRollingStdDev,
+				Window:      10 * time.Minute,
+				Sensitivity: defaultAnomalySensitivity,
+			},
+			bands: true,
+		},
+		{
+			name:  "seasonal with sensitivity",
+			query: `{ "expression": "A", "algorithm": "seasonal", "period": "1d", "sensitivity": 2.5 }`,
+			expected: mathexp.AnomalyOptions{
+				Algorithm:   mathexp.AnomalySeasonal,
+				Period:      24 * time.Hour,
+				Sensitivity: 2.5,
+			},
+			bands: true,
+		},
+		{
+			name:  "without bands",
+			query: `{ "expression": "$A", "algorithm": "mad", "window": "10m", "bands": false }`,
+			expected: mathexp.AnomalyOptions{
+				Algorithm:   mathexp.AnomalyMAD,
+				Window:      10 * time.Minute,
+				Sensitivity: defaultAnomalySensitivity,
+			},
+		},
+		{
+			name:    "error when expression is missing",
+			query:   `{ "algorithm": "mad", "window": "10m" }`,
+			isError: true,
+		},
+		{
+			name:    "error when window is invalid",
+			query:   `{ "expression": "$A", "algorithm": "mad", "window": "ten minutes" }`,
+			isError: true,
+		},
+		{
+			name:    "error when window is missing",
+			query:   `{ "expression": "$A", "algorithm": "mad" }`,
+			isError: true,
+		},
+		{
+			name:    "error when algorithm is unknown",
+			query:   `{ "expression": "$A", "algorithm": "prophet", "window": "10m" }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{
+				RefID:    "B",
+				QueryRaw: []byte(test.query),
+			})
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			require.Equal(t, test.expected, cmd.Options)
+			require.Equal(t, test.bands, cmd.Bands)
+		})
+	}
+}
+
+func TestAnomalyCommandExecute(t *testing.T) {
+	cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyOptions{
+		Algorithm:   mathexp.AnomalyRollingStdDev,
+		Window:      time.Hour,
+		Sensitivity: 3,
+	}, true)
+	require.NoError(t, err)
+
+	t.Run("series are marked as anomalous", func(t *testing.T) {
+		values := []float64{10, 11, 10, 9, 10, 50}
+		series := mathexp.NewSeries("A", data.Labels{"host": "a"}, len(values))
+		for i, v := range values {
+			series.SetPoint(i, time.Unix(int64(i*60), 0), fp(v))
+		}
+
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{series}},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 3)
+
+		s, ok := res.Values[0].(mathexp.Series)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+		require.Equal(t, "B", s.Frame.RefID)
+		require.Equal(t, fp(0), s.GetValue(4))
+		require.Equal(t, fp(1), s.GetValue(5))
+
+		// the bounds of the band follow the series they belong to
+		lower, ok := res.Values[1].(mathexp.Series)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a", "band": "lower"}, lower.GetLabels())
+		upper, ok := res.Values[2].(mathexp.Series)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a", "band": "upper"}, upper.GetLabels())
+		require.Less(t, *lower.GetValue(5), 50.0)
+		require.Less(t, *upper.GetValue(5), 50.0)
+	})
+
+	t.Run("bands can be turned off", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", cmd.Options, false)
+		require.NoError(t, err)
+
+		series := mathexp.NewSeries("A", data.Labels{"host": "a"}, 1)
+		series.SetPoint(0, time.Unix(0, 0), fp(1))
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{series}},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, parse.TypeNoData, res.Values[0].Type())
+	})
+
+	t.Run("error for numbers", func(t *testing.T) {
+		_, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}},
+		}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
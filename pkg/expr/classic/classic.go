@@ -306,6 +306,9 @@ func UnmarshalConditionsCmd(rawQuery map[string]any, refID string) (*ConditionsC
 
 		cond.Reducer = reducer(cj.Reducer.Type)
 		if !cond.Reducer.ValidReduceFunc() {
+			if mathexp.LooksLikePercentile(string(cond.Reducer)) {
+				return nil, fmt.Errorf("invalid reducer '%v' in condition %v, %s", cond.Reducer, i+1, mathexp.PercentileHint)
+			}
 			return nil, fmt.Errorf("invalid reducer '%v' in condition %v", cond.Reducer, i+1)
 		}
 
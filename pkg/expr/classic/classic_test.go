@@ -708,3 +708,32 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalConditionsCmdInvalidReducer(t *testing.T) {
+	unmarshal := func(reducer string) error {
+		rq := map[string]any{
+			"conditions": []any{
+				map[string]any{
+					"evaluator": map[string]any{"params": []any{2}, "type": "gt"},
+					"operator":  map[string]any{"type": "and"},
+					"query":     map[string]any{"params": []any{"A"}},
+					"reducer":   map[string]any{"params": []any{}, "type": reducer},
+				},
+			},
+		}
+		_, err := UnmarshalConditionsCmd(rq, "")
+		return err
+	}
+
+	t.Run("should explain how percentiles are named", func(t *testing.T) {
+		err := unmarshal("p101")
+		require.ErrorContains(t, err, "invalid reducer 'p101' in condition 1")
+		require.ErrorContains(t, err, "percentiles are named pN")
+	})
+
+	t.Run("should not mention percentiles for other reducers", func(t *testing.T) {
+		err := unmarshal("average")
+		require.ErrorContains(t, err, "invalid reducer 'average' in condition 1")
+		require.NotContains(t, err.Error(), "percentiles")
+	})
+}
@@ -15,8 +15,11 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "variance", "range", "rate", "increase":
+		return true
 	}
-	return false
+	_, ok := mathexp.ParsePercentile(string(cr))
+	return ok
 }
 
 //nolint:gocyclo
@@ -144,6 +147,22 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	default:
+		// the remaining reducers are shared with the Reduce expression and ignore null and NaN values
+		reduceFunc, err := mathexp.GetSeriesReduceFunc(string(cr))
+		if err != nil {
+			return num
+		}
+		nonNull := dropNilOrNaN(series)
+		if nonNull.Len() == 0 {
+			return num
+		}
+		f := reduceFunc(nonNull)
+		if nilOrNaN(f) {
+			return num
+		}
+		value = *f
+		allNull = false
 	}
 
 	if allNull {
@@ -182,6 +201,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// dropNilOrNaN returns a copy of the series without the points that are either null or NaN.
+func dropNilOrNaN(series mathexp.Series) mathexp.Series {
+	result := mathexp.NewSeries("", nil, 0)
+	for i := 0; i < series.Len(); i++ {
+		f := series.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		result.AppendPoint(series.GetTime(i), f)
+	}
+	return result
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
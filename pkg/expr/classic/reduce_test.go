@@ -115,6 +115,60 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN()), util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "p75 should ignore null values",
+			reducer:        reducer("p75"),
+			inputSeries:    newSeries(nil, util.Pointer(1.0), util.Pointer(2.0), util.Pointer(3.0), util.Pointer(4.0), util.Pointer(5.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "variance should ignore null values",
+			reducer:        reducer("variance"),
+			inputSeries:    newSeries(util.Pointer(1.0), nil, util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "increase with counter reset",
+			reducer:        reducer("increase"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(5.0), util.Pointer(2.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(8.0)),
+		},
+		{
+			name:           "rate should ignore null values",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(util.Pointer(1.0), nil, util.Pointer(5.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "rate with a single value",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(nil, util.Pointer(1.0)),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "stddev with only nulls",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
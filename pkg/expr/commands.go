@@ -85,7 +85,7 @@ type ReduceCommand struct {
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+	_, err := mathexp.GetSeriesReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +200,16 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 	return newRes, nil
 }
 
+// ResampleAlignment defines where the buckets of a ResampleCommand start.
+type ResampleAlignment string
+
+const (
+	// ResampleAlignFrom aligns buckets to the start of the time range.
+	ResampleAlignFrom ResampleAlignment = "from"
+	// ResampleAlignCalendar aligns buckets to calendar boundaries, e.g. the start of an hour or a day, in the command's location.
+	ResampleAlignCalendar ResampleAlignment = "calendar"
+)
+
 // ResampleCommand is an expression command for resampling of a timeseries.
 type ResampleCommand struct {
 	Window        time.Duration
@@ -207,7 +217,10 @@ type ResampleCommand struct {
 	Downsampler   string
 	Upsampler     string
 	TimeRange     TimeRange
-	refID         string
+	Alignment     ResampleAlignment
+	// Location is the time zone used to find calendar boundaries when Alignment is ResampleAlignCalendar.
+	Location *time.Location
+	refID    string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
@@ -223,6 +236,8 @@ func NewResampleCommand(refID, rawWindow, varToResample string, downsampler stri
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
 		TimeRange:     tr,
+		Alignment:     ResampleAlignFrom,
+		Location:      time.UTC,
 		refID:         refID,
 	}, nil
 }
@@ -270,7 +285,40 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
-	return NewResampleCommand(rn.RefID, window, varToResample, downsampler, upsampler, rn.TimeRange)
+	cmd, err := NewResampleCommand(rn.RefID, window, varToResample, downsampler, upsampler, rn.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if rawAlignment, ok := rn.Query["alignment"]; ok {
+		alignment, ok := rawAlignment.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample alignment to be a string, got type %T", rawAlignment)
+		}
+		switch ResampleAlignment(alignment) {
+		case "", ResampleAlignFrom:
+		case ResampleAlignCalendar:
+			cmd.Alignment = ResampleAlignCalendar
+		default:
+			return nil, fmt.Errorf("resample alignment %q is not supported, must be one of %q or %q", alignment, ResampleAlignFrom, ResampleAlignCalendar)
+		}
+	}
+
+	if rawTimezone, ok := rn.Query["timezone"]; ok {
+		timezone, ok := rawTimezone.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample timezone to be a string, got type %T", rawTimezone)
+		}
+		if timezone != "" {
+			loc, err := time.LoadLocation(timezone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse resample timezone %q: %w", timezone, err)
+			}
+			cmd.Location = loc
+		}
+	}
+
+	return cmd, nil
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -286,6 +334,13 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 	defer span.End()
 	newRes := mathexp.Results{}
 	timeRange := gr.TimeRange.AbsoluteTime(now)
+	if gr.Alignment == ResampleAlignCalendar {
+		loc := gr.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		timeRange.From = mathexp.AlignToCalendar(timeRange.From, gr.Window, loc)
+	}
 	for _, val := range vars[gr.VarToResample].Values {
 		if val == nil {
 			continue
@@ -323,6 +378,10 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeSQL is the CMDType for running a SQL statement over the results of other queries and expressions.
+	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies in time series.
+	TypeAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -335,6 +394,12 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
+	case TypeSQL:
+		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
 	default:
 		return "unknown"
 	}
@@ -353,6 +418,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "sql":
+		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -273,3 +273,62 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func Test_UnmarshalResampleCommand_Alignment(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name              string
+		querySettings     string
+		isError           bool
+		expectedAlignment ResampleAlignment
+		expectedLocation  *time.Location
+	}{
+		{
+			name:              "aligned to from in UTC by default",
+			querySettings:     ``,
+			expectedAlignment: ResampleAlignFrom,
+			expectedLocation:  time.UTC,
+		},
+		{
+			name:              "aligned to calendar with timezone",
+			querySettings:     `, "alignment": "calendar", "timezone": "Europe/Berlin"`,
+			expectedAlignment: ResampleAlignCalendar,
+			expectedLocation:  berlin,
+		},
+		{
+			name:          "error when alignment is not known",
+			querySettings: `, "alignment": "week"`,
+			isError:       true,
+		},
+		{
+			name:          "error when timezone is not known",
+			querySettings: `, "alignment": "calendar", "timezone": "Mars/Olympus_Mons"`,
+			isError:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := fmt.Sprintf(`{ "expression" : "$A", "window": "1h", "downsampler": "mean", "upsampler": "fillna"%s }`, test.querySettings)
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+			cmd, err := UnmarshalResampleCommand(&rawNode{
+				RefID:     "B",
+				Query:     qmap,
+				TimeRange: RelativeTimeRange{From: -time.Hour},
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAlignment, cmd.Alignment)
+			require.Equal(t, test.expectedLocation.String(), cmd.Location.String())
+		})
+	}
+}
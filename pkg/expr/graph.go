@@ -111,7 +111,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
+		recordNodeStats(c, node, time.Since(start))
 		if err != nil {
 			res.Error = err
 		}
@@ -263,6 +265,10 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 
 		cmdNode := node.(*CMDNode)
 
+		if sqlCmd, ok := cmdNode.Command.(*SQLCommand); ok {
+			sqlCmd.resolveTables(registry)
+		}
+
 		for _, neededVar := range cmdNode.Command.NeedsVars() {
 			neededNode, ok := registry[neededVar]
 			if !ok {
@@ -279,6 +285,14 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 				}
 			}
 
+			if dsNode, ok := neededNode.(*DSNode); ok {
+				if cmdNode.CMDType == TypeSQL {
+					dsNode.isInputToSQLExpr = true
+				} else {
+					dsNode.isInputToOtherExpr = true
+				}
+			}
+
 			if neededNode.NodeType() == TypeCMDNode {
 				if neededNode.(*CMDNode).CMDType == TypeClassicConditions {
 					return fmt.Errorf("classic conditions may not be the input for other expressions, but %v is the input for %v", neededVar, cmdNode.RefID())
@@ -0,0 +1,266 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// AnomalyRollingStdDev bounds each point by the mean ± sensitivity·stddev of the points in the preceding window.
+	AnomalyRollingStdDev = "rolling_stddev"
+	// AnomalyMAD bounds each point by the median ± sensitivity·MAD of the points in the preceding window,
+	// where MAD is the median absolute deviation scaled to be comparable to the standard deviation.
+	AnomalyMAD = "mad"
+	// AnomalySeasonal decomposes the points preceding each point into trend, seasonality with the given period and
+	// residual, and bounds the point by trend + seasonality ± sensitivity·stddev of the residuals.
+	AnomalySeasonal = "seasonal"
+)
+
+// madScale makes the median absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+const madScale = 1.4826
+
+// AnomalyOptions configures DetectAnomalies.
+type AnomalyOptions struct {
+	Algorithm string
+	// Window is the duration of the window preceding each point that is used by the rolling algorithms.
+	Window time.Duration
+	// Period is the length of a season used by the seasonal algorithm.
+	Period time.Duration
+	// Sensitivity is the number of standard deviations the band extends from the expected value.
+	Sensitivity float64
+}
+
+// Validate checks that the options are complete for the algorithm.
+func (o AnomalyOptions) Validate() error {
+	switch o.Algorithm {
+	case AnomalyRollingStdDev, AnomalyMAD:
+		if o.Window <= 0 {
+			return fmt.Errorf("window must be greater than 0 for the %s algorithm", o.Algorithm)
+		}
+	case AnomalySeasonal:
+		if o.Period <= 0 {
+			return fmt.Errorf("period must be greater than 0 for the %s algorithm", o.Algorithm)
+		}
+	default:
+		return fmt.Errorf("anomaly detection algorithm %q is not supported, must be one of [%s, %s, %s]", o.Algorithm, AnomalyRollingStdDev, AnomalyMAD, AnomalySeasonal)
+	}
+	if o.Sensitivity <= 0 || math.IsNaN(o.Sensitivity) || math.IsInf(o.Sensitivity, 0) {
+		return fmt.Errorf("sensitivity must be a number greater than 0")
+	}
+	return nil
+}
+
+// AnomalyBandLabel is the label that tells the lower and upper bounds of the band returned by DetectAnomalies apart.
+const AnomalyBandLabel = "band"
+
+// AnomalyBands are the lower and upper bounds of the expected band of each point of a series returned by
+// DetectAnomalies. Their labels are the labels of the series with AnomalyBandLabel set to "lower" and "upper".
+type AnomalyBands struct {
+	Lower Series
+	Upper Series
+}
+
+// DetectAnomalies returns a series that is 1 at the points that are outside the expected band, 0 at the points
+// inside of it, and null where there is no value or not enough data to calculate the band, along with the bounds
+// of the band. The band of a point only depends on the points that precede it. Points are expected to be sorted
+// by time in ascending order.
+func (s Series) DetectAnomalies(refID string, opts AnomalyOptions) (Series, AnomalyBands, error) {
+	if err := opts.Validate(); err != nil {
+		return s, AnomalyBands{}, err
+	}
+
+	var lower, upper []*float64
+	switch opts.Algorithm {
+	case AnomalyRollingStdDev:
+		lower, upper = s.rollingBands(opts.Window, opts.Sensitivity, meanStdDev)
+	case AnomalyMAD:
+		lower, upper = s.rollingBands(opts.Window, opts.Sensitivity, medianMAD)
+	case AnomalySeasonal:
+		lower, upper = s.seasonalBands(opts.Period, opts.Sensitivity)
+	}
+
+	labels := s.GetLabels()
+	result := NewSeries(refID, labels, s.Len())
+	bands := AnomalyBands{
+		Lower: NewSeries(refID, bandLabels(labels, "lower"), s.Len()),
+		Upper: NewSeries(refID, bandLabels(labels, "upper"), s.Len()),
+	}
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		var anomalous *float64
+		if v != nil && !math.IsNaN(*v) && lower[i] != nil && upper[i] != nil {
+			f := 0.0
+			if *v < *lower[i] || *v > *upper[i] {
+				f = 1
+			}
+			anomalous = &f
+		}
+		result.SetPoint(i, t, anomalous)
+		bands.Lower.SetPoint(i, t, lower[i])
+		bands.Upper.SetPoint(i, t, upper[i])
+	}
+	return result, bands, nil
+}
+
+func bandLabels(labels data.Labels, band string) data.Labels {
+	l := labels.Copy()
+	l[AnomalyBandLabel] = band
+	return l
+}
+
+// rollingBands calculates the band of each point from the valid values in the window that precedes it.
+// The point itself is not part of its window, so that an outlier cannot widen its own band.
+func (s Series) rollingBands(window time.Duration, k float64, center func([]float64) (float64, float64, bool)) ([]*float64, []*float64) {
+	lower := make([]*float64, s.Len())
+	upper := make([]*float64, s.Len())
+	start := 0
+	for i := 0; i < s.Len(); i++ {
+		t := s.GetTime(i)
+		for start < i && !s.GetTime(start).After(t.Add(-window)) {
+			start++
+		}
+		vals := make([]float64, 0, i-start)
+		for j := start; j < i; j++ {
+			if v := s.GetValue(j); v != nil && !math.IsNaN(*v) {
+				vals = append(vals, *v)
+			}
+		}
+		c, spread, ok := center(vals)
+		if !ok {
+			continue
+		}
+		l, u := c-k*spread, c+k*spread
+		lower[i], upper[i] = &l, &u
+	}
+	return lower, upper
+}
+
+// seasonalBands performs an additive decomposition of the points that precede each point into a trend, calculated
+// as the average of the values in the period before the point, a seasonal component, calculated as the average
+// difference to their trend of the earlier points at the same position within the period, and the residual. The band
+// is the trend plus the seasonal component ± k times the standard deviation of the earlier residuals. Points get a
+// band once at least two periods of data precede them.
+func (s Series) seasonalBands(period time.Duration, k float64) ([]*float64, []*float64) {
+	n := s.Len()
+	lower := make([]*float64, n)
+	upper := make([]*float64, n)
+
+	step := s.medianStep()
+	if step <= 0 {
+		return lower, upper
+	}
+	seasonLength := int(math.Round(float64(period) / float64(step)))
+	if seasonLength < 2 {
+		return lower, upper
+	}
+	phase := func(i int) int {
+		return int(math.Round(float64(s.GetTime(i).Sub(s.GetTime(0)))/float64(step))) % seasonLength
+	}
+	valid := func(v *float64) bool {
+		return v != nil && !math.IsNaN(*v)
+	}
+
+	seasonSums := make([]float64, seasonLength)
+	seasonCounts := make([]int, seasonLength)
+	var trendSum, residualSum, residualSquares float64
+	var trendCount, residualCount int
+	start := 0
+	for i := 0; i < n; i++ {
+		t, v := s.GetPoint(i)
+		for start < i && s.GetTime(start).Before(t.Add(-period)) {
+			if w := s.GetValue(start); valid(w) {
+				trendSum -= *w
+				trendCount--
+			}
+			start++
+		}
+
+		// the trend is only calculated over a whole period
+		if trendCount > 0 && !t.Before(s.GetTime(0).Add(period)) {
+			trend := trendSum / float64(trendCount)
+			p := phase(i)
+			if seasonCounts[p] > 0 {
+				expected := trend + seasonSums[p]/float64(seasonCounts[p])
+				if residualCount >= 2 {
+					mean := residualSum / float64(residualCount)
+					stdDev := math.Sqrt(math.Max(residualSquares/float64(residualCount)-mean*mean, 0))
+					l, u := expected-k*stdDev, expected+k*stdDev
+					lower[i], upper[i] = &l, &u
+				}
+				if valid(v) {
+					residual := *v - expected
+					residualSum += residual
+					residualSquares += residual * residual
+					residualCount++
+				}
+			}
+			if valid(v) {
+				seasonSums[p] += *v - trend
+				seasonCounts[p]++
+			}
+		}
+
+		if valid(v) {
+			trendSum += *v
+			trendCount++
+		}
+	}
+	return lower, upper
+}
+
+// medianStep returns the median duration between two consecutive points.
+func (s Series) medianStep() time.Duration {
+	if s.Len() < 2 {
+		return 0
+	}
+	steps := make([]time.Duration, 0, s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		steps = append(steps, s.GetTime(i).Sub(s.GetTime(i-1)))
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i] < steps[j] })
+	return steps[len(steps)/2]
+}
+
+// meanStdDev returns the mean and the population standard deviation of the values. At least two values are required.
+func meanStdDev(vals []float64) (float64, float64, bool) {
+	if len(vals) < 2 {
+		return 0, 0, false
+	}
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+	var sq float64
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(vals))), true
+}
+
+// medianMAD returns the median and the scaled median absolute deviation of the values. At least two values are required.
+func medianMAD(vals []float64) (float64, float64, bool) {
+	if len(vals) < 2 {
+		return 0, 0, false
+	}
+	median := medianOf(vals)
+	deviations := make([]float64, len(vals))
+	for i, v := range vals {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, madScale * medianOf(deviations), true
+}
+
+func medianOf(vals []float64) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	l := len(sorted)
+	if l%2 == 1 {
+		return sorted[l/2]
+	}
+	return (sorted[l/2-1] + sorted[l/2]) / 2
+}
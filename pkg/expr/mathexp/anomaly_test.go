@@ -0,0 +1,145 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesOf(step time.Duration, vals ...*float64) Series {
+	s := NewSeries("A", nil, len(vals))
+	for i, v := range vals {
+		s.SetPoint(i, time.Unix(0, 0).Add(time.Duration(i)*step), v)
+	}
+	return s
+}
+
+func anomalies(t *testing.T, s Series) []*float64 {
+	t.Helper()
+	res := make([]*float64, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		res[i] = s.GetValue(i)
+	}
+	return res
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	t.Run("rolling stddev", func(t *testing.T) {
+		s := seriesOf(time.Minute,
+			float64Pointer(10), float64Pointer(11), float64Pointer(9), float64Pointer(10),
+			float64Pointer(11), float64Pointer(30), float64Pointer(10), nil,
+		)
+		s.SetLabels(data.Labels{"host": "a"})
+		res, bands, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalyRollingStdDev, Window: 5 * time.Minute, Sensitivity: 3})
+		require.NoError(t, err)
+
+		require.Equal(t, []*float64{
+			nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(1), float64Pointer(0), nil,
+		}, anomalies(t, res))
+
+		require.Equal(t, data.Labels{"host": "a"}, res.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", "band": "lower"}, bands.Lower.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", "band": "upper"}, bands.Upper.GetLabels())
+		require.Equal(t, s.Len(), bands.Lower.Len())
+		require.Equal(t, s.Len(), bands.Upper.Len())
+		require.Equal(t, "B", bands.Lower.Frame.RefID)
+		require.Equal(t, s.GetTime(2), bands.Lower.GetTime(2))
+		require.Nil(t, bands.Lower.GetValue(1))
+		// the band at index 2 is based on the two preceding points, 10 and 11
+		require.InDelta(t, 9, *bands.Lower.GetValue(2), 1e-9)
+		require.InDelta(t, 12, *bands.Upper.GetValue(2), 1e-9)
+	})
+
+	t.Run("median absolute deviation is robust to earlier outliers", func(t *testing.T) {
+		s := seriesOf(time.Minute,
+			float64Pointer(10), float64Pointer(12), float64Pointer(100), float64Pointer(11),
+			float64Pointer(10), float64Pointer(12), float64Pointer(11), float64Pointer(40),
+		)
+		res, _, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalyMAD, Window: 10 * time.Minute, Sensitivity: 3})
+		require.NoError(t, err)
+		got := anomalies(t, res)
+		require.Equal(t, float64Pointer(1), got[2])
+		require.Equal(t, float64Pointer(0), got[3])
+		require.Equal(t, float64Pointer(1), got[7])
+	})
+
+	t.Run("seasonal", func(t *testing.T) {
+		// a daily pattern sampled every 6 hours over 4 days, with a spike on the last day
+		pattern := []float64{10, 20, 30, 20}
+		vals := make([]*float64, 0, 16)
+		for day := 0; day < 4; day++ {
+			for _, v := range pattern {
+				vals = append(vals, float64Pointer(v))
+			}
+		}
+		vals[13] = float64Pointer(45)
+		s := seriesOf(6*time.Hour, vals...)
+
+		res, _, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalySeasonal, Period: 24 * time.Hour, Sensitivity: 2})
+		require.NoError(t, err)
+		got := anomalies(t, res)
+		for i, v := range got {
+			// bands require two periods of data, and two residuals of the third one
+			if i < 10 {
+				require.Nil(t, v, "point %d", i)
+				continue
+			}
+			require.NotNil(t, v, "point %d", i)
+			if i == 13 {
+				require.Equal(t, 1.0, *v, "point %d", i)
+			} else {
+				require.Equal(t, 0.0, *v, "point %d", i)
+			}
+		}
+	})
+
+	t.Run("bands only depend on the preceding points", func(t *testing.T) {
+		vals := make([]*float64, 0, 48)
+		for i := 0; i < 48; i++ {
+			vals = append(vals, float64Pointer(math.Sin(float64(i)*math.Pi/6)*10+float64(i%5)))
+		}
+		s := seriesOf(time.Hour, vals...)
+		prefix := seriesOf(time.Hour, vals[:36]...)
+
+		for _, opts := range []AnomalyOptions{
+			{Algorithm: AnomalyRollingStdDev, Window: 6 * time.Hour, Sensitivity: 3},
+			{Algorithm: AnomalyMAD, Window: 6 * time.Hour, Sensitivity: 3},
+			{Algorithm: AnomalySeasonal, Period: 12 * time.Hour, Sensitivity: 3},
+		} {
+			_, bands, err := s.DetectAnomalies("B", opts)
+			require.NoError(t, err)
+			_, prefixBands, err := prefix.DetectAnomalies("B", opts)
+			require.NoError(t, err)
+
+			require.NotNil(t, prefixBands.Lower.GetValue(35), opts.Algorithm)
+			for i := 0; i < prefix.Len(); i++ {
+				require.Equal(t, prefixBands.Lower.GetValue(i), bands.Lower.GetValue(i), opts.Algorithm)
+				require.Equal(t, prefixBands.Upper.GetValue(i), bands.Upper.GetValue(i), opts.Algorithm)
+			}
+		}
+	})
+
+	t.Run("seasonal requires two periods", func(t *testing.T) {
+		s := seriesOf(time.Hour, float64Pointer(1), float64Pointer(2), float64Pointer(3))
+		res, _, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalySeasonal, Period: 24 * time.Hour, Sensitivity: 3})
+		require.NoError(t, err)
+		require.Equal(t, []*float64{nil, nil, nil}, anomalies(t, res))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		s := seriesOf(time.Minute, float64Pointer(1))
+		for _, opts := range []AnomalyOptions{
+			{Algorithm: "prophet", Window: time.Minute, Sensitivity: 3},
+			{Algorithm: AnomalyRollingStdDev, Sensitivity: 3},
+			{Algorithm: AnomalySeasonal, Window: time.Minute, Sensitivity: 3},
+			{Algorithm: AnomalyMAD, Window: time.Minute, Sensitivity: 0},
+			{Algorithm: AnomalyMAD, Window: time.Minute, Sensitivity: math.NaN()},
+		} {
+			_, _, err := s.DetectAnomalies("B", opts)
+			require.Error(t, err)
+		}
+	})
+}
@@ -214,28 +214,8 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	aMatched := make([]bool, len(aResults.Values))
 	bMatched := make([]bool, len(bResults.Values))
 	collectDrops := func() {
-		check := func(v string, matchArray []bool, r *Results) {
-			for i, b := range matchArray {
-				if b {
-					continue
-				}
-				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
-				}
-				if e.Drops[biNode.String()] == nil {
-					e.Drops[biNode.String()] = make(map[string][]data.Labels)
-				}
-
-				if r.Values[i].Type() == parse.TypeNoData {
-					continue
-				}
-
-				e.DropCount++
-				e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
-			}
-		}
-		check(aVar, aMatched, &aResults)
-		check(bVar, bMatched, &bResults)
+		e.collectDrops(biNode, aVar, aMatched, &aResults)
+		e.collectDrops(biNode, bVar, bMatched, &bResults)
 	}
 
 	aValueLen := len(aResults.Values)
@@ -306,6 +286,159 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	return unions
 }
 
+// collectDrops records the values of one side of a binary operation that did not match any value on the other side.
+func (e *State) collectDrops(biNode *parse.BinaryNode, v string, matched []bool, r *Results) {
+	for i, b := range matched {
+		if b {
+			continue
+		}
+		if e.Drops == nil {
+			e.Drops = make(map[string]map[string][]data.Labels)
+		}
+		if e.Drops[biNode.String()] == nil {
+			e.Drops[biNode.String()] = make(map[string][]data.Labels)
+		}
+
+		if r.Values[i].Type() == parse.TypeNoData {
+			continue
+		}
+
+		e.DropCount++
+		e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
+	}
+}
+
+// matchUnion creates Union objects by matching the labels of the Series or Numbers on both sides of a binary
+// operation as described by its vector matching, e.g. on(host) or ignoring(instance) group_left(team).
+// Unlike union, it returns an error when the matching is ambiguous.
+func (e *State) matchUnion(aResults, bResults Results, biNode *parse.BinaryNode) ([]*Union, error) {
+	m := biNode.Matching
+	unions := []*Union{}
+
+	aVar := biNode.Args[0].String()
+	bVar := biNode.Args[1].String()
+
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions, nil
+	}
+
+	aMatched := make([]bool, len(aResults.Values))
+	bMatched := make([]bool, len(bResults.Values))
+
+	if len(aResults.Values) == 1 || len(bResults.Values) == 1 {
+		aNoData := aResults.Values[0].Type() == parse.TypeNoData
+		bNoData := bResults.Values[0].Type() == parse.TypeNoData
+		if aNoData || bNoData {
+			unions = append(unions, &Union{
+				A: aResults.Values[0],
+				B: bResults.Values[0],
+			})
+			e.collectDrops(biNode, aVar, aMatched, &aResults)
+			e.collectDrops(biNode, bVar, bMatched, &bResults)
+			return unions, nil
+		}
+	}
+
+	signatures := func(side string, r Results, unique bool) ([]string, error) {
+		sigs := make([]string, len(r.Values))
+		seen := make(map[string]bool, len(r.Values))
+		for i, v := range r.Values {
+			switch v.Type() {
+			case parse.TypeSeriesSet, parse.TypeNumberSet:
+			default:
+				return nil, fmt.Errorf("%s is only supported between series and numbers, got %s on the %s side", m, v.Type(), side)
+			}
+			sigs[i] = matchingLabels(v.GetLabels(), m).String()
+			if unique && seen[sigs[i]] {
+				return nil, fmt.Errorf("found more than one value with the labels {%s} on the %s side of %s, "+
+					"matching labels must be unique on the %s side or the operation must use group_left or group_right", sigs[i], side, biNode, side)
+			}
+			seen[sigs[i]] = true
+		}
+		return sigs, nil
+	}
+	aSigs, err := signatures("left", aResults, m.Card != parse.CardManyToOne)
+	if err != nil {
+		return nil, err
+	}
+	bSigs, err := signatures("right", bResults, m.Card != parse.CardOneToMany)
+	if err != nil {
+		return nil, err
+	}
+
+	resultLabels := map[string]bool{}
+	for iA, a := range aResults.Values {
+		for iB, b := range bResults.Values {
+			if aSigs[iA] != bSigs[iB] {
+				continue
+			}
+			var labels data.Labels
+			switch m.Card {
+			case parse.CardManyToOne:
+				labels = includeLabels(a.GetLabels(), b.GetLabels(), m.Include)
+			case parse.CardOneToMany:
+				labels = includeLabels(b.GetLabels(), a.GetLabels(), m.Include)
+			default:
+				labels = matchingLabels(a.GetLabels(), m)
+			}
+			key := labels.String()
+			if resultLabels[key] {
+				return nil, fmt.Errorf("more than one result of %s has the labels {%s}, the matching labels must make results unique", biNode, key)
+			}
+			resultLabels[key] = true
+			unions = append(unions, &Union{
+				Labels: labels,
+				A:      a,
+				B:      b,
+			})
+			aMatched[iA] = true
+			bMatched[iB] = true
+		}
+	}
+
+	e.collectDrops(biNode, aVar, aMatched, &aResults)
+	e.collectDrops(biNode, bVar, bMatched, &bResults)
+	return unions, nil
+}
+
+// matchingLabels returns the labels that values are matched by: only the labels listed in on(...),
+// or all labels except the ones listed in ignoring(...).
+func matchingLabels(labels data.Labels, m *parse.VectorMatching) data.Labels {
+	result := data.Labels{}
+	if m.On {
+		for _, name := range m.MatchingLabels {
+			if v, ok := labels[name]; ok {
+				result[name] = v
+			}
+		}
+		return result
+	}
+	for k, v := range labels {
+		result[k] = v
+	}
+	for _, name := range m.MatchingLabels {
+		delete(result, name)
+	}
+	return result
+}
+
+// includeLabels returns the labels of the "many" side of a match with the labels listed in group_left(...) or
+// group_right(...) copied from the "one" side. Listed labels that the "one" side does not have are removed.
+func includeLabels(many, one data.Labels, include []string) data.Labels {
+	result := many.Copy()
+	if result == nil {
+		result = data.Labels{}
+	}
+	for _, name := range include {
+		if v, ok := one[name]; ok {
+			result[name] = v
+		} else {
+			delete(result, name)
+		}
+	}
+	return result
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values: Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -316,7 +449,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := e.union(ar, br, node)
+	var unions []*Union
+	if node.Matching != nil {
+		unions, err = e.matchUnion(ar, br, node)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = e.union(ar, br, node)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
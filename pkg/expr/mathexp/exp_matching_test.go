@@ -0,0 +1,172 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestVectorMatchingParse(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+		isError  bool
+	}{
+		{expr: "$A + on(host) $B", expected: "$A + on(host) $B"},
+		{expr: "$A+ignoring(instance, job)$B", expected: "$A + ignoring(instance, job) $B"},
+		{expr: `$A / on("k8s.pod", host) group_left $B`, expected: "$A / on(k8s.pod, host) group_left() $B"},
+		{expr: "$A > on() group_right(team, env2) $B * 2", expected: "$A > on() group_right(team, env2) $B * 2"},
+		{expr: "$A + on(host) ($B + 1)", expected: "$A + on(host) $B + 1"},
+		{expr: "$A + on host $B", isError: true},
+		{expr: "$A + on(host,) $B", isError: true},
+		{expr: "$A + on(host) group_left(team $B", isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := New(tt.expr)
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, e.Tree.String())
+		})
+	}
+}
+
+func TestVectorMatchingExecute(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("A", data.Labels{"instance": "a", "job": "node"}, float64Pointer(1)),
+			makeNumber("A", data.Labels{"instance": "b", "job": "node"}, float64Pointer(2)),
+			makeNumber("A", data.Labels{"instance": "c", "job": "node"}, float64Pointer(3)),
+		),
+		"B": resultValuesNoErr(
+			makeNumber("B", data.Labels{"host": "a", "team": "x"}, float64Pointer(10)),
+			makeNumber("B", data.Labels{"host": "b", "team": "y"}, float64Pointer(20)),
+		),
+		"C": resultValuesNoErr(
+			makeNumber("C", data.Labels{"instance": "a", "job": "node", "cpu": "0"}, float64Pointer(100)),
+			makeNumber("C", data.Labels{"instance": "a", "job": "node", "cpu": "1"}, float64Pointer(200)),
+		),
+		"S": resultValuesNoErr(
+			makeSeries("S", data.Labels{"instance": "a", "job": "node", "cpu": "0"}, tp{time.Unix(5, 0), float64Pointer(4)}),
+		),
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected Results
+		isError  bool
+	}{
+		{
+			name: "label_replace and on()",
+			expr: `label_replace($A, "host", "$1", "instance", "(.*)") + on(host) $B`,
+			expected: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(11)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(22)),
+			),
+		},
+		{
+			name: "group_left copies labels from the right",
+			expr: `$C * on(instance) group_left(team) label_replace($B, "instance", "$1", "host", "(.*)")`,
+			expected: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a", "job": "node", "cpu": "0", "team": "x"}, float64Pointer(1000)),
+				makeNumber("", data.Labels{"instance": "a", "job": "node", "cpu": "1", "team": "x"}, float64Pointer(2000)),
+			),
+		},
+		{
+			name: "group_right with ignoring",
+			expr: `$A + ignoring(cpu) group_right $C`,
+			expected: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a", "job": "node", "cpu": "0"}, float64Pointer(101)),
+				makeNumber("", data.Labels{"instance": "a", "job": "node", "cpu": "1"}, float64Pointer(201)),
+			),
+		},
+		{
+			name: "series and number",
+			expr: `$S - ignoring(cpu) $A`,
+			expected: resultValuesNoErr(
+				makeSeries("", data.Labels{"instance": "a", "job": "node"}, tp{time.Unix(5, 0), float64Pointer(3)}),
+			),
+		},
+		{
+			name:    "many-to-one without group_left",
+			expr:    `$C + on(instance) $A`,
+			isError: true,
+		},
+		{
+			name:    "many-to-many",
+			expr:    `$C + on(job) group_left $A`,
+			isError: true,
+		},
+		{
+			name:    "scalars are not matched",
+			expr:    `$A + on(instance) 1`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// unmatched values are reported as notices, which are not compared
+			for _, v := range res.Values {
+				v.AsDataFrame().Meta.Notices = nil
+			}
+			require.ElementsMatch(t, tt.expected.Values, res.Values)
+		})
+	}
+}
+
+func TestLabelReplace(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("A", data.Labels{"instance": "host-1:9100"}, float64Pointer(1)),
+			makeNumber("A", data.Labels{"instance": "other"}, float64Pointer(2)),
+		),
+	}
+
+	t.Run("replaces with capture groups", func(t *testing.T) {
+		e, err := New(`label_replace($A, "host", "${name}", "instance", "(?P<name>[^:]+):.*")`)
+		require.NoError(t, err)
+		res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "host-1:9100", "host": "host-1"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"instance": "other"}, float64Pointer(2)),
+		), res)
+	})
+
+	t.Run("empty replacement removes the label", func(t *testing.T) {
+		e, err := New(`label_replace($A, "instance", "", "instance", "host-.*")`)
+		require.NoError(t, err)
+		res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{}, res.Values[0].GetLabels())
+	})
+
+	t.Run("error when labels are not unique", func(t *testing.T) {
+		e, err := New(`label_replace($A, "instance", "", "instance", ".*")`)
+		require.NoError(t, err)
+		_, err = e.Execute("", vars, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+
+	t.Run("invalid regular expression", func(t *testing.T) {
+		_, err := New(`label_replace($A, "host", "$1", "instance", "(.*")`)
+		require.Error(t, err)
+	})
+}
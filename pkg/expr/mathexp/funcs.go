@@ -1,7 +1,14 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +75,54 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"clamp_min": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clampMin,
+	},
+	"clamp_max": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clampMax,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkDurationArg(1),
+	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      shift,
+		Check:  checkDurationArg(1),
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"timestamp": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      timestamp,
+	},
+	"label_replace": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString, parse.TypeString, parse.TypeString, parse.TypeString},
+		VariantReturn: true,
+		F:             labelReplace,
+		Check:         checkLabelReplace,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +362,310 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// clampMin returns the larger of each value and the given minimum for each result in NumberSet, SeriesSet, or Scalar
+func clampMin(e *State, varSet Results, minSet Results) (Results, error) {
+	minV, err := scalarArg(minSet)
+	if err != nil {
+		return Results{}, err
+	}
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			if math.IsNaN(f) {
+				return f
+			}
+			return math.Max(f, minV)
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// clampMax returns the smaller of each value and the given maximum for each result in NumberSet, SeriesSet, or Scalar
+func clampMax(e *State, varSet Results, maxSet Results) (Results, error) {
+	maxV, err := scalarArg(maxSet)
+	if err != nil {
+		return Results{}, err
+	}
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			if math.IsNaN(f) {
+				return f
+			}
+			return math.Min(f, maxV)
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// rate returns, for each point but the first, the per-second rate of increase since the previous point.
+// A decrease is treated as a counter reset. The result is null if either of the points is null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			prevT, prev := s.GetPoint(i - 1)
+			t, f := s.GetPoint(i)
+			seconds := t.Sub(prevT).Seconds()
+			if f == nil || prev == nil || seconds <= 0 {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			increase := *f - *prev
+			if *f < *prev {
+				increase = *f
+			}
+			nF := increase / seconds
+			newSeries.AppendPoint(t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// delta returns, for each point but the first, the difference to the previous point.
+// The result is null if either of the points is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			_, prev := s.GetPoint(i - 1)
+			t, f := s.GetPoint(i)
+			if f == nil || prev == nil {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			nF := *f - *prev
+			newSeries.AppendPoint(t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns for each point the average of the non-null and non-NaN values within the window that ends at that
+// point.
+// Points are expected to be sorted by time in ascending order.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := parseDurationArg(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg window must be greater than 0, got %s", rawWindow)
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		var count int
+		start := 0
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f != nil && !math.IsNaN(*f) {
+				sum += *f
+				count++
+			}
+			for ; !s.GetTime(start).After(t.Add(-window)); start++ {
+				if v := s.GetValue(start); v != nil && !math.IsNaN(*v) {
+					sum -= *v
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			nF := sum / float64(count)
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// shift moves each point of the series forward in time by the given duration, which can be negative.
+// This makes it possible to compare a series with the same series at an earlier time, e.g. shift($A, "1w").
+func shift(e *State, varSet Results, rawOffset string) (Results, error) {
+	offset, err := parseDurationArg(rawOffset)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(offset), f)
+		}
+		return newSeries
+	})
+}
+
+// cumsum returns the cumulative sum of the values of the series. Null values are skipped and stay null.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// timestamp returns for each point of the series its time as the number of seconds since the Unix epoch.
+func timestamp(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "timestamp", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t := s.GetTime(i)
+			nF := float64(t.UnixNano()) / float64(time.Second)
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// perSeries passes each Series of the results to seriesF. NoData is returned as is.
+// Any other type is an error because these functions operate on the timestamps of the points.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch res.Type() {
+		case parse.TypeSeriesSet:
+			newRes.Values = append(newRes.Values, seriesF(res.(Series)))
+		case parse.TypeNoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s expects time series, got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// labelReplace matches the regular expression against the value of the src label of each result. If it matches, the
+// dst label is set to the replacement, in which $1, $2 or ${name} refer to the capture groups of the expression.
+// The dst label is removed if the replacement is empty. Results that don't match keep their labels.
+func labelReplace(e *State, varSet Results, dst, replacement, src, rawRegex string) (Results, error) {
+	regex, err := compileAnchoredRegexp(rawRegex)
+	if err != nil {
+		return Results{}, err
+	}
+	newRes := Results{}
+	seen := map[string]bool{}
+	for _, res := range varSet.Values {
+		labels := res.GetLabels().Copy()
+		if labels == nil {
+			labels = data.Labels{}
+		}
+		if match := regex.FindStringSubmatchIndex(labels[src]); match != nil {
+			value := string(regex.ExpandString(nil, replacement, labels[src], match))
+			if value == "" {
+				delete(labels, dst)
+			} else {
+				labels[dst] = value
+			}
+		}
+
+		var newVal Value
+		switch res.Type() {
+		case parse.TypeNumberSet:
+			n := NewNumber(e.RefID, labels)
+			n.SetValue(res.(Number).GetFloat64Value())
+			newVal = n
+		case parse.TypeSeriesSet:
+			s := res.(Series)
+			newSeries := NewSeries(e.RefID, labels, s.Len())
+			for i := 0; i < s.Len(); i++ {
+				t, f := s.GetPoint(i)
+				newSeries.SetPoint(i, t, f)
+			}
+			newVal = newSeries
+		case parse.TypeScalar, parse.TypeNoData:
+			newRes.Values = append(newRes.Values, res)
+			continue
+		default:
+			return newRes, fmt.Errorf("label_replace expects series or numbers, got %v", res.Type())
+		}
+
+		key := labels.String()
+		if seen[key] {
+			return newRes, fmt.Errorf("label_replace results in more than one value with the labels {%s}", key)
+		}
+		seen[key] = true
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// checkLabelReplace checks the label names and the regular expression of label_replace at parse time.
+func checkLabelReplace(_ *parse.Tree, f *parse.FuncNode) error {
+	for idx, name := range []string{"destination label", "replacement", "source label", "regular expression"} {
+		if _, ok := f.Args[idx+1].(*parse.StringNode); !ok {
+			return fmt.Errorf("parse: expected a string for the %s of %s", name, f.Name)
+		}
+	}
+	if f.Args[1].(*parse.StringNode).Text == "" {
+		return fmt.Errorf("parse: the destination label of %s must not be empty", f.Name)
+	}
+	if _, err := compileAnchoredRegexp(f.Args[4].(*parse.StringNode).Text); err != nil {
+		return fmt.Errorf("parse: invalid regular expression in %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// compileAnchoredRegexp compiles a regular expression that has to match the whole value.
+func compileAnchoredRegexp(raw string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + raw + ")$")
+}
+
+// scalarArg returns the value of a scalar function argument.
+func scalarArg(res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("expected a scalar argument")
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return math.NaN(), nil
+	}
+	return *f, nil
+}
+
+// parseDurationArg parses a duration argument such as "5m" or "1w". A leading minus sign makes the duration negative.
+func parseDurationArg(raw string) (time.Duration, error) {
+	negative := strings.HasPrefix(raw, "-")
+	d, err := gtime.ParseDuration(strings.TrimPrefix(raw, "-"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration %q: %w", raw, err)
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// checkDurationArg returns a parse time check that the argument at the given index is a valid duration.
+func checkDurationArg(idx int) func(*parse.Tree, *parse.FuncNode) error {
+	return func(_ *parse.Tree, f *parse.FuncNode) error {
+		arg, ok := f.Args[idx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", idx, f.Name)
+		}
+		if _, err := parseDurationArg(arg.Text); err != nil {
+			return fmt.Errorf("parse: invalid argument %v of %s: %w", idx, f.Name, err)
+		}
+		return nil
+	}
+}
@@ -140,3 +140,171 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(5)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(4)},
+				tp{time.Unix(40, 0), float64Pointer(2)}),
+		),
+	}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "rate treats a decrease as a counter reset",
+			expr: "rate($A)",
+			vars: counter,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(0.4)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(0.2)}),
+			),
+		},
+		{
+			name: "delta",
+			expr: "delta($A)",
+			vars: counter,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(4)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(-2)}),
+			),
+		},
+		{
+			name: "moving_avg ignores null values",
+			expr: `moving_avg($A, "20s")`,
+			vars: counter,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(20, 0), float64Pointer(5)},
+					tp{time.Unix(30, 0), float64Pointer(4)},
+					tp{time.Unix(40, 0), float64Pointer(3)}),
+			),
+		},
+		{
+			name: "moving_avg ignores NaN values",
+			expr: `moving_avg($A, "20s")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("temp", nil,
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(10, 0), NaN},
+						tp{time.Unix(20, 0), float64Pointer(3)},
+						tp{time.Unix(30, 0), float64Pointer(5)}),
+				),
+			},
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(1)},
+					tp{time.Unix(20, 0), float64Pointer(3)},
+					tp{time.Unix(30, 0), float64Pointer(4)}),
+			),
+		},
+		{
+			name: "cumsum skips null values",
+			expr: "cumsum($A)",
+			vars: counter,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(6)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(10)},
+					tp{time.Unix(40, 0), float64Pointer(12)}),
+			),
+		},
+		{
+			name: "shift moves the points in time",
+			expr: `shift($A, "1w")`,
+			vars: aSeries,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(5, 0).Add(7 * 24 * time.Hour), float64Pointer(2)},
+					tp{time.Unix(10, 0).Add(7 * 24 * time.Hour), float64Pointer(1)}),
+			),
+		},
+		{
+			name: "shift with a negative duration",
+			expr: `shift($A, "-5s")`,
+			vars: aSeries,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(2)},
+					tp{time.Unix(5, 0), float64Pointer(1)}),
+			),
+		},
+		{
+			name: "timestamp",
+			expr: "timestamp($A)",
+			vars: aSeries,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(5, 0), float64Pointer(5)},
+					tp{time.Unix(10, 0), float64Pointer(10)}),
+			),
+		},
+		{
+			name: "clamp_min on series",
+			expr: "clamp_min($A, 1.5)",
+			vars: aSeries,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(5, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(1.5)}),
+			),
+		},
+		{
+			name: "clamp_max on number",
+			expr: "clamp_max($A, 5)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(6))),
+			},
+			results: resultValuesNoErr(makeNumber("", nil, float64Pointer(5))),
+		},
+		{
+			name:    "series functions return no data as is",
+			expr:    "rate($A)",
+			vars:    Vars{"A": resultValuesNoErr(NewNoData())},
+			results: resultValuesNoErr(NewNoData()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Equal(t, tt.results, res)
+		})
+	}
+
+	t.Run("series functions error on numbers", func(t *testing.T) {
+		e, err := New("cumsum($A)")
+		require.NoError(t, err)
+		_, err = e.Execute("", Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(6)))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+
+	t.Run("invalid duration fails to parse", func(t *testing.T) {
+		_, err := New(`shift($A, "1 week")`)
+		require.Error(t, err)
+		_, err = New(`moving_avg($A, 5)`)
+		require.Error(t, err)
+	})
+}
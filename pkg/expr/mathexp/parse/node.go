@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -300,6 +301,9 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching describes how the values of both arguments are matched by their labels.
+	// It is nil if the operation uses the default union of labels.
+	Matching *VectorMatching
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -308,11 +312,17 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
@@ -331,6 +341,46 @@ func (b *BinaryNode) Return() ReturnType {
 	return t0
 }
 
+// VectorMatchCardinality describes how many values on each side of a binary operation can match each other.
+type VectorMatchCardinality int
+
+const (
+	// CardOneToOne requires each value on either side to match at most one value on the other side.
+	CardOneToOne VectorMatchCardinality = iota
+	// CardManyToOne allows many values on the left side to match the same value on the right side (group_left).
+	CardManyToOne
+	// CardOneToMany allows many values on the right side to match the same value on the left side (group_right).
+	CardOneToMany
+)
+
+// VectorMatching describes how the values of a binary operation are matched by their labels,
+// e.g. on(host), ignoring(instance) or on(host) group_left(team).
+type VectorMatching struct {
+	Card VectorMatchCardinality
+	// On is true if values are matched by MatchingLabels only,
+	// and false if values are matched by all labels except MatchingLabels.
+	On             bool
+	MatchingLabels []string
+	// Include are the labels of the "one" side that are copied to the result of a many-to-one or one-to-many match.
+	Include []string
+}
+
+// String returns the modifiers of the binary operation as they are written in an expression.
+func (m *VectorMatching) String() string {
+	keyword := "ignoring"
+	if m.On {
+		keyword = "on"
+	}
+	s := fmt.Sprintf("%s(%s)", keyword, strings.Join(m.MatchingLabels, ", "))
+	switch m.Card {
+	case CardManyToOne:
+		s += fmt.Sprintf(" group_left(%s)", strings.Join(m.Include, ", "))
+	case CardOneToMany:
+		s += fmt.Sprintf(" group_right(%s)", strings.Join(m.Include, ", "))
+	}
+	return s
+}
+
 // UnaryNode holds one argument and an operator.
 type UnaryNode struct {
 	NodeType
@@ -405,6 +455,8 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeTableData is a table of arbitrary columns, such as the result of a SQL expression.
+	TypeTableData
 )
 
 // String returns a string representation of the ReturnType.
@@ -422,6 +474,8 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeTableData:
+		return "tableData"
 	default:
 		return "unknown"
 	}
@@ -165,16 +165,19 @@ func (t *Tree) parse() {
 }
 
 /* Grammar:
-O -> A {"||" A}
-A -> C {"&&" C}
-C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") P}
-P -> M {( "+" | "-" ) M}
-M -> E {( "*" | "/" ) F}
-E -> F {( "**" ) F}
+O -> A {"||" [matching] A}
+A -> C {"&&" [matching] C}
+C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") [matching] P}
+P -> M {( "+" | "-" ) [matching] M}
+M -> E {( "*" | "/" ) [matching] F}
+E -> F {( "**" ) [matching] F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
 param -> number | "string" | queryVar
+matching -> ( "on" | "ignoring" ) labels [( "group_left" | "group_right" ) [labels]]
+labels -> "(" [label {"," label}] ")"
+label -> name | "string"
 */
 
 // expr:
@@ -185,7 +188,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(t.next(), n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +201,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(t.next(), n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +214,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(t.next(), n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +227,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(t.next(), n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +240,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(t.next(), n, t.E)
 		default:
 			return n
 		}
@@ -250,13 +253,82 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(t.next(), n, t.F)
 		default:
 			return n
 		}
 	}
 }
 
+// binary creates a BinaryNode for the operator, parsing the optional vector matching that follows the operator
+// before the right hand side.
+func (t *Tree) binary(operator item, lhs Node, rhs func() Node) Node {
+	matching := t.matching()
+	n := newBinary(operator, lhs, rhs())
+	n.Matching = matching
+	return n
+}
+
+// matching is the optional [matching] in the grammar.
+func (t *Tree) matching() *VectorMatching {
+	token := t.peek()
+	if token.typ != itemFunc || (token.val != "on" && token.val != "ignoring") {
+		return nil
+	}
+	t.next()
+	m := &VectorMatching{
+		On:             token.val == "on",
+		MatchingLabels: t.labels(token.val),
+	}
+
+	token = t.peek()
+	if token.typ != itemFunc || (token.val != "group_left" && token.val != "group_right") {
+		return m
+	}
+	t.next()
+	m.Card = CardManyToOne
+	if token.val == "group_right" {
+		m.Card = CardOneToMany
+	}
+	if t.peek().typ == itemLeftParen {
+		m.Include = t.labels(token.val)
+	}
+	return m
+}
+
+// labels is the labels list of vector matching modifiers in the grammar.
+func (t *Tree) labels(context string) []string {
+	t.expect(itemLeftParen, context)
+	labels := []string{}
+	for {
+		switch token := t.next(); token.typ {
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, s)
+		case itemRightParen:
+			if len(labels) == 0 {
+				return labels
+			}
+			t.unexpected(token, context)
+		default:
+			t.unexpected(token, context)
+		}
+		switch token := t.next(); token.typ {
+		case itemComma:
+			// continue with the next label
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+	}
+}
+
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
@@ -332,6 +404,14 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		switch token = t.next(); token.typ {
+		case itemComma:
+			// continue with the next argument
+		case itemRightParen:
+			return
+		default:
+			t.unexpected(token, "func")
+		}
 	}
 }
 
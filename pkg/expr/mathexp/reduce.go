@@ -3,6 +3,8 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,6 +83,111 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer that calculates the p-th percentile (0 <= p <= 100) of the values
+// using linear interpolation between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		var f float64
+		if fv.Len() == 0 {
+			f = math.NaN()
+			return &f
+		}
+		vals := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				f = math.NaN()
+				return &f
+			}
+			vals = append(vals, *v)
+		}
+		sort.Float64s(vals)
+		rank := p / 100 * float64(len(vals)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f = vals[lower] + (vals[upper]-vals[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// Variance returns the population variance of the values.
+func Variance(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		f += d * d
+	}
+	f /= float64(fv.Len())
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// ParsePercentile parses the name of a percentile reducer such as "p95" or "p99.9" and returns the percentile.
+func ParsePercentile(rFunc string) (float64, bool) {
+	if len(rFunc) < 2 || (rFunc[0] != 'p' && rFunc[0] != 'P') {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(rFunc[1:], 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
+// LooksLikePercentile returns true if the reduction function name looks like an attempt to name a
+// percentile, such as "p99.9x" or "percentile95", so that errors can explain how percentiles are named.
+func LooksLikePercentile(rFunc string) bool {
+	return strings.HasPrefix(strings.ToLower(rFunc), "p")
+}
+
+// PercentileHint is appended to errors about invalid reduction functions that look like a percentile.
+const PercentileHint = "percentiles are named pN with N between 0 and 100 such as p95"
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
@@ -95,14 +202,100 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return StdDev, nil
+	case "variance":
+		return Variance, nil
+	case "range":
+		return Range, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
+		if p, ok := ParsePercentile(rFunc); ok {
+			return Percentile(p), nil
+		}
+		if LooksLikePercentile(rFunc) {
+			return nil, fmt.Errorf("reduction %v not implemented, %s", rFunc, PercentileHint)
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names. Percentiles are supported as well but are
+// not listed, as their names are parsed from the pN syntax, see ParsePercentile.
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "stddev", "variance", "range", "count_non_null", "rate", "increase"}
+}
+
+// SeriesReducerFunc is a reduction function that, unlike ReducerFunc, has access to the timestamps of the series.
+type SeriesReducerFunc = func(s Series) *float64
+
+// GetSeriesReduceFunc returns the reduction function for the given name. In addition to the functions
+// supported by GetReduceFunc it supports the time-aware functions "rate" and "increase".
+func GetSeriesReduceFunc(rFunc string) (SeriesReducerFunc, error) {
+	switch strings.ToLower(rFunc) {
+	case "rate":
+		return Rate, nil
+	case "increase":
+		return Increase, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, err
+	}
+	return func(s Series) *float64 {
+		floatField := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+		return reduceFunc(&floatField)
+	}, nil
+}
+
+// Increase returns the increase of the series over its time range. A decrease between two
+// consecutive points is treated as a counter reset, in which case the value after the reset is
+// counted as the increase. The result is NaN if the series has less than two points or contains
+// null or NaN values.
+func Increase(s Series) *float64 {
+	var f float64
+	if s.Len() < 2 {
+		f = math.NaN()
+		return &f
+	}
+	var prev float64
+	for i := 0; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			f = math.NaN()
+			return &f
+		}
+		if i > 0 {
+			if *v >= prev {
+				f += *v - prev
+			} else {
+				f += *v
+			}
+		}
+		prev = *v
+	}
+	return &f
+}
+
+// Rate returns the per-second average rate of increase of the series over its time range.
+// See Increase for how counter resets are handled.
+func Rate(s Series) *float64 {
+	f := Increase(s)
+	if math.IsNaN(*f) {
+		return f
+	}
+	seconds := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if seconds <= 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	rate := *f / seconds
+	return &rate
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
@@ -119,13 +312,11 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
@@ -204,6 +204,83 @@ func TestSeriesReduce(t *testing.T) {
 	}
 }
 
+func TestSeriesReduceExtended(t *testing.T) {
+	counter := makeSeries("counter", nil,
+		tp{time.Unix(0, 0), float64Pointer(1)},
+		tp{time.Unix(10, 0), float64Pointer(5)},
+		tp{time.Unix(20, 0), float64Pointer(2)},
+		tp{time.Unix(30, 0), float64Pointer(4)},
+	)
+	values := makeSeries("values", nil,
+		tp{time.Unix(0, 0), float64Pointer(2)},
+		tp{time.Unix(10, 0), float64Pointer(4)},
+		tp{time.Unix(20, 0), float64Pointer(4)},
+		tp{time.Unix(30, 0), float64Pointer(4)},
+		tp{time.Unix(40, 0), float64Pointer(5)},
+		tp{time.Unix(50, 0), float64Pointer(5)},
+		tp{time.Unix(60, 0), float64Pointer(7)},
+		tp{time.Unix(70, 0), float64Pointer(9)},
+	)
+	single := makeSeries("single", nil, tp{time.Unix(0, 0), float64Pointer(3)})
+	withNil := makeSeries("nil", nil,
+		tp{time.Unix(0, 0), float64Pointer(3)},
+		tp{time.Unix(10, 0), nil},
+	)
+
+	var tests = []struct {
+		name     string
+		red      string
+		series   Series
+		expected float64
+	}{
+		{name: "first", red: "first", series: values, expected: 2},
+		{name: "median", red: "median", series: values, expected: 4.5},
+		{name: "p50 is the median", red: "p50", series: values, expected: 4.5},
+		{name: "p0 is the min", red: "p0", series: values, expected: 2},
+		{name: "p100 is the max", red: "p100", series: values, expected: 9},
+		{name: "p90 interpolates", red: "p90", series: values, expected: 7.6},
+		{name: "variance", red: "variance", series: values, expected: 4},
+		{name: "stddev", red: "stddev", series: values, expected: 2},
+		{name: "range", red: "range", series: values, expected: 7},
+		{name: "count_non_null", red: "count_non_null", series: withNil, expected: 1},
+		{name: "increase handles counter resets", red: "increase", series: counter, expected: 8},
+		{name: "rate handles counter resets", red: "rate", series: counter, expected: 8.0 / 30},
+		{name: "increase of a single point is NaN", red: "increase", series: single, expected: math.NaN()},
+		{name: "rate of a single point is NaN", red: "rate", series: single, expected: math.NaN()},
+		{name: "stddev with a nil value is NaN", red: "stddev", series: withNil, expected: math.NaN()},
+		{name: "p95 with a nil value is NaN", red: "p95", series: withNil, expected: math.NaN()},
+		{name: "range with a nil value is NaN", red: "range", series: withNil, expected: math.NaN()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, err := tt.series.Reduce("", tt.red, nil)
+			require.NoError(t, err)
+			f := num.GetFloat64Value()
+			require.NotNil(t, f)
+			if math.IsNaN(tt.expected) {
+				require.True(t, math.IsNaN(*f), "expected NaN but got %v", *f)
+				return
+			}
+			require.InDelta(t, tt.expected, *f, 1e-9)
+		})
+	}
+
+	t.Run("invalid percentile will error", func(t *testing.T) {
+		for _, red := range []string{"p101", "p-1", "pNaN", "p"} {
+			_, err := values.Reduce("", red, nil)
+			require.Errorf(t, err, "expected %s to error", red)
+			require.ErrorContains(t, err, "percentiles are named pN")
+		}
+	})
+
+	t.Run("unknown reducer that is not a percentile will error without the percentile hint", func(t *testing.T) {
+		_, err := values.Reduce("", "avg", nil)
+		require.ErrorContains(t, err, "reduction avg not implemented")
+		require.NotContains(t, err.Error(), "percentiles are named pN")
+	})
+}
+
 var seriesNonNumbers = Vars{
 	"A": resultValuesNoErr(
 		makeSeries("temp", nil,
@@ -13,9 +13,14 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
+	// the downsampler is only looked up when there is something to downsample
+	var downsample ReducerFunc
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	// the last point with a value, used for linear interpolation
+	var lastKnownTime time.Time
+	var lastKnown *float64
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -32,6 +37,9 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 			bookmark++
 			sIdx++
 			lastSeen = v
+			if v != nil {
+				lastKnownTime, lastKnown = st, v
+			}
 			vals = append(vals, v)
 		}
 		var value *float64
@@ -51,30 +59,23 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 				}
 			case "fillna":
 				value = nil
+			case "linear":
+				value = s.interpolate(t, sIdx, lastKnownTime, lastKnown)
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
 		} else if len(vals) == 1 {
 			value = vals[0]
 		} else { // downsampling
+			if downsample == nil {
+				var err error
+				if downsample, err = getDownsampleFunc(downsampler); err != nil {
+					return s, err
+				}
+			}
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case "sum":
-				tmp = Sum(&ff)
-			case "mean":
-				tmp = Avg(&ff)
-			case "min":
-				tmp = Min(&ff)
-			case "max":
-				tmp = Max(&ff)
-			case "last":
-				tmp = Last(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = downsample(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -82,3 +83,64 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 	}
 	return resampled, nil
 }
+
+// interpolate returns the value at time t on the line between the last known point and
+// the next point with a value, starting the search at index next.
+// It returns nil if there is no known point on either side of t.
+func (s Series) interpolate(t time.Time, next int, prevTime time.Time, prev *float64) *float64 {
+	if prev == nil {
+		return nil
+	}
+	for ; next < s.Len(); next++ {
+		nextTime, v := s.GetPoint(next)
+		if v == nil {
+			continue
+		}
+		ratio := float64(t.Sub(prevTime)) / float64(nextTime.Sub(prevTime))
+		value := *prev + (*v-*prev)*ratio
+		return &value
+	}
+	return nil
+}
+
+func getDownsampleFunc(downsampler string) (ReducerFunc, error) {
+	switch downsampler {
+	case "sum":
+		return Sum, nil
+	case "mean":
+		return Avg, nil
+	case "min":
+		return Min, nil
+	case "max":
+		return Max, nil
+	case "last":
+		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "count":
+		return Count, nil
+	}
+	if p, ok := ParsePercentile(downsampler); ok {
+		return Percentile(p), nil
+	}
+	return nil, fmt.Errorf("downsampling %v not implemented", downsampler)
+}
+
+// AlignToCalendar returns the start of the bucket that contains t when buckets of the given interval
+// are aligned to calendar boundaries in loc instead of to t itself. Intervals up to a day are aligned
+// to multiples of the interval on the wall clock since midnight, longer intervals are aligned to midnight.
+// The buckets follow the wall clock, so they stay aligned on days with a daylight saving time change.
+func AlignToCalendar(t time.Time, interval time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	year, month, day := local.Date()
+	if interval <= 0 || interval >= 24*time.Hour {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc).In(t.Location())
+	}
+	hour, minute, sec := local.Clock()
+	sinceMidnight := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(local.Nanosecond())
+	sinceMidnight -= sinceMidnight % interval
+	return time.Date(year, month, day, 0, 0, 0, int(sinceMidnight), loc).In(t.Location())
+}
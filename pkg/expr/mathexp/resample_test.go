@@ -277,6 +277,167 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(9, 0), float64Pointer(0),
 			}),
 		},
+		{
+			name:        "resample series: upsampling (mean / linear)",
+			interval:    time.Second * 2,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(7, 0), float64Pointer(1),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(1.6),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(1.2),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(10, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: downsampling (first / pad )",
+			interval:    time.Second * 3,
+			downsampler: "first",
+			upsampler:   "pad",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(3),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(1),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(3),
+			}, tp{
+				time.Unix(9, 0), float64Pointer(0),
+			}),
+		},
+		{
+			name:        "resample series: downsampling (median / fillna )",
+			interval:    time.Second * 5,
+			downsampler: "median",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(5, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(2),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), float64Pointer(2),
+			}),
+		},
+		{
+			name:        "resample series: downsampling (count / fillna )",
+			interval:    time.Second * 5,
+			downsampler: "count",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(5, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(2),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), float64Pointer(3),
+			}),
+		},
+		{
+			name:        "resample series: downsampling (percentile / fillna )",
+			interval:    time.Second * 5,
+			downsampler: "p75",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(5, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(2),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), float64Pointer(3.5),
+			}),
+		},
+		{
+			name:        "resample series: invalid percentile downsampler",
+			interval:    time.Second * 5,
+			downsampler: "p101",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(5, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(5),
+			}),
+		},
+		{
+			name:        "resample series: invalid downsampler is ignored when only upsampling",
+			interval:    time.Second * 2,
+			downsampler: "p101",
+			upsampler:   "pad",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(4, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), float64Pointer(1),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(2, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(1),
+			}),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -290,3 +451,24 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestAlignToCalendar(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	ts := time.Date(2023, 10, 10, 13, 47, 12, 0, time.UTC)
+
+	require.Equal(t, time.Date(2023, 10, 10, 13, 0, 0, 0, time.UTC), AlignToCalendar(ts, time.Hour, time.UTC))
+	require.Equal(t, time.Date(2023, 10, 10, 13, 45, 0, 0, time.UTC), AlignToCalendar(ts, 15*time.Minute, time.UTC))
+	require.Equal(t, time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC), AlignToCalendar(ts, 24*time.Hour, time.UTC))
+	// midnight in Berlin is 22:00 UTC the day before during summer time
+	require.Equal(t, time.Date(2023, 10, 9, 22, 0, 0, 0, time.UTC), AlignToCalendar(ts, 24*time.Hour, berlin))
+	require.Equal(t, time.Date(2023, 10, 10, 10, 0, 0, 0, time.UTC), AlignToCalendar(ts, 6*time.Hour, berlin))
+
+	// buckets follow the wall clock on days with a daylight saving time change
+	springForward := time.Date(2023, 3, 26, 8, 30, 0, 0, time.UTC) // 10:30 CEST
+	require.Equal(t, time.Date(2023, 3, 26, 4, 0, 0, 0, time.UTC), AlignToCalendar(springForward, 6*time.Hour, berlin))
+	require.Equal(t, time.Date(2023, 3, 25, 23, 0, 0, 0, time.UTC), AlignToCalendar(springForward, 24*time.Hour, berlin))
+	fallBack := time.Date(2023, 10, 29, 13, 47, 0, 0, time.UTC) // 14:47 CET
+	require.Equal(t, time.Date(2023, 10, 29, 11, 0, 0, 0, time.UTC), AlignToCalendar(fallBack, 6*time.Hour, berlin))
+	require.Equal(t, time.Date(2023, 10, 29, 13, 0, 0, 0, time.UTC), AlignToCalendar(fallBack, time.Hour, berlin))
+}
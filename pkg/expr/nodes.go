@@ -121,6 +121,13 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
+	case TypeSQL:
+		if !toggles.IsEnabled(featuremgmt.FlagSqlExpressions) {
+			return nil, fmt.Errorf("sql expressions are not enabled, expression '%v' cannot be used", rn.RefID)
+		}
+		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeAnomaly:
+		node.Command, err = UnmarshalAnomalyCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -148,6 +155,18 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is set when a SQL expression reads from the query, and isInputToOtherExpr
+	// when any other expression does. See keepsTables.
+	isInputToSQLExpr   bool
+	isInputToOtherExpr bool
+}
+
+// keepsTables returns true if only SQL expressions read from the query, in which case the response
+// frames are kept as tables instead of being converted to series or numbers. Otherwise the other
+// expressions get the usual conversion and SQL expressions convert the series and numbers to tables.
+func (dn *DSNode) keepsTables() bool {
+	return dn.isInputToSQLExpr && !dn.isInputToOtherExpr
 }
 
 // NodeType returns the data pipeline node type.
@@ -221,6 +240,13 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 		func() {
 			ctx, span := s.tracer.Start(ctx, "SSE.ExecuteDatasourceQuery")
 			defer span.End()
+			start := time.Now()
+			defer func() {
+				duration := time.Since(start)
+				for _, dn := range nodeGroup {
+					recordNodeStats(ctx, dn, duration)
+				}
+			}()
 			firstNode := nodeGroup[0]
 			pCtx, err := s.pCtxProvider.GetWithDataSource(ctx, firstNode.datasource.Type, firstNode.request.User, firstNode.datasource)
 			if err != nil {
@@ -288,6 +314,12 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
+				if dn.keepsTables() {
+					instrument(nil, "table")
+					vars[dn.refID] = framesToTableData(dataFrames)
+					continue
+				}
+
 				var result mathexp.Results
 				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 				if err != nil {
@@ -356,6 +388,11 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
 
+	if dn.keepsTables() {
+		responseType = "table"
+		return framesToTableData(dataFrames), nil
+	}
+
 	var result mathexp.Results
 	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 	if err != nil {
@@ -559,7 +596,10 @@ func extractNumberSet(frame *data.Frame) ([]mathexp.Number, error) {
 				labels = make(data.Labels)
 			}
 			key := stringFieldNames[i] // TODO check for duplicate string column names
-			val, _ := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			val, ok := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			if !ok {
+				continue // null values of nullable string fields are not labels
+			}
 			labels[key] = val.(string) // TODO check assertion / return error
 		}
 
@@ -114,6 +114,22 @@ func TestService(t *testing.T) {
 	if diff := cmp.Diff(expect, res, options...); diff != "" {
 		t.Errorf("Result mismatch (-want +got):\n%s", diff)
 	}
+
+	t.Run("collects execution stats of nodes", func(t *testing.T) {
+		ctx, stats := WithExecutionStats(context.Background())
+		_, err := s.ExecutePipeline(ctx, time.Now(), pl)
+		require.NoError(t, err)
+
+		nodes := stats.Nodes()
+		require.Len(t, nodes, 2)
+		require.Equal(t, "A", nodes[0].RefID)
+		require.Equal(t, TypeDatasourceNode, nodes[0].NodeType)
+		require.Equal(t, "test", nodes[0].DatasourceUID)
+		require.Equal(t, "B", nodes[1].RefID)
+		require.Equal(t, TypeCMDNode, nodes[1].NodeType)
+		require.Equal(t, TypeMath, nodes[1].CommandType)
+		require.Empty(t, nodes[1].DatasourceUID)
+	})
 }
 
 func TestDSQueryError(t *testing.T) {
@@ -0,0 +1,377 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteRecursive is the authorizer action code for recursive common table expressions. It is not exported by go-sqlite3.
+const sqliteRecursive = 33
+
+// Query loads the frames of each table into a new in-memory database and runs the SELECT statement against it.
+// The frames of a table are combined into a single table whose columns are the union of the fields and labels of
+// all frames. The statement is only allowed to read data.
+func Query(ctx context.Context, rawSQL string, tables map[string][]*data.Frame) (*data.Frame, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	// every connection to :memory: is a separate database, so everything has to run on the same connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	for name, frames := range tables {
+		if err := loadTable(ctx, conn, name, frames); err != nil {
+			return nil, fmt.Errorf("failed to load table %s: %w", name, err)
+		}
+	}
+
+	err = conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection type %T", driverConn)
+		}
+		c.RegisterAuthorizer(readOnlyAuthorizer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, rawSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return rowsToFrame(rows)
+}
+
+// readOnlyAuthorizer only allows the statement to read from tables and call functions.
+func readOnlyAuthorizer(action int, arg1, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	case sqlite3.SQLITE_FUNCTION:
+		if strings.EqualFold(arg1, "load_extension") {
+			return sqlite3.SQLITE_DENY
+		}
+		return sqlite3.SQLITE_OK
+	default:
+		return sqlite3.SQLITE_DENY
+	}
+}
+
+type column struct {
+	name    string
+	sqlType string
+}
+
+// loadTable creates the table and inserts the rows of all frames.
+func loadTable(ctx context.Context, conn *sql.Conn, name string, frames []*data.Frame) error {
+	frames = splitLabeledFields(frames)
+
+	var columns []column
+	index := map[string]int{}
+	addColumn := func(name, sqlType string) {
+		key := strings.ToUpper(name)
+		if _, ok := index[key]; ok {
+			return
+		}
+		index[key] = len(columns)
+		columns = append(columns, column{name: name, sqlType: sqlType})
+	}
+	for _, frame := range frames {
+		for i, field := range frame.Fields {
+			addColumn(fieldName(field, i), sqlType(field.Type()))
+		}
+	}
+	// labels are added after all fields, so a field always wins over a label with the same name
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			for _, k := range sortedLabelKeys(field.Labels) {
+				addColumn(k, "TEXT")
+			}
+		}
+	}
+	if len(columns) == 0 {
+		addColumn("value", "REAL")
+	}
+
+	defs := make([]string, 0, len(columns))
+	placeholders := make([]string, 0, len(columns))
+	for _, c := range columns {
+		defs = append(defs, fmt.Sprintf("%s %s", quoteIdent(c.name), c.sqlType))
+		placeholders = append(placeholders, "?")
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(name), strings.Join(defs, ", "))); err != nil {
+		return err
+	}
+
+	stmt, err := conn.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdent(name), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, frame := range frames {
+		rowLen, err := frame.RowLen()
+		if err != nil {
+			return err
+		}
+		// label values are the same for every row of the frame, fields are set after them so they take precedence
+		labelArgs := map[int]any{}
+		for _, field := range frame.Fields {
+			for k, v := range field.Labels {
+				labelArgs[index[strings.ToUpper(k)]] = v
+			}
+		}
+		for row := 0; row < rowLen; row++ {
+			args := make([]any, len(columns))
+			for idx, v := range labelArgs {
+				args[idx] = v
+			}
+			for i, field := range frame.Fields {
+				args[index[strings.ToUpper(fieldName(field, i))]] = sqlValue(field, row)
+			}
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitLabeledFields splits frames in the wide format, that have more than one field with labels,
+// into one frame per labeled field so that each row belongs to a single set of labels.
+func splitLabeledFields(frames []*data.Frame) []*data.Frame {
+	result := make([]*data.Frame, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		var labeled, unlabeled []*data.Field
+		for _, field := range frame.Fields {
+			if len(field.Labels) > 0 {
+				labeled = append(labeled, field)
+			} else {
+				unlabeled = append(unlabeled, field)
+			}
+		}
+		if len(labeled) <= 1 {
+			result = append(result, frame)
+			continue
+		}
+		for _, field := range labeled {
+			fields := make([]*data.Field, 0, len(unlabeled)+1)
+			fields = append(fields, unlabeled...)
+			fields = append(fields, field)
+			result = append(result, data.NewFrame(frame.Name, fields...))
+		}
+	}
+	return result
+}
+
+func fieldName(field *data.Field, idx int) string {
+	if field.Name != "" {
+		return field.Name
+	}
+	switch {
+	case field.Type().Time():
+		return "time"
+	case field.Type().Numeric():
+		return "value"
+	default:
+		return fmt.Sprintf("field_%d", idx)
+	}
+}
+
+func sqlType(ft data.FieldType) string {
+	switch {
+	case ft.Time():
+		return "DATETIME"
+	case ft.Numeric():
+		return "REAL"
+	case ft == data.FieldTypeBool || ft == data.FieldTypeNullableBool:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func sqlValue(field *data.Field, row int) any {
+	if field.Type().Numeric() {
+		f, err := field.NullableFloatAt(row)
+		if err != nil || f == nil {
+			return nil
+		}
+		return *f
+	}
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC()
+	case bool, string:
+		return t
+	case json.RawMessage:
+		return string(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func sortedLabelKeys(labels data.Labels) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// rowsToFrame reads all rows into a frame. The type of each field is derived from the values of the column:
+// numbers become float64, and a column with values of different types becomes a string field.
+func rowsToFrame(rows *sql.Rows) (*data.Frame, error) {
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]any, len(names))
+	for rows.Next() {
+		dest := make([]any, len(names))
+		ptrs := make([]any, len(names))
+		for i := range dest {
+			ptrs[i] = &dest[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i, v := range dest {
+			values[i] = append(values[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame("")
+	for i, name := range names {
+		field, err := columnToField(name, values[i])
+		if err != nil {
+			return nil, err
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame, nil
+}
+
+type columnKind int
+
+const (
+	kindUnknown columnKind = iota
+	kindNumber
+	kindTime
+	kindBool
+	kindString
+)
+
+func kindOf(v any) columnKind {
+	switch v.(type) {
+	case int64, float64:
+		return kindNumber
+	case time.Time:
+		return kindTime
+	case bool:
+		return kindBool
+	default:
+		return kindString
+	}
+}
+
+func columnToField(name string, values []any) (*data.Field, error) {
+	kind := kindUnknown
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		k := kindOf(v)
+		if kind == kindUnknown {
+			kind = k
+		} else if kind != k {
+			kind = kindString
+			break
+		}
+	}
+
+	switch kind {
+	case kindNumber, kindUnknown:
+		vals := make([]*float64, len(values))
+		for i, v := range values {
+			switch t := v.(type) {
+			case int64:
+				f := float64(t)
+				vals[i] = &f
+			case float64:
+				f := t
+				vals[i] = &f
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case kindTime:
+		vals := make([]*time.Time, len(values))
+		for i, v := range values {
+			if t, ok := v.(time.Time); ok {
+				vals[i] = &t
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case kindBool:
+		vals := make([]*bool, len(values))
+		for i, v := range values {
+			if b, ok := v.(bool); ok {
+				vals[i] = &b
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case kindString:
+		vals := make([]*string, len(values))
+		for i, v := range values {
+			if v == nil {
+				continue
+			}
+			var s string
+			switch t := v.(type) {
+			case []byte:
+				s = string(t)
+			case time.Time:
+				s = t.Format(time.RFC3339Nano)
+			default:
+				s = fmt.Sprint(t)
+			}
+			vals[i] = &s
+		}
+		return data.NewField(name, nil, vals), nil
+	}
+	return nil, errors.New("unexpected column type")
+}
@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestQuery(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	inventory := data.NewFrame("",
+		data.NewField("host", nil, []string{"web-1", "web-2", "db-1"}),
+		data.NewField("team", nil, []string{"frontend", "frontend", "storage"}),
+	)
+	cpu := data.Frames{
+		data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{now, now.Add(time.Minute)}),
+			data.NewField("Value", data.Labels{"host": "web-1"}, []float64{1, 3}),
+		),
+		data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{now, now.Add(time.Minute)}),
+			data.NewField("Value", data.Labels{"host": "web-2"}, []*float64{util.Pointer(5.0), nil}),
+		),
+	}
+	tables := map[string][]*data.Frame{
+		"A": {inventory},
+		"B": cpu,
+	}
+
+	t.Run("join and group by", func(t *testing.T) {
+		frame, err := Query(context.Background(), `
+			SELECT A.team, avg(B.value) AS cpu
+			FROM A JOIN B ON A.host = B.host
+			GROUP BY A.team
+			ORDER BY A.team`, tables)
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("",
+			data.NewField("team", nil, []*string{util.Pointer("frontend")}),
+			data.NewField("cpu", nil, []*float64{util.Pointer(3.0)}),
+		), frame)
+	})
+
+	t.Run("labels become columns and times are kept", func(t *testing.T) {
+		frame, err := Query(context.Background(), `SELECT time, host, value FROM B WHERE value IS NULL`, tables)
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("",
+			data.NewField("Time", nil, []*time.Time{util.Pointer(now.Add(time.Minute))}),
+			data.NewField("host", nil, []*string{util.Pointer("web-2")}),
+			data.NewField("Value", nil, []*float64{nil}),
+		), frame)
+	})
+
+	t.Run("wide frames are split by labels", func(t *testing.T) {
+		wide := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{now}),
+			data.NewField("value", data.Labels{"host": "a"}, []float64{1}),
+			data.NewField("value", data.Labels{"host": "b"}, []float64{2}),
+		)
+		frame, err := Query(context.Background(), `SELECT host, value FROM C ORDER BY host`, map[string][]*data.Frame{"C": {wide}})
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("",
+			data.NewField("host", nil, []*string{util.Pointer("a"), util.Pointer("b")}),
+			data.NewField("value", nil, []*float64{util.Pointer(1.0), util.Pointer(2.0)}),
+		), frame)
+	})
+
+	t.Run("statements can only read data", func(t *testing.T) {
+		for _, stmt := range []string{
+			"DELETE FROM A",
+			"INSERT INTO A VALUES ('x', 'y')",
+			"ATTACH DATABASE 'test.db' AS other",
+			"CREATE TABLE C (x TEXT)",
+			"PRAGMA table_info(A)",
+			"SELECT 1; DROP TABLE A",
+		} {
+			_, err := Query(context.Background(), stmt, tables)
+			require.Errorf(t, err, "expected %q to be denied", stmt)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenIdent tokenType = iota
+	tokenQuotedIdent
+	tokenSymbol
+)
+
+type token struct {
+	typ tokenType
+	val string
+}
+
+// keywords that end a table reference in a FROM clause, so they are never treated as a table alias.
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "LIMIT": true, "HAVING": true, "WINDOW": true,
+	"JOIN": true, "LEFT": true, "RIGHT": true, "FULL": true, "INNER": true, "OUTER": true, "CROSS": true, "NATURAL": true,
+	"ON": true, "USING": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "SELECT": true, "FROM": true,
+}
+
+// TablesList returns the names of the tables the SQL statement reads from, in order of appearance.
+// Names of common table expressions and table-valued functions are not included.
+func TablesList(rawSQL string) ([]string, error) {
+	tokens, err := tokenize(rawSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctes := map[string]bool{}
+	for i, t := range tokens {
+		// WITH name AS ( ... ) or WITH name(col1, col2) AS ( ... )
+		if !isKeyword(t, "AS") || i+1 >= len(tokens) || tokens[i+1].val != "(" || i == 0 {
+			continue
+		}
+		prev := i - 1
+		if tokens[prev].val == ")" && tokens[prev].typ == tokenSymbol {
+			depth := 0
+			for ; prev >= 0; prev-- {
+				if tokens[prev].typ != tokenSymbol {
+					continue
+				}
+				if tokens[prev].val == ")" {
+					depth++
+				} else if tokens[prev].val == "(" {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			prev--
+		}
+		if prev >= 0 && tokens[prev].typ != tokenSymbol {
+			ctes[strings.ToUpper(tokens[prev].val)] = true
+		}
+	}
+
+	var tables []string
+	seen := map[string]bool{}
+	addTable := func(name string) {
+		if ctes[strings.ToUpper(name)] || seen[name] {
+			return
+		}
+		seen[name] = true
+		tables = append(tables, name)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		isFrom := isKeyword(tokens[i], "FROM")
+		if !isFrom && !isKeyword(tokens[i], "JOIN") {
+			continue
+		}
+		for j := i + 1; j < len(tokens); {
+			name, next, ok := tableReference(tokens, j)
+			if !ok {
+				break
+			}
+			if name != "" {
+				addTable(name)
+			}
+			j = next
+			// alias, with or without AS
+			if j < len(tokens) && isKeyword(tokens[j], "AS") {
+				j++
+			}
+			if j < len(tokens) && tokens[j].typ != tokenSymbol && !clauseKeywords[strings.ToUpper(tokens[j].val)] {
+				j++
+			}
+			// FROM a, b
+			if !isFrom || j >= len(tokens) || tokens[j].val != "," || tokens[j].typ != tokenSymbol {
+				break
+			}
+			j++
+		}
+	}
+	return tables, nil
+}
+
+// tableReference reads the table name starting at position i. It returns an empty name if the
+// reference is a subquery or a table-valued function, which are skipped.
+func tableReference(tokens []token, i int) (string, int, bool) {
+	if i >= len(tokens) {
+		return "", i, false
+	}
+	t := tokens[i]
+	if t.typ == tokenSymbol || (t.typ == tokenIdent && clauseKeywords[strings.ToUpper(t.val)]) {
+		// a subquery is handled when its own FROM clause is reached
+		return "", i, false
+	}
+	name := t.val
+	i++
+	// schema qualified name such as main.A
+	if i+1 < len(tokens) && tokens[i].typ == tokenSymbol && tokens[i].val == "." {
+		name = tokens[i+1].val
+		i += 2
+	}
+	if i < len(tokens) && tokens[i].typ == tokenSymbol && tokens[i].val == "(" {
+		return "", i, true
+	}
+	return name, i, true
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.typ == tokenIdent && strings.EqualFold(t.val, keyword)
+}
+
+// tokenize splits the SQL statement into identifiers and symbols. Literals and comments are dropped.
+func tokenize(rawSQL string) ([]token, error) {
+	var tokens []token
+	r := []rune(rawSQL)
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			j := i + 2
+			for j+1 < len(r) && (r[j] != '*' || r[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(r) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = j + 1
+		case c == '\'':
+			j, err := closingQuote(r, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = j
+		case c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			j, err := closingQuote(r, i, closing)
+			if err != nil {
+				return nil, err
+			}
+			val := string(r[i+1 : j])
+			if c != '[' {
+				val = strings.ReplaceAll(val, string([]rune{c, c}), string(c))
+			}
+			tokens = append(tokens, token{typ: tokenQuotedIdent, val: val})
+			i = j
+		case isIdentRune(c):
+			j := i
+			for j < len(r) && isIdentRune(r[j]) {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokenIdent, val: string(r[i:j])})
+			i = j - 1
+		default:
+			tokens = append(tokens, token{typ: tokenSymbol, val: string(c)})
+		}
+	}
+	return tokens, nil
+}
+
+// closingQuote returns the position of the quote that closes the quote at position start.
+// Doubled quotes are treated as an escaped quote.
+func closingQuote(r []rune, start int, quote rune) (int, error) {
+	for j := start + 1; j < len(r); j++ {
+		if r[j] != quote {
+			continue
+		}
+		if quote != ']' && j+1 < len(r) && r[j+1] == quote {
+			j++
+			continue
+		}
+		return j, nil
+	}
+	return 0, fmt.Errorf("unterminated quote %q", quote)
+}
+
+func isIdentRune(c rune) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c > 127
+}
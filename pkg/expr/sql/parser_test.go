@@ -0,0 +1,68 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablesList(t *testing.T) {
+	var tests = []struct {
+		name     string
+		sql      string
+		expected []string
+	}{
+		{
+			name:     "single table",
+			sql:      "SELECT * FROM A",
+			expected: []string{"A"},
+		},
+		{
+			name:     "join",
+			sql:      `SELECT a.host, b.value FROM A a LEFT JOIN "B" AS b ON a.host = b.host WHERE b.value > 1`,
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "comma separated tables",
+			sql:      "SELECT * FROM A a, B AS b, C WHERE a.x = b.x",
+			expected: []string{"A", "B", "C"},
+		},
+		{
+			name:     "subquery",
+			sql:      "SELECT host, avg(value) FROM (SELECT * FROM A WHERE value > 0) GROUP BY host",
+			expected: []string{"A"},
+		},
+		{
+			name:     "common table expressions are not tables",
+			sql:      "WITH latest(host, value) AS (SELECT host, max(value) FROM A GROUP BY host), other AS (SELECT * FROM B) SELECT * FROM latest JOIN other USING (host)",
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "literals and comments are ignored",
+			sql:      "SELECT 'FROM X' AS a /* FROM Y */ FROM A -- FROM Z\nWHERE a = 'JOIN W'",
+			expected: []string{"A"},
+		},
+		{
+			name:     "table-valued functions are not tables",
+			sql:      "SELECT * FROM json_each('[1,2]')",
+			expected: nil,
+		},
+		{
+			name:     "tables are only listed once",
+			sql:      "SELECT * FROM A UNION SELECT * FROM A",
+			expected: []string{"A"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := TablesList(tt.sql)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tables)
+		})
+	}
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		_, err := TablesList("SELECT * FROM A WHERE host = 'a")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,167 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// SQLCommand is an expression that runs a SQL SELECT statement over the results of other queries and expressions.
+// Every query or expression the statement reads from is available as a table named by its refID.
+type SQLCommand struct {
+	query       string
+	varsToQuery []string
+	refID       string
+}
+
+// NewSQLCommand creates a new SQLCommand.
+func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
+	if rawSQL == "" {
+		return nil, errors.New("sql expression is empty")
+	}
+	tables, err := sql.TablesList(rawSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sql expression: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("sql expression does not read from any query or expression")
+	}
+	return &SQLCommand{
+		query:       rawSQL,
+		varsToQuery: tables,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	rawExpr, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("sql command is missing an expression")
+	}
+	expressionRaw, ok := rawExpr.(string)
+	if !ok {
+		return nil, fmt.Errorf("sql expression is expected to be a string, got %T", rawExpr)
+	}
+	return NewSQLCommand(rn.RefID, expressionRaw)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gr *SQLCommand) NeedsVars() []string {
+	return gr.varsToQuery
+}
+
+// resolveTables replaces the table names of the statement with the refIDs of the queries and expressions they read
+// from. Table names are case-insensitive in SQL, so a table "a" reads from the query with refID "A" unless there is a
+// query with refID "a".
+func (gr *SQLCommand) resolveTables(registry map[string]Node) {
+	resolved := make([]string, 0, len(gr.varsToQuery))
+	seen := make(map[string]bool, len(gr.varsToQuery))
+	for _, table := range gr.varsToQuery {
+		refID := table
+		if _, ok := registry[table]; !ok {
+			for id := range registry {
+				if strings.EqualFold(id, table) {
+					refID = id
+					break
+				}
+			}
+		}
+		if seen[refID] {
+			continue
+		}
+		seen[refID] = true
+		resolved = append(resolved, refID)
+	}
+	gr.varsToQuery = resolved
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gr *SQLCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteSQL")
+	defer span.End()
+
+	span.SetAttributes(attribute.StringSlice("sql.tables", gr.varsToQuery))
+
+	tables := make(map[string][]*data.Frame, len(gr.varsToQuery))
+	for _, ref := range gr.varsToQuery {
+		tables[ref] = valuesToTableFrames(vars[ref].Values)
+	}
+
+	frame, err := sql.Query(ctx, gr.query, tables)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute sql expression: %w", err)
+	}
+	frame.RefID = gr.refID
+
+	if frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+	}
+
+	// tables with a single numeric column can be used like the results of reduce expressions, e.g. in alert conditions
+	if isNumberTable(frame) {
+		numbers, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make(mathexp.Values, 0, len(numbers))
+		for _, n := range numbers {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// valuesToTableFrames returns the frames to load into the table of a SQL expression. Series, numbers and scalars
+// are converted to frames with "time" and "value" fields, whose labels become additional columns.
+func valuesToTableFrames(values mathexp.Values) []*data.Frame {
+	frames := make([]*data.Frame, 0, len(values))
+	for _, v := range values {
+		frame := v.AsDataFrame()
+		switch v.(type) {
+		case mathexp.TableData:
+			frames = append(frames, frame)
+		case mathexp.Series:
+			frames = append(frames, data.NewFrame("", renameField(frame.Fields[0], "time"), renameField(frame.Fields[1], "value")))
+		case mathexp.Number, mathexp.Scalar:
+			frames = append(frames, data.NewFrame("", renameField(frame.Fields[0], "value")))
+		}
+	}
+	return frames
+}
+
+func renameField(field *data.Field, name string) *data.Field {
+	f := *field
+	f.Name = name
+	f.Config = nil
+	return &f
+}
+
+// framesToTableData returns each frame of a data source response as TableData. It is used instead of the usual
+// conversion to series and numbers when the query is an input to a SQL expression.
+func framesToTableData(frames data.Frames) mathexp.Results {
+	vals := make(mathexp.Values, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil || len(frame.Fields) == 0 {
+			continue
+		}
+		vals = append(vals, mathexp.TableData{Frame: frame})
+	}
+	if len(vals) == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}
+	}
+	return mathexp.Results{Values: vals}
+}
@@ -0,0 +1,214 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewSQLCommand(t *testing.T) {
+	cmd, err := NewSQLCommand("C", "SELECT * FROM A JOIN B ON A.host = B.host")
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+
+	_, err = NewSQLCommand("C", "")
+	require.Error(t, err)
+
+	_, err = NewSQLCommand("C", "SELECT 1")
+	require.ErrorContains(t, err, "does not read from any query or expression")
+}
+
+func TestSQLCommandExecute(t *testing.T) {
+	series := mathexp.NewSeries("A", data.Labels{"host": "a"}, 2)
+	series.SetPoint(0, time.Unix(0, 0), fp(1))
+	series.SetPoint(1, time.Unix(60, 0), fp(3))
+	other := mathexp.NewSeries("A", data.Labels{"host": "b"}, 1)
+	other.SetPoint(0, time.Unix(0, 0), fp(10))
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{series, other}},
+	}
+
+	t.Run("a single numeric column is returned as numbers", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT host, max(value) AS value FROM A GROUP BY host ORDER BY host")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+
+		expected := []struct {
+			host  string
+			value float64
+		}{{"a", 3}, {"b", 10}}
+		for i, v := range res.Values {
+			n, ok := v.(mathexp.Number)
+			require.True(t, ok)
+			require.Equal(t, data.Labels{"host": expected[i].host}, n.GetLabels())
+			require.Equal(t, expected[i].value, *n.GetFloat64Value())
+		}
+	})
+
+	t.Run("other results are returned as a table", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT time, value FROM A WHERE host = 'a' ORDER BY time")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+		require.Equal(t, 2, table.Frame.Rows())
+		require.Equal(t, "B", table.Frame.RefID)
+	})
+
+	t.Run("empty results are no data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT * FROM A WHERE value > 100")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+}
+
+func TestSQLExpression(t *testing.T) {
+	inventory := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b"}),
+		data.NewField("team", nil, []string{"frontend", "storage"}),
+	)
+	cpu := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0)}),
+		data.NewField("value", data.Labels{"host": "a"}, []float64{1, 1}),
+		data.NewField("value", data.Labels{"host": "b"}, []float64{2, 2}),
+	)
+
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{inventory}},
+			"B": {Frames: data.Frames{cpu}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeDataSourceService{}, nil, fakes.NewFakeLicensingService(), &config.Cfg{})
+
+	s := Service{
+		cfg:          setting.NewCfg(),
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions),
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+	}
+
+	dsQuery := func(refID string) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"},
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "1" } }`),
+			TimeRange:  AbsoluteTimeRange{},
+		}
+	}
+	queries := []Query{
+		dsQuery("A"),
+		dsQuery("B"),
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON: json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql",
+				"expression": "SELECT A.team, sum(B.value) AS value FROM A JOIN B ON A.host = B.host GROUP BY A.team ORDER BY A.team" }`),
+		},
+	}
+
+	req := &Request{Queries: queries, User: &user.SignedInUser{}}
+
+	pl, err := s.BuildPipeline(req)
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["C"].Error)
+
+	frames := res.Responses["C"].Frames
+	require.Len(t, frames, 2)
+	require.Equal(t, data.Labels{"team": "frontend"}, frames[0].Fields[0].Labels)
+	require.Equal(t, fp(2), frames[0].Fields[0].At(0))
+	require.Equal(t, data.Labels{"team": "storage"}, frames[1].Fields[0].Labels)
+	require.Equal(t, fp(4), frames[1].Fields[0].At(0))
+
+	t.Run("other expressions reading the same query get series", func(t *testing.T) {
+		req := &Request{Queries: append(queries, Query{
+			RefID:      "D",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$B * 2" }`),
+		}), User: &user.SignedInUser{}}
+
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["D"].Error)
+		require.Len(t, res.Responses["D"].Frames, 2)
+		require.Equal(t, data.Labels{"host": "a"}, res.Responses["D"].Frames[0].Fields[1].Labels)
+		require.Equal(t, fp(2), res.Responses["D"].Frames[0].Fields[1].At(0))
+
+		require.NoError(t, res.Responses["C"].Error)
+		frames := res.Responses["C"].Frames
+		require.Len(t, frames, 2)
+		require.Equal(t, fp(2), frames[0].Fields[0].At(0))
+		require.Equal(t, fp(4), frames[1].Fields[0].At(0))
+	})
+
+	t.Run("table names match refIDs case-insensitively", func(t *testing.T) {
+		req := &Request{Queries: []Query{
+			dsQuery("A"),
+			dsQuery("B"),
+			{
+				RefID:      "C",
+				DataSource: dataSourceModel(),
+				JSON: json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql",
+					"expression": "SELECT a.team, sum(b.value) AS value FROM a JOIN b ON a.host = b.host GROUP BY a.team ORDER BY a.team" }`),
+			},
+		}, User: &user.SignedInUser{}}
+
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["C"].Error)
+		frames := res.Responses["C"].Frames
+		require.Len(t, frames, 2)
+		require.Equal(t, fp(2), frames[0].Fields[0].At(0))
+		require.Equal(t, fp(4), frames[1].Fields[0].At(0))
+	})
+
+	t.Run("sql expressions require the feature toggle", func(t *testing.T) {
+		s.features = featuremgmt.WithFeatures()
+		_, err := s.BuildPipeline(req)
+		require.ErrorContains(t, err, "sql expressions are not enabled")
+	})
+}
@@ -0,0 +1,73 @@
+package expr
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NodeStats describes the execution of a node of a pipeline.
+type NodeStats struct {
+	RefID    string
+	NodeType NodeType
+	// CommandType is only set for expression nodes.
+	CommandType CommandType
+	// DatasourceUID and DatasourceType are only set for data source nodes.
+	DatasourceUID  string
+	DatasourceType string
+	// Duration is the time the node took to execute. Data source nodes that are queried together in one request
+	// all have the duration of the request.
+	Duration time.Duration
+}
+
+// ExecutionStats collects the NodeStats of the pipelines that are executed with a context returned by
+// WithExecutionStats.
+type ExecutionStats struct {
+	mtx   sync.Mutex
+	nodes []NodeStats
+}
+
+// Nodes returns the stats of the executed nodes in the order they were executed.
+func (s *ExecutionStats) Nodes() []NodeStats {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	result := make([]NodeStats, len(s.nodes))
+	copy(result, s.nodes)
+	return result
+}
+
+func (s *ExecutionStats) add(stats NodeStats) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.nodes = append(s.nodes, stats)
+}
+
+type executionStatsKey struct{}
+
+// WithExecutionStats returns a context that collects the stats of the executed pipeline nodes.
+func WithExecutionStats(ctx context.Context) (context.Context, *ExecutionStats) {
+	stats := &ExecutionStats{}
+	return context.WithValue(ctx, executionStatsKey{}, stats), stats
+}
+
+func recordNodeStats(ctx context.Context, node Node, duration time.Duration) {
+	stats, ok := ctx.Value(executionStatsKey{}).(*ExecutionStats)
+	if !ok {
+		return
+	}
+	ns := NodeStats{
+		RefID:    node.RefID(),
+		NodeType: node.NodeType(),
+		Duration: duration,
+	}
+	switch n := node.(type) {
+	case *DSNode:
+		if n.datasource != nil {
+			ns.DatasourceUID = n.datasource.UID
+			ns.DatasourceType = n.datasource.Type
+		}
+	case *CMDNode:
+		ns.CommandType = n.CMDType
+	}
+	stats.add(ns)
+}
@@ -2,8 +2,23 @@ package caching
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -15,6 +30,20 @@ const (
 	StatusDisabled = "DISABLED"
 )
 
+const (
+	queryCacheKeyPrefix    = "query-cache:"
+	resourceCacheKeyPrefix = "resource-cache:"
+
+	// Keys in the datasource JSON data that control caching for a single datasource.
+	jsonDataCachingDisabled    = "queryCachingDisabled"
+	jsonDataQueryCachingTTL    = "queryCachingTTL"
+	jsonDataResourceCachingTTL = "resourceCachingTTL"
+)
+
+// volatileQueryFields are removed from the query JSON before building the cache key
+// because they change between otherwise identical requests.
+var volatileQueryFields = []string{"requestId", "queryCachingTTL"}
+
 type CacheQueryResponseFn func(context.Context, *backend.QueryDataResponse)
 type CacheResourceResponseFn func(context.Context, *backend.CallResourceResponse)
 
@@ -35,8 +64,12 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
+func ProvideCachingService(cfg *setting.Cfg, cache remotecache.CacheStorage) *OSSCachingService {
+	return &OSSCachingService{
+		cache:    cache,
+		settings: cfg.Caching,
+		log:      log.New("caching"),
+	}
 }
 
 type CachingService interface {
@@ -49,16 +82,301 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+// OSSCachingService caches query and resource responses in the remote cache.
+// Cache keys include the time the datasource was last updated, so updating a datasource
+// invalidates all of its cached responses.
 type OSSCachingService struct {
+	cache    remotecache.CacheStorage
+	settings setting.CachingSettings
+	log      log.Logger
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if !s.settings.Enabled || s.cache == nil || req == nil {
+		return false, CachedQueryDataResponse{}
+	}
+
+	ds := req.PluginContext.DataSourceInstanceSettings
+	if ds == nil || skipCache(ctx) || hasAuthHeaders(req.Headers) {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedQueryDataResponse{}
+	}
+
+	ttl, enabled := s.queryTTL(ds, req.Queries)
+	if !enabled {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req, s.settings.TimeRangeStep)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to build query cache key", "datasource", ds.UID, "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	if b, err := s.cache.Get(ctx, key); err == nil {
+		resp := &backend.QueryDataResponse{}
+		if err := json.Unmarshal(b, resp); err == nil {
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedQueryDataResponse{Response: resp}
+		}
+		s.log.FromContext(ctx).Warn("Failed to decode cached query response", "datasource", ds.UID, "error", err)
+	} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.FromContext(ctx).Debug("Failed to read query response from cache", "datasource", ds.UID, "error", err)
+	}
+
+	setCacheStatus(ctx, StatusMiss)
+	return false, CachedQueryDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.QueryDataResponse) {
+			if resp == nil || hasResponseErrors(resp) {
+				return
+			}
+			b, err := json.Marshal(resp)
+			if err != nil {
+				s.log.FromContext(ctx).Warn("Failed to encode query response for cache", "datasource", ds.UID, "error", err)
+				return
+			}
+			s.set(ctx, key, b, ttl)
+		},
+	}
 }
 
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if !s.settings.Enabled || s.cache == nil || req == nil {
+		return false, CachedResourceDataResponse{}
+	}
+
+	ds := req.PluginContext.DataSourceInstanceSettings
+	if ds == nil || req.Method != http.MethodGet || skipCache(ctx) || hasAuthHeaders(req.Headers) {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	ttl, enabled := s.resourceTTL(ds)
+	if !enabled {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key := resourceCacheKey(req)
+	if b, err := s.cache.Get(ctx, key); err == nil {
+		resp := &backend.CallResourceResponse{}
+		if err := json.Unmarshal(b, resp); err == nil {
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedResourceDataResponse{Response: resp}
+		}
+		s.log.FromContext(ctx).Warn("Failed to decode cached resource response", "datasource", ds.UID, "error", err)
+	} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.FromContext(ctx).Debug("Failed to read resource response from cache", "datasource", ds.UID, "error", err)
+	}
+
+	setCacheStatus(ctx, StatusMiss)
+
+	// Only responses that arrive in a single message are cached. If the plugin streams
+	// several messages we drop whatever was stored for the first one.
+	var mu sync.Mutex
+	calls := 0
+	return false, CachedResourceDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.CallResourceResponse) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if calls > 1 {
+				if calls == 2 {
+					if err := s.cache.Delete(ctx, key); err != nil {
+						s.log.FromContext(ctx).Debug("Failed to delete streamed resource response from cache", "datasource", ds.UID, "error", err)
+					}
+				}
+				return
+			}
+			if resp == nil || resp.Status < http.StatusOK || resp.Status >= http.StatusMultipleChoices {
+				return
+			}
+			b, err := json.Marshal(resp)
+			if err != nil {
+				s.log.FromContext(ctx).Warn("Failed to encode resource response for cache", "datasource", ds.UID, "error", err)
+				return
+			}
+			s.set(ctx, key, b, ttl)
+		},
+	}
+}
+
+func (s *OSSCachingService) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if s.settings.MaxValueSize > 0 && len(value) > s.settings.MaxValueSize {
+		s.log.FromContext(ctx).Debug("Response too large to cache", "size", len(value), "limit", s.settings.MaxValueSize)
+		return
+	}
+	if err := s.cache.Set(ctx, key, value, ttl); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to write response to cache", "error", err)
+	}
+}
+
+// queryTTL returns the TTL for a query request and whether caching is enabled for it.
+// A queryCachingTTL set on the queries takes precedence over the one configured on the datasource,
+// which in turn takes precedence over the default TTL.
+func (s *OSSCachingService) queryTTL(ds *backend.DataSourceInstanceSettings, queries []backend.DataQuery) (time.Duration, bool) {
+	jsonData := dataSourceJSONData(ds)
+	if disabled, _ := jsonData[jsonDataCachingDisabled].(bool); disabled {
+		return 0, false
+	}
+
+	var ttl time.Duration
+	for _, q := range queries {
+		var model struct {
+			QueryCachingTTL int64 `json:"queryCachingTTL"`
+		}
+		if err := json.Unmarshal(q.JSON, &model); err != nil || model.QueryCachingTTL <= 0 {
+			continue
+		}
+		if qTTL := time.Duration(model.QueryCachingTTL) * time.Millisecond; ttl == 0 || qTTL < ttl {
+			ttl = qTTL
+		}
+	}
+	if ttl == 0 {
+		ttl = durationFromJSONData(jsonData, jsonDataQueryCachingTTL, s.settings.TTL)
+	}
+	return ttl, ttl > 0
+}
+
+func (s *OSSCachingService) resourceTTL(ds *backend.DataSourceInstanceSettings) (time.Duration, bool) {
+	jsonData := dataSourceJSONData(ds)
+	if disabled, _ := jsonData[jsonDataCachingDisabled].(bool); disabled {
+		return 0, false
+	}
+	ttl := durationFromJSONData(jsonData, jsonDataResourceCachingTTL, s.settings.ResourcesTTL)
+	return ttl, ttl > 0
+}
+
+func dataSourceJSONData(ds *backend.DataSourceInstanceSettings) map[string]any {
+	jsonData := map[string]any{}
+	if len(ds.JSONData) > 0 {
+		_ = json.Unmarshal(ds.JSONData, &jsonData)
+	}
+	return jsonData
+}
+
+// durationFromJSONData reads a duration in milliseconds from the datasource JSON data.
+func durationFromJSONData(jsonData map[string]any, key string, def time.Duration) time.Duration {
+	switch v := jsonData[key].(type) {
+	case float64:
+		if v > 0 {
+			return time.Duration(v) * time.Millisecond
+		}
+	case string:
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return def
+}
+
+type queryCacheKeyQuery struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	Interval      time.Duration   `json:"interval"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	Model         json.RawMessage `json:"model"`
+}
+
+// queryCacheKey builds a cache key from the normalized request: the datasource and its last update time,
+// and every query with its time range rounded down to step and volatile fields removed.
+func queryCacheKey(req *backend.QueryDataRequest, step time.Duration) (string, error) {
+	ds := req.PluginContext.DataSourceInstanceSettings
+
+	queries := make([]queryCacheKeyQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		model, err := normalizeQueryJSON(q.JSON)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize query %s: %w", q.RefID, err)
+		}
+		from, to := q.TimeRange.From, q.TimeRange.To
+		if step > 0 {
+			from, to = from.Truncate(step), to.Truncate(step)
+		}
+		queries = append(queries, queryCacheKeyQuery{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          from.UnixMilli(),
+			To:            to.UnixMilli(),
+			Model:         model,
+		})
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].RefID < queries[j].RefID })
+
+	b, err := json.Marshal(queries)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%d\x00", req.PluginContext.OrgID, ds.UID, ds.Updated.UnixNano())
+	_, _ = h.Write(b)
+	return queryCacheKeyPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// normalizeQueryJSON re-encodes the query model with sorted keys and without volatile fields.
+func normalizeQueryJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	model := map[string]any{}
+	if err := json.Unmarshal(raw, &model); err != nil {
+		return nil, err
+	}
+	for _, f := range volatileQueryFields {
+		delete(model, f)
+	}
+	return json.Marshal(model)
+}
+
+func resourceCacheKey(req *backend.CallResourceRequest) string {
+	ds := req.PluginContext.DataSourceInstanceSettings
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%s\x00%d\x00%s\x00%s\x00", req.PluginContext.OrgID, req.PluginContext.PluginID,
+		ds.UID, ds.Updated.UnixNano(), req.Path, req.URL)
+	_, _ = h.Write(req.Body)
+	return resourceCacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// skipCache returns true if the incoming request asked to skip the cache using the X-Cache-Skip header.
+func skipCache(ctx context.Context) bool {
+	reqCtx := contexthandler.FromContext(ctx)
+	return reqCtx != nil && reqCtx.SkipQueryCache
+}
+
+// hasAuthHeaders returns true if user credentials are forwarded to the datasource,
+// in which case responses may differ per user and must not be shared.
+func hasAuthHeaders[T string | []string](headers map[string]T) bool {
+	for k := range headers {
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasResponseErrors(resp *backend.QueryDataResponse) bool {
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func setCacheStatus(ctx context.Context, status string) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Resp == nil {
+		return
+	}
+	reqCtx.Resp.Header().Set(XCacheHeader, status)
 }
 
 var _ CachingService = &OSSCachingService{}
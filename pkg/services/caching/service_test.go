@@ -0,0 +1,210 @@
+package caching
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newTestService(t *testing.T) (*OSSCachingService, remotecache.FakeCacheStorage) {
+	t.Helper()
+	store := remotecache.NewFakeCacheStorage()
+	return &OSSCachingService{
+		cache: store,
+		settings: setting.CachingSettings{
+			Enabled:       true,
+			TTL:           time.Minute,
+			ResourcesTTL:  time.Minute,
+			TimeRangeStep: time.Minute,
+		},
+		log: log.NewNopLogger(),
+	}, store
+}
+
+func newQueryRequest(from time.Time, model string) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID: 1,
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				UID:      "ds-uid",
+				Updated:  time.Unix(1000, 0),
+				JSONData: []byte(`{}`),
+			},
+		},
+		Queries: []backend.DataQuery{
+			{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+				JSON:      []byte(model),
+			},
+		},
+	}
+}
+
+func newQueryResponse() *backend.QueryDataResponse {
+	resp := backend.NewQueryDataResponse()
+	resp.Responses["A"] = backend.DataResponse{
+		Frames: data.Frames{data.NewFrame("A", data.NewField("value", nil, []float64{1, 2, 3}))},
+	}
+	return resp
+}
+
+func TestHandleQueryRequest(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("disabled service does not cache", func(t *testing.T) {
+		s, _ := newTestService(t)
+		s.settings.Enabled = false
+
+		hit, cr := s.HandleQueryRequest(context.Background(), newQueryRequest(now, `{"expr":"up"}`))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+	})
+
+	t.Run("miss then hit", func(t *testing.T) {
+		s, store := newTestService(t)
+		req := newQueryRequest(now, `{"expr":"up"}`)
+
+		hit, cr := s.HandleQueryRequest(context.Background(), req)
+		require.False(t, hit)
+		require.NotNil(t, cr.UpdateCacheFn)
+		cr.UpdateCacheFn(context.Background(), newQueryResponse())
+		require.Len(t, store.Storage, 1)
+
+		hit, cr = s.HandleQueryRequest(context.Background(), req)
+		require.True(t, hit)
+		require.Contains(t, cr.Response.Responses, "A")
+		require.Len(t, cr.Response.Responses["A"].Frames, 1)
+	})
+
+	t.Run("time range is rounded to the configured step", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		hit, cr := s.HandleQueryRequest(context.Background(), newQueryRequest(now, `{"expr":"up"}`))
+		require.False(t, hit)
+		cr.UpdateCacheFn(context.Background(), newQueryResponse())
+
+		hit, _ = s.HandleQueryRequest(context.Background(), newQueryRequest(now.Add(20*time.Second), `{"expr":"up", "requestId":"Q101"}`))
+		require.True(t, hit)
+
+		hit, _ = s.HandleQueryRequest(context.Background(), newQueryRequest(now.Add(time.Minute), `{"expr":"up"}`))
+		require.False(t, hit)
+	})
+
+	t.Run("updating the datasource invalidates the cache", func(t *testing.T) {
+		s, _ := newTestService(t)
+		req := newQueryRequest(now, `{"expr":"up"}`)
+
+		_, cr := s.HandleQueryRequest(context.Background(), req)
+		cr.UpdateCacheFn(context.Background(), newQueryResponse())
+
+		req.PluginContext.DataSourceInstanceSettings.Updated = time.Unix(2000, 0)
+		hit, _ := s.HandleQueryRequest(context.Background(), req)
+		require.False(t, hit)
+	})
+
+	t.Run("responses with errors are not cached", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		_, cr := s.HandleQueryRequest(context.Background(), newQueryRequest(now, `{"expr":"up"}`))
+		resp := backend.NewQueryDataResponse()
+		resp.Responses["A"] = backend.DataResponse{Error: errors.New("boom")}
+		cr.UpdateCacheFn(context.Background(), resp)
+		require.Empty(t, store.Storage)
+	})
+
+	t.Run("forwarded credentials bypass the cache", func(t *testing.T) {
+		s, _ := newTestService(t)
+		req := newQueryRequest(now, `{"expr":"up"}`)
+		req.Headers = map[string]string{"Authorization": "Bearer token"}
+
+		hit, cr := s.HandleQueryRequest(context.Background(), req)
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+	})
+
+	t.Run("datasource can disable caching", func(t *testing.T) {
+		s, _ := newTestService(t)
+		req := newQueryRequest(now, `{"expr":"up"}`)
+		req.PluginContext.DataSourceInstanceSettings.JSONData = []byte(`{"queryCachingDisabled":true}`)
+
+		hit, cr := s.HandleQueryRequest(context.Background(), req)
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+	})
+}
+
+func TestQueryTTL(t *testing.T) {
+	s, _ := newTestService(t)
+	ds := &backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)}
+
+	ttl, ok := s.queryTTL(ds, nil)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, ttl)
+
+	ds.JSONData = []byte(`{"queryCachingTTL":30000}`)
+	ttl, ok = s.queryTTL(ds, nil)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, ttl)
+
+	ttl, ok = s.queryTTL(ds, []backend.DataQuery{{JSON: []byte(`{"queryCachingTTL":5000}`)}})
+	require.True(t, ok)
+	require.Equal(t, 5*time.Second, ttl)
+}
+
+func TestHandleResourceRequest(t *testing.T) {
+	newRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:    1,
+				PluginID: "prometheus",
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:     "ds-uid",
+					Updated: time.Unix(1000, 0),
+				},
+			},
+			Method: method,
+			Path:   "api/v1/labels",
+			URL:    "api/v1/labels?match=up",
+		}
+	}
+
+	t.Run("only GET requests are cached", func(t *testing.T) {
+		s, _ := newTestService(t)
+		hit, cr := s.HandleResourceRequest(context.Background(), newRequest(http.MethodPost))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+	})
+
+	t.Run("miss then hit", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		hit, cr := s.HandleResourceRequest(context.Background(), newRequest(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(context.Background(), &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`{"status":"success"}`)})
+
+		hit, cr = s.HandleResourceRequest(context.Background(), newRequest(http.MethodGet))
+		require.True(t, hit)
+		require.Equal(t, []byte(`{"status":"success"}`), cr.Response.Body)
+	})
+
+	t.Run("streamed responses are not cached", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		_, cr := s.HandleResourceRequest(context.Background(), newRequest(http.MethodGet))
+		cr.UpdateCacheFn(context.Background(), &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`a`)})
+		cr.UpdateCacheFn(context.Background(), &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`b`)})
+		require.Empty(t, store.Storage)
+	})
+}
@@ -20,6 +20,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	publicdashboardsStore "github.com/grafana/grafana/pkg/services/publicdashboards/database"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -98,10 +101,14 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
 		{"delete expired images", srv.deleteExpiredImages},
+		{"delete expired alert state history", srv.deleteExpiredAlertStateHistory},
+		{"delete expired alert notification history", srv.deleteExpiredAlertNotificationHistory},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"disable expired public dashboards", srv.disableExpiredPublicDashboards},
+		{"delete stale public dashboard access log", srv.deleteStalePublicDashboardAccessLog},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -222,6 +229,32 @@ func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredAlertStateHistory(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	retention := srv.Cfg.UnifiedAlerting.StateHistory.SQLRetention
+	if !srv.Cfg.UnifiedAlerting.IsEnabled() || retention <= 0 {
+		return
+	}
+	if rowsAffected, err := historian.DeleteExpiredSQLStateHistory(ctx, srv.store, time.Now().Add(-retention)); err != nil {
+		logger.Error("Failed to delete expired alert state history", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired alert state history", "rows affected", rowsAffected)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredAlertNotificationHistory(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	retention := srv.Cfg.UnifiedAlerting.NotificationHistory.Retention
+	if !srv.Cfg.UnifiedAlerting.IsEnabled() || retention <= 0 {
+		return
+	}
+	if rowsAffected, err := ngstore.DeleteExpiredNotificationHistory(ctx, srv.store, time.Now().Add(-retention)); err != nil {
+		logger.Error("Failed to delete expired alert notification history", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired alert notification history", "rows affected", rowsAffected)
+	}
+}
+
 func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	maxInviteLifetime := srv.Cfg.UserInviteMaxLifetime
@@ -279,3 +312,23 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) disableExpiredPublicDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if rowsAffected, err := publicdashboardsStore.DisableExpired(ctx, srv.store, time.Now()); err != nil {
+		logger.Error("Failed to disable expired public dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Disabled expired public dashboards", "rows affected", rowsAffected)
+	}
+}
+
+func (srv *CleanUpService) deleteStalePublicDashboardAccessLog(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	// Delete views of public dashboards from 90+ days ago
+	olderThan := time.Now().Add(-time.Hour * 24 * 90)
+	if rowsAffected, err := publicdashboardsStore.DeleteAccessLogBefore(ctx, srv.store, olderThan); err != nil {
+		logger.Error("Failed to delete stale public dashboard access log", "error", err.Error())
+	} else {
+		logger.Debug("Deleted stale public dashboard access log", "rows affected", rowsAffected)
+	}
+}
@@ -959,5 +959,11 @@ var (
 			RequiresDevMode: false,
 			Owner:           grafanaAuthnzSquad,
 		},
+		{
+			Name:        "sqlExpressions",
+			Description: "Enables using SQL queries over the results of other queries and expressions in server-side expressions",
+			Stage:       FeatureStageExperimental,
+			Owner:       grafanaObservabilityMetricsSquad,
+		},
 	}
 )
@@ -554,4 +554,8 @@ const (
 	// FlagAnnotationPermissionUpdate
 	// Separate annotation permissions from dashboard permissions to allow for more granular control.
 	FlagAnnotationPermissionUpdate = "annotationPermissionUpdate"
+
+	// FlagSqlExpressions
+	// Enables using SQL queries over the results of other queries and expressions in server-side expressions
+	FlagSqlExpressions = "sqlExpressions"
 )
@@ -3,6 +3,7 @@ package remotewrite
 import (
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strings"
 	"time"
 
@@ -190,6 +191,81 @@ func TimeSeriesFromFramesLabelsColumn(frames ...*data.Frame) []prompb.TimeSeries
 	return promTimeSeriesBatch
 }
 
+// InstantTimeSeriesFromFrames converts frames to slice of Prometheus TimeSeries named after
+// the given metric. Every numeric field produces a single sample at the provided time, using
+// the last non-null value of the field. Extra labels are added to every series and take
+// precedence over field labels.
+func InstantTimeSeriesFromFrames(name string, t time.Time, extraLabels map[string]string, frames ...*data.Frame) ([]prompb.TimeSeries, error) {
+	metricName, ok := sanitizeMetricName(name)
+	if !ok {
+		return nil, fmt.Errorf("invalid metric name: %q", name)
+	}
+
+	var entries = make(map[metricKey]prompb.TimeSeries)
+	var keys []metricKey // sorted keys.
+
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+
+			var value float64
+			var found bool
+			for i := field.Len() - 1; i >= 0; i-- {
+				val, ok := field.ConcreteAt(i)
+				if !ok {
+					continue
+				}
+				value, found = sampleValue(val)
+				if found {
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+
+			fieldLabels := make(map[string]string, len(field.Labels)+len(extraLabels))
+			for k, v := range field.Labels {
+				fieldLabels[k] = v
+			}
+			for k, v := range extraLabels {
+				fieldLabels[k] = v
+			}
+			labels := createLabels(fieldLabels)
+			sort.Slice(labels, func(i, j int) bool {
+				return labels[i].Name < labels[j].Name
+			})
+			key := makeMetricKey(metricName, labels)
+			if _, ok := entries[key]; ok {
+				return nil, fmt.Errorf("duplicate series for metric %q with labels %v", metricName, fieldLabels)
+			}
+
+			labels = append(labels, prompb.Label{
+				Name:  "__name__",
+				Value: metricName,
+			})
+			entries[key] = prompb.TimeSeries{
+				Labels: labels,
+				Samples: []prompb.Sample{{
+					// Timestamp is int milliseconds for remote write.
+					Timestamp: toSampleTime(t),
+					Value:     value,
+				}},
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	var promTimeSeriesBatch = make([]prompb.TimeSeries, 0, len(entries))
+	for _, key := range keys {
+		promTimeSeriesBatch = append(promTimeSeriesBatch, entries[key])
+	}
+
+	return promTimeSeriesBatch, nil
+}
+
 func timeFieldIndex(frame *data.Frame) (int, bool) {
 	timeFieldIndex := -1
 	for i, field := range frame.Fields {
@@ -5,7 +5,10 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestTsFromFrames(t *testing.T) {
@@ -77,6 +80,37 @@ func TestTsFromFramesMultipleFrames(t *testing.T) {
 	require.Equal(t, 4.0, ts[1].Samples[1].Value)
 }
 
+func TestInstantTsFromFrames(t *testing.T) {
+	now := time.Now()
+	number := data.NewFrame("",
+		data.NewField("", map[string]string{"instance": "a"}, []*float64{util.Pointer(1.0)}),
+	)
+	series := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{now.Add(-time.Second), now}),
+		data.NewField("value", map[string]string{"instance": "b"}, []*float64{util.Pointer(2.0), nil}),
+	)
+	ts, err := InstantTimeSeriesFromFrames("test_metric", now, map[string]string{"instance": "override", "rule": "test"}, number)
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	require.Equal(t, []prompb.Label{
+		{Name: "instance", Value: "override"},
+		{Name: "rule", Value: "test"},
+		{Name: "__name__", Value: "test_metric"},
+	}, ts[0].Labels)
+
+	ts, err = InstantTimeSeriesFromFrames("test_metric", now, nil, number, series)
+	require.NoError(t, err)
+	require.Len(t, ts, 2)
+	require.Equal(t, []prompb.Sample{{Timestamp: toSampleTime(now), Value: 1.0}}, ts[0].Samples)
+	require.Equal(t, []prompb.Sample{{Timestamp: toSampleTime(now), Value: 2.0}}, ts[1].Samples)
+
+	_, err = InstantTimeSeriesFromFrames("test_metric", now, map[string]string{"instance": "override"}, number, series)
+	require.Error(t, err)
+
+	_, err = InstantTimeSeriesFromFrames("", now, nil, number)
+	require.Error(t, err)
+}
+
 func TestSerialize(t *testing.T) {
 	frame := data.NewFrame("test",
 		data.NewField("time", nil, []time.Time{time.Now(), time.Now().Add(time.Second)}),
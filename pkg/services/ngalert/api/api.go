@@ -62,6 +62,7 @@ type API struct {
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
 	Historian            Historian
+	NotificationHistory  NotificationHistory
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 
@@ -113,6 +114,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			amConfigStore:   api.AlertingStore,
+			ruleStore:       api.RuleStore,
+			stateManager:    api.StateManager,
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
@@ -136,8 +140,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
-		logger: logger,
-		hist:   api.Historian,
+		logger:              logger,
+		hist:                api.Historian,
+		notificationHistory: api.NotificationHistory,
 	}), m)
 }
 
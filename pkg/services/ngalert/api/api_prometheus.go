@@ -326,6 +326,11 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 			Type:           apiv1.RuleTypeAlerting,
 			LastEvaluation: time.Time{},
 		}
+		if rule.IsRecordingRule() {
+			// recording rules do not fire, so they have no state and are not counted in the totals
+			newRule.Type = apiv1.RuleTypeRecording
+			alertingRule.State = ""
+		}
 
 		states := srv.manager.GetStatesForRuleUID(rule.OrgID, rule.UID)
 		totals := make(map[string]int64)
@@ -342,6 +347,9 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 			if alertState.Error != nil && rule.ExecErrState != ngmodels.ErrorErrState {
 				totals["error"] += 1
 			}
+			if alertState.StateReason == ngmodels.StateReasonSuppressed {
+				totals["suppressed"] += 1
+			}
 			alert := apimodels.Alert{
 				Labels:      alertState.GetLabels(labelOptions...),
 				Annotations: alertState.Annotations,
@@ -396,6 +404,9 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 			if alertState.Error != nil && rule.ExecErrState != ngmodels.ErrorErrState {
 				totalsFiltered["error"] += 1
 			}
+			if alertState.StateReason == ngmodels.StateReasonSuppressed {
+				totalsFiltered["suppressed"] += 1
+			}
 
 			alertingRule.Alerts = append(alertingRule.Alerts, alert)
 		}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
@@ -401,16 +402,19 @@ func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, name
 	rules.SortByGroupIndex()
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(rules))
 	var interval time.Duration
+	var activeTimeIntervals []timeinterval.TimeInterval
 	if len(rules) > 0 {
 		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+		activeTimeIntervals = rules[0].GroupActiveTimeIntervals
 	}
 	for _, r := range rules {
 		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, namespaceID, provenanceRecords))
 	}
 	return apimodels.GettableRuleGroupConfig{
-		Name:     groupName,
-		Interval: model.Duration(interval),
-		Rules:    ruleNodes,
+		Name:                groupName,
+		Interval:            model.Duration(interval),
+		Rules:               ruleNodes,
+		ActiveTimeIntervals: activeTimeIntervals,
 	}
 }
 
@@ -421,22 +425,25 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 	}
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
-			ID:              r.ID,
-			OrgID:           r.OrgID,
-			Title:           r.Title,
-			Condition:       r.Condition,
-			Data:            ApiAlertQueriesFromAlertQueries(r.Data),
-			Updated:         r.Updated,
-			IntervalSeconds: r.IntervalSeconds,
-			Version:         r.Version,
-			UID:             r.UID,
-			NamespaceUID:    r.NamespaceUID,
-			NamespaceID:     namespaceID,
-			RuleGroup:       r.RuleGroup,
-			NoDataState:     apimodels.NoDataState(r.NoDataState),
-			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
-			Provenance:      apimodels.Provenance(provenance),
-			IsPaused:        r.IsPaused,
+			ID:                  r.ID,
+			OrgID:               r.OrgID,
+			Title:               r.Title,
+			Condition:           r.Condition,
+			Data:                ApiAlertQueriesFromAlertQueries(r.Data),
+			Updated:             r.Updated,
+			IntervalSeconds:     r.IntervalSeconds,
+			Version:             r.Version,
+			UID:                 r.UID,
+			NamespaceUID:        r.NamespaceUID,
+			NamespaceID:         namespaceID,
+			RuleGroup:           r.RuleGroup,
+			NoDataState:         apimodels.NoDataState(r.NoDataState),
+			ExecErrState:        apimodels.ExecutionErrorState(r.ExecErrState),
+			Provenance:          apimodels.Provenance(provenance),
+			IsPaused:            r.IsPaused,
+			Record:              ApiRecordFromModelRecord(r.Record),
+			DependsOn:           ApiRuleDependenciesFromModelRuleDependencies(r.DependsOn),
+			ActiveTimeIntervals: r.ActiveTimeIntervals,
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -445,6 +452,10 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -17,9 +19,14 @@ type Historian interface {
 	Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error)
 }
 
+type NotificationHistory interface {
+	GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+}
+
 type HistorySrv struct {
-	logger log.Logger
-	hist   Historian
+	logger              log.Logger
+	hist                Historian
+	notificationHistory NotificationHistory
 }
 
 const labelQueryPrefix = "labels_"
@@ -56,3 +63,50 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 	}
 	return response.JSON(http.StatusOK, frame)
 }
+
+func (srv *HistorySrv) RouteQueryNotificationHistory(c *contextmodel.ReqContext) response.Response {
+	status := models.NotificationStatus(c.Query("status"))
+	if status != "" && status != models.NotificationStatusSuccess && status != models.NotificationStatusFailure {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be either %q or %q", status, models.NotificationStatusSuccess, models.NotificationStatusFailure), "")
+	}
+
+	query := models.NotificationHistoryQuery{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		Receiver:         c.Query("receiver"),
+		Integration:      c.Query("integration"),
+		GroupKey:         c.Query("groupKey"),
+		AlertFingerprint: c.Query("fingerprint"),
+		Status:           status,
+		Limit:            c.QueryInt("limit"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+
+	entries, err := srv.notificationHistory.GetNotificationHistory(c.Req.Context(), &query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to query notification history")
+	}
+
+	result := apimodels.NotificationHistory{
+		Results: make([]apimodels.NotificationHistoryEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		result.Results = append(result.Results, apimodels.NotificationHistoryEntry{
+			Receiver:          e.Receiver,
+			Integration:       e.Integration,
+			IntegrationIndex:  e.IntegrationIndex,
+			GroupKey:          e.GroupKey,
+			AlertFingerprints: e.AlertFingerprints,
+			Status:            string(e.Status),
+			Error:             e.Error,
+			DurationMs:        e.Duration.Milliseconds(),
+			Retry:             e.Retry,
+			SentAt:            e.SentAt,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
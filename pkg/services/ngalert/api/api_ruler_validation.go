@@ -67,34 +67,59 @@ func validateRuleNode(
 		}
 	}
 
+	record := ModelRecordFromApiRecord(ruleNode.GrafanaManagedAlert.Record)
+	if record != nil && !cfg.RecordingRules.Enabled {
+		return nil, fmt.Errorf("%w: recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
-			if ruleNode.GrafanaManagedAlert.Condition != "" {
+			if ruleNode.GrafanaManagedAlert.Condition != "" || record != nil {
 				return nil, fmt.Errorf("%w: query is not specified by condition is. You must specify both query and condition to update existing alert rule", ngmodels.ErrAlertRuleFailedValidation)
 			}
 		} else {
 			return nil, fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
 		}
 	} else {
-		err = validateCondition(ruleNode.GrafanaManagedAlert.Condition, ruleNode.GrafanaManagedAlert.Data)
+		// the condition of a recording rule is the query or expression it records
+		condition := ruleNode.GrafanaManagedAlert.Condition
+		if record != nil {
+			condition = record.From
+		}
+		err = validateCondition(condition, ruleNode.GrafanaManagedAlert.Data)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
 		}
 	}
 
 	queries := AlertQueriesFromApiAlertQueries(ruleNode.GrafanaManagedAlert.Data)
+	if record != nil && len(queries) > 0 {
+		if err := record.Validate(queries); err != nil {
+			return nil, err
+		}
+	}
 
 	newAlertRule := ngmodels.AlertRule{
-		OrgID:           orgId,
-		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
-		Data:            queries,
-		UID:             ruleNode.GrafanaManagedAlert.UID,
-		IntervalSeconds: intervalSeconds,
-		NamespaceUID:    namespace.UID,
-		RuleGroup:       groupName,
-		NoDataState:     noDataState,
-		ExecErrState:    errorState,
+		OrgID:               orgId,
+		Title:               ruleNode.GrafanaManagedAlert.Title,
+		Condition:           ruleNode.GrafanaManagedAlert.Condition,
+		Data:                queries,
+		UID:                 ruleNode.GrafanaManagedAlert.UID,
+		IntervalSeconds:     intervalSeconds,
+		NamespaceUID:        namespace.UID,
+		RuleGroup:           groupName,
+		NoDataState:         noDataState,
+		ExecErrState:        errorState,
+		Record:              record,
+		DependsOn:           ModelRuleDependenciesFromApiRuleDependencies(ruleNode.GrafanaManagedAlert.DependsOn),
+		ActiveTimeIntervals: ruleNode.GrafanaManagedAlert.ActiveTimeIntervals,
+	}
+
+	if record != nil && len(newAlertRule.DependsOn) > 0 {
+		return nil, fmt.Errorf("%w: recording rules cannot depend on other rules", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if err := newAlertRule.ValidateDependencies(); err != nil {
+		return nil, err
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -102,6 +127,14 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringForInterval(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && newAlertRule.KeepFiringFor > 0 {
+		return nil, fmt.Errorf("%w: field `keep_firing_for` cannot be set for recording rules", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -173,6 +206,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil // if it's a new rule, use the 0 as the default
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
@@ -216,6 +264,8 @@ func validateRuleGroup(
 			uids[rule.UID] = idx
 		}
 
+		rule.GroupActiveTimeIntervals = ruleGroupConfig.ActiveTimeIntervals
+
 		var hasPause, isPaused bool
 		original := ruleGroupConfig.Rules[idx]
 		if alert := original.GrafanaManagedAlert; alert != nil {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/rand"
@@ -223,6 +224,27 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.True(t, alert.HasPause)
 		}
 	})
+
+	t.Run("should keep group active time intervals apart from the ones of the rules", func(t *testing.T) {
+		groupIntervals := []timeinterval.TimeInterval{
+			{Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}}},
+		}
+		ruleIntervals := []timeinterval.TimeInterval{
+			{Times: []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 120}}},
+		}
+		withIntervals := validRule()
+		withIntervals.GrafanaManagedAlert.ActiveTimeIntervals = ruleIntervals
+		g := validGroup(cfg, validRule(), withIntervals)
+		g.ActiveTimeIntervals = groupIntervals
+		alerts, err := validateRuleGroup(&g, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		for _, alert := range alerts {
+			require.Equal(t, groupIntervals, alert.GroupActiveTimeIntervals)
+		}
+		require.Empty(t, alerts[0].ActiveTimeIntervals)
+		require.Equal(t, ruleIntervals, alerts[1].ActiveTimeIntervals)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -408,6 +430,48 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "", alert.RuleGroup)
 	})
+
+	t.Run("converts keep_firing_for", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.UID = ""
+		keepFiringFor := model.Duration(5 * time.Minute)
+		r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+		alert, err := validateRuleNode(&r, name, interval, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+	})
+
+	t.Run("converts dependencies", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.UID = ""
+		r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+			{RuleUID: "upstream"},
+			{NamespaceUID: "upstream-folder", RuleGroup: "upstream-group"},
+		}
+		alert, err := validateRuleNode(&r, name, interval, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleDependency{
+			{RuleUID: "upstream"},
+			{NamespaceUID: "upstream-folder", RuleGroup: "upstream-group"},
+		}, alert.DependsOn)
+	})
+
+	t.Run("converts recording rule", func(t *testing.T) {
+		cfg := *cfg
+		cfg.RecordingRules.Enabled = true
+		r := validRule()
+		r.GrafanaManagedAlert.UID = ""
+		r.GrafanaManagedAlert.Condition = ""
+		r.GrafanaManagedAlert.Record = &apimodels.Record{
+			Metric: "test_metric",
+			From:   "A",
+		}
+		alert, err := validateRuleNode(&r, name, interval, orgId, folder, &cfg)
+		require.NoError(t, err)
+		require.True(t, alert.IsRecordingRule())
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, alert.Record)
+		require.Equal(t, "A", alert.GetEvalCondition().Condition)
+	})
 }
 
 func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
@@ -543,6 +607,41 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rules are disabled",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+				return &r
+			},
+		},
+		{
+			name: "fail if dependency references both a rule and a rule group",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: "upstream", NamespaceUID: "upstream-folder", RuleGroup: "upstream-group"},
+				}
+				return &r
+			},
+		},
+		{
+			name: "fail if dependency references a rule group without namespace",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{{RuleGroup: "upstream-group"}}
+				return &r
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -566,6 +665,46 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNodeFailures_Recording(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = true
+
+	testCases := []struct {
+		name   string
+		record *apimodels.Record
+	}{
+		{
+			name:   "fail if metric name is invalid",
+			record: &apimodels.Record{Metric: "invalid metric", From: "A"},
+		},
+		{
+			name:   "fail if metric name is empty",
+			record: &apimodels.Record{Metric: "", From: "A"},
+		},
+		{
+			name:   "fail if From is empty",
+			record: &apimodels.Record{Metric: "test_metric", From: ""},
+		},
+		{
+			name:   "fail if From does not exist",
+			record: &apimodels.Record{Metric: "test_metric", From: "B"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := validRule()
+			r.GrafanaManagedAlert.UID = ""
+			r.GrafanaManagedAlert.Record = testCase.record
+
+			_, err := validateRuleNode(&r, "", cfg.BaseInterval, orgId, folder, cfg)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+}
+
 func TestValidateRuleNode_UID(t *testing.T) {
 	orgId := rand.Int63()
 	folder := randFolder()
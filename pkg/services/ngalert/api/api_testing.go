@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,9 +13,11 @@ import (
 	"github.com/grafana/alerting/models"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -26,7 +29,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -39,11 +44,18 @@ type TestingApiSrv struct {
 	evaluator       eval.EvaluatorFactory
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
+	amConfigStore   AlertingStore
+	ruleStore       RuleStore
+	stateManager    statePreviewer
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 }
 
+type statePreviewer interface {
+	PreviewEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *ngmodels.AlertRule, results eval.Results, extraLabels data.Labels) []state.StateTransition
+}
+
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
 // as true as possible to what would be generated by the ruler except that the resulting alerts are not filtered to
 // only Resolved / Firing and ready to send.
@@ -183,6 +195,124 @@ func (srv TestingApiSrv) RouteEvalQueries(c *contextmodel.ReqContext, cmd apimod
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
 
+// RouteExplainRule evaluates a stored rule and returns the results of every query and expression of the rule, the
+// evaluation results and the state transitions that the state manager would apply. Nothing is persisted.
+func (srv TestingApiSrv) RouteExplainRule(c *contextmodel.ReqContext, cmd apimodels.ExplainRulePayload, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	rules, err := srv.ruleStore.GetAlertRulesGroupByRuleUID(ctx, &ngmodels.GetAlertRulesGroupByRuleUIDQuery{
+		UID:   ruleUID,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule")
+	}
+	var rule *ngmodels.AlertRule
+	for _, r := range rules {
+		if r.UID == ruleUID {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+	if !authorizeDatasourceAccessForRule(rule, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(evaluator)
+	}) {
+		return errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
+	}
+	namespace, err := srv.ruleStore.GetNamespaceByUID(ctx, rule.NamespaceUID, rule.OrgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	now := cmd.Now
+	if now.IsZero() {
+		now = timeNow()
+	}
+
+	ruleCtx := ngmodels.WithRuleKey(ctx, rule.GetKey())
+	evaluator, err := srv.evaluator.Create(eval.NewContext(ruleCtx, c.SignedInUser), rule.GetEvalCondition())
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+	}
+
+	statsCtx, stats := expr.WithExecutionStats(ruleCtx)
+	raw, err := evaluator.EvaluateRaw(statsCtx, now)
+	var results eval.Results
+	if err != nil {
+		results = eval.Results{eval.NewResultFromError(err, now, 0)}
+	} else {
+		results = eval.ResultsFromRawResponse(rule.GetEvalCondition(), raw, now)
+	}
+
+	extraLabels := state.GetRuleExtraLabels(rule, namespace.Title, !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel))
+	transitions := srv.stateManager.PreviewEvalResults(ruleCtx, now, rule, results, extraLabels)
+
+	return response.JSON(http.StatusOK, explainRuleResponse(now, stats.Nodes(), raw, results, transitions))
+}
+
+func explainRuleResponse(now time.Time, nodes []expr.NodeStats, raw *backend.QueryDataResponse, results eval.Results, transitions []state.StateTransition) apimodels.ExplainRuleResponse {
+	resp := apimodels.ExplainRuleResponse{
+		EvaluatedAt: now,
+		Nodes:       make([]apimodels.ExplainNode, 0, len(nodes)),
+		Instances:   make([]apimodels.ExplainInstance, 0, len(results)),
+		Transitions: make([]apimodels.ExplainTransition, 0, len(transitions)),
+	}
+
+	for _, n := range nodes {
+		node := apimodels.ExplainNode{
+			RefID:          n.RefID,
+			Type:           "datasource",
+			DatasourceUID:  n.DatasourceUID,
+			DatasourceType: n.DatasourceType,
+			DurationMs:     n.Duration.Milliseconds(),
+		}
+		switch n.NodeType {
+		case expr.TypeCMDNode:
+			node.Type = n.CommandType.String()
+		case expr.TypeMLNode:
+			node.Type = "ml"
+		}
+		if raw != nil {
+			if r, ok := raw.Responses[n.RefID]; ok {
+				node.Frames = r.Frames
+				if r.Error != nil {
+					node.Error = r.Error.Error()
+				}
+			}
+		}
+		resp.Nodes = append(resp.Nodes, node)
+	}
+
+	for _, r := range results {
+		instance := apimodels.ExplainInstance{
+			Labels: r.Instance,
+			State:  r.State.String(),
+		}
+		if r.Error != nil {
+			instance.Error = r.Error.Error()
+		}
+		if len(r.Values) > 0 {
+			instance.Values = make(map[string]*float64, len(r.Values))
+			for refID, v := range r.Values {
+				instance.Values[refID] = v.Value
+			}
+		}
+		resp.Instances = append(resp.Instances, instance)
+	}
+
+	for _, t := range transitions {
+		resp.Transitions = append(resp.Transitions, apimodels.ExplainTransition{
+			Labels:        t.Labels,
+			PreviousState: t.PreviousFormatted(),
+			State:         t.Formatted(),
+			Changed:       t.Changed(),
+		})
+	}
+	return resp
+}
+
 func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
 	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
 		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
@@ -237,6 +367,10 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		Labels:          cmd.Labels,
 	}
 
+	if cmd.Notifications {
+		return srv.backtestNotifications(c, rule, cmd.From, cmd.To, cmd.NamespaceUID)
+	}
+
 	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
 	if err != nil {
 		if errors.Is(err, backtesting.ErrInvalidInputData) {
@@ -251,3 +385,72 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+func (srv TestingApiSrv) backtestNotifications(c *contextmodel.ReqContext, rule *ngmodels.AlertRule, from, to time.Time, namespaceUID string) response.Response {
+	// the alerts get the same extra labels as the alerts of a stored rule, because notification policies often match them
+	var folderTitle string
+	if namespaceUID != "" {
+		namespace, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), namespaceUID, rule.OrgID, c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+		rule.NamespaceUID = namespace.UID
+		folderTitle = namespace.Title
+	}
+	includeFolder := namespaceUID != "" && !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel)
+	extraLabels := state.GetRuleExtraLabels(rule, folderTitle, includeFolder)
+
+	amConfig, err := srv.getAlertmanagerConfig(c)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "Failed to load the notification policy tree")
+	}
+
+	result, notifications, err := srv.backtesting.TestNotifications(c.Req.Context(), c.SignedInUser, rule, from, to, extraLabels, amConfig.AlertmanagerConfig.Config)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	states, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return ErrResp(500, err, "Failed to convert frame to JSON")
+	}
+	body := apimodels.BacktestNotificationsResult{
+		States:        states,
+		Notifications: make([]apimodels.BacktestNotification, 0, len(notifications)),
+	}
+	for _, n := range notifications {
+		notification := apimodels.BacktestNotification{
+			Time:        n.Time,
+			Receiver:    n.Receiver,
+			GroupLabels: n.GroupLabels,
+			Firing:      make([]map[string]string, 0, len(n.Firing)),
+			Resolved:    make([]map[string]string, 0, len(n.Resolved)),
+		}
+		for _, l := range n.Firing {
+			notification.Firing = append(notification.Firing, l)
+		}
+		for _, l := range n.Resolved {
+			notification.Resolved = append(notification.Resolved, l)
+		}
+		body.Notifications = append(body.Notifications, notification)
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// getAlertmanagerConfig returns the latest Alertmanager configuration of the organization, or the default
+// configuration if the organization does not have one.
+func (srv TestingApiSrv) getAlertmanagerConfig(c *contextmodel.ReqContext) (*apimodels.PostableUserConfig, error) {
+	raw := srv.cfg.DefaultConfiguration
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: c.SignedInUser.GetOrgID()}
+	amConfig, err := srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), &query)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return nil, err
+	}
+	if amConfig != nil {
+		raw = amConfig.AlertmanagerConfiguration
+	}
+	return notifier.Load([]byte(raw))
+}
@@ -1,12 +1,16 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
 
+	alertingModels "github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -16,11 +20,17 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -269,6 +279,91 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteExplainRule(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	rule := models.AlertRuleGen(models.WithOrgID(1))()
+	permissions := make([]accesscontrol.Permission, 0, len(rule.Data))
+	for _, q := range rule.Data {
+		permissions = append(permissions, accesscontrol.Permission{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(q.DatasourceUID)})
+	}
+
+	createSrv := func(ac *acMock.Mock, evaluator *eval_mocks.ConditionEvaluatorMock, previewer *fakeStatePreviewer) *TestingApiSrv {
+		ruleStore := ngfakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), rule)
+		srv := createTestingApiSrv(t, nil, ac, eval_mocks.NewEvaluatorFactory(evaluator))
+		srv.ruleStore = ruleStore
+		srv.stateManager = previewer
+		return srv
+	}
+
+	t.Run("should return 404 if rule does not exist", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(permissions), &eval_mocks.ConditionEvaluatorMock{}, &fakeStatePreviewer{})
+
+		response := srv.RouteExplainRule(rc, definitions.ExplainRulePayload{}, "unknown")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 401 if user cannot query a data source", func(t *testing.T) {
+		srv := createSrv(acMock.New(), &eval_mocks.ConditionEvaluatorMock{}, &fakeStatePreviewer{})
+
+		response := srv.RouteExplainRule(rc, definitions.ExplainRulePayload{}, rule.UID)
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return results of nodes and state transitions", func(t *testing.T) {
+		now := time.Now()
+		value := 1.0
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, now).Return(&backend.QueryDataResponse{
+			Responses: map[string]backend.DataResponse{
+				rule.Condition: {Frames: data.Frames{data.NewFrame("", data.NewField("", data.Labels{"a": "b"}, []*float64{&value}))}},
+			},
+		}, nil)
+		previewer := &fakeStatePreviewer{
+			transitions: []state.StateTransition{{
+				PreviousState: eval.Normal,
+				State:         &state.State{State: eval.Alerting, Labels: data.Labels{"a": "b"}},
+			}},
+		}
+		srv := createSrv(acMock.New().WithPermissions(permissions), evaluator, previewer)
+
+		response := srv.RouteExplainRule(rc, definitions.ExplainRulePayload{Now: now}, rule.UID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var body definitions.ExplainRuleResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &body))
+		require.Equal(t, []definitions.ExplainTransition{
+			{Labels: map[string]string{"a": "b"}, PreviousState: "Normal", State: "Alerting", Changed: true},
+		}, body.Transitions)
+		require.Len(t, body.Instances, 1)
+		require.Equal(t, "Alerting", body.Instances[0].State)
+
+		require.Equal(t, now, previewer.evaluatedAt)
+		require.Equal(t, rule.UID, previewer.extraLabels[alertingModels.RuleUIDLabel])
+	})
+}
+
+type fakeStatePreviewer struct {
+	transitions []state.StateTransition
+	evaluatedAt time.Time
+	extraLabels data.Labels
+}
+
+func (f *fakeStatePreviewer) PreviewEvalResults(_ context.Context, evaluatedAt time.Time, _ *models.AlertRule, _ eval.Results, extraLabels data.Labels) []state.StateTransition {
+	f.evaluatedAt = evaluatedAt
+	f.extraLabels = extraLabels
+	return f.transitions
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -282,3 +377,83 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		tracer:          tracing.InitializeTracerForTest(),
 	}
 }
+
+func TestBacktestAlertRuleNotifications(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	ruleStore := ngfakes.NewRuleStore(t)
+	ruleStore.Folders[1] = []*folder.Folder{{UID: "folder-uid", Title: "folder-title"}}
+	amConfigStore := &fakeAlertmanagerConfigStore{config: `{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "default",
+			"group_by": ["alertname"],
+			"routes": [{"receiver": "folder", "object_matchers": [["grafana_folder", "=", "folder-title"]]}]
+		},
+		"receivers": [{"name": "default"}, {"name": "folder"}]
+	}
+}`}
+	ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("__data__")},
+	})
+	srv := createTestingApiSrv(t, nil, ac, eval_mocks.NewEvaluatorFactory(&eval_mocks.ConditionEvaluatorMock{}))
+	srv.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+	srv.featureManager = featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting)
+	srv.backtesting = backtesting.NewEngine(nil, srv.evaluator, srv.tracer)
+	srv.ruleStore = ruleStore
+	srv.amConfigStore = amConfigStore
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 0, 10)
+	values := make([]float64, 0, 10)
+	for i := 0; i < cap(times); i++ {
+		times = append(times, from.Add(time.Duration(i)*time.Minute))
+		values = append(values, 1)
+	}
+	frame, err := json.Marshal(map[string]any{"data": data.NewFrame("", data.NewField("time", nil, times), data.NewField("value", nil, values))})
+	require.NoError(t, err)
+	cmd := definitions.BacktestConfig{
+		From:      from,
+		To:        from.Add(10 * time.Minute),
+		Interval:  prommodel.Duration(time.Minute),
+		Condition: "A",
+		Data: []definitions.AlertQuery{{
+			RefID:         "A",
+			DatasourceUID: "__data__",
+			Model:         frame,
+		}},
+		Title:         "test",
+		NoDataState:   definitions.NoData,
+		Notifications: true,
+		NamespaceUID:  "folder-uid",
+	}
+
+	response := srv.BacktestAlertRule(rc, cmd)
+
+	require.Equal(t, http.StatusOK, response.Status(), string(response.Body()))
+	var body definitions.BacktestNotificationsResult
+	require.NoError(t, json.Unmarshal(response.Body(), &body))
+	require.NotEmpty(t, body.States)
+	require.Len(t, body.Notifications, 1)
+	notification := body.Notifications[0]
+	require.Equal(t, from.Add(30*time.Second), notification.Time)
+	require.Equal(t, "folder", notification.Receiver)
+	require.Equal(t, map[string]string{"alertname": "test"}, notification.GroupLabels)
+	require.Len(t, notification.Firing, 1)
+	require.Equal(t, "folder-title", notification.Firing[0][models.FolderTitleLabel])
+	require.Empty(t, notification.Resolved)
+}
+
+type fakeAlertmanagerConfigStore struct {
+	config string
+}
+
+func (f *fakeAlertmanagerConfigStore) GetLatestAlertmanagerConfiguration(_ context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (*models.AlertConfiguration, error) {
+	return &models.AlertConfiguration{OrgID: query.OrgID, AlertmanagerConfiguration: f.config}, nil
+}
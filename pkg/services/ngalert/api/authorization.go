@@ -55,6 +55,10 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodGet + "/api/v1/rules/history":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
+	// Grafana notification history paths
+	case http.MethodGet + "/api/v1/notifications/history":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+
 	// Grafana, Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
@@ -70,6 +74,9 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/explain/{RuleUID}":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}":
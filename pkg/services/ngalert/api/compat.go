@@ -14,43 +14,51 @@ import (
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:                  a.ID,
+		UID:                 a.UID,
+		OrgID:               a.OrgID,
+		NamespaceUID:        a.FolderUID,
+		RuleGroup:           a.RuleGroup,
+		Title:               a.Title,
+		Condition:           a.Condition,
+		Data:                AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:             a.Updated,
+		NoDataState:         models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:        models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:                 time.Duration(a.For),
+		KeepFiringFor:       time.Duration(a.KeepFiringFor),
+		Annotations:         a.Annotations,
+		Labels:              a.Labels,
+		IsPaused:            a.IsPaused,
+		Record:              ModelRecordFromApiRecord(a.Record),
+		DependsOn:           ModelRuleDependenciesFromApiRuleDependencies(a.DependsOn),
+		ActiveTimeIntervals: a.ActiveTimeIntervals,
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:                  rule.ID,
+		UID:                 rule.UID,
+		OrgID:               rule.OrgID,
+		FolderUID:           rule.NamespaceUID,
+		RuleGroup:           rule.RuleGroup,
+		Title:               rule.Title,
+		For:                 model.Duration(rule.For),
+		KeepFiringFor:       model.Duration(rule.KeepFiringFor),
+		Condition:           rule.Condition,
+		Data:                ApiAlertQueriesFromAlertQueries(rule.Data),
+		Updated:             rule.Updated,
+		NoDataState:         definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:        definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:         rule.Annotations,
+		Labels:              rule.Labels,
+		Provenance:          definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:            rule.IsPaused,
+		Record:              ApiRecordFromModelRecord(rule.Record),
+		DependsOn:           ApiRuleDependenciesFromModelRuleDependencies(rule.DependsOn),
+		ActiveTimeIntervals: rule.ActiveTimeIntervals,
 	}
 }
 
@@ -63,6 +71,60 @@ func ProvisionedAlertRuleFromAlertRules(rules []*models.AlertRule, provenances m
 	return result
 }
 
+// ModelRecordFromApiRecord converts definitions.Record to models.Record
+func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// ApiRecordFromModelRecord converts models.Record to definitions.Record
+func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
+	if r == nil {
+		return nil
+	}
+	return &definitions.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// ModelRuleDependenciesFromApiRuleDependencies converts a collection of definitions.RuleDependency to a collection of models.RuleDependency
+func ModelRuleDependenciesFromApiRuleDependencies(deps []definitions.RuleDependency) []models.RuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]models.RuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, models.RuleDependency{
+			RuleUID:      d.RuleUID,
+			NamespaceUID: d.NamespaceUID,
+			RuleGroup:    d.RuleGroup,
+		})
+	}
+	return result
+}
+
+// ApiRuleDependenciesFromModelRuleDependencies converts a collection of models.RuleDependency to a collection of definitions.RuleDependency
+func ApiRuleDependenciesFromModelRuleDependencies(deps []models.RuleDependency) []definitions.RuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.RuleDependency{
+			RuleUID:      d.RuleUID,
+			NamespaceUID: d.NamespaceUID,
+			RuleGroup:    d.RuleGroup,
+		})
+	}
+	return result
+}
+
 // AlertQueriesFromApiAlertQueries converts a collection of definitions.AlertQuery to collection of models.AlertQuery
 func AlertQueriesFromApiAlertQueries(queries []definitions.AlertQuery) []models.AlertQuery {
 	result := make([]models.AlertQuery, 0, len(queries))
@@ -101,9 +163,10 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
-		Title:     a.Title,
-		FolderUID: a.FolderUID,
-		Interval:  a.Interval,
+		Title:               a.Title,
+		FolderUID:           a.FolderUID,
+		Interval:            a.Interval,
+		ActiveTimeIntervals: a.ActiveTimeIntervals,
 	}
 	for i := range a.Rules {
 		converted, err := AlertRuleFromProvisionedAlertRule(a.Rules[i])
@@ -121,10 +184,11 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		rules = append(rules, ProvisionedAlertRuleFromAlertRule(d.Rules[i], d.Provenance))
 	}
 	return definitions.AlertRuleGroup{
-		Title:     d.Title,
-		FolderUID: d.FolderUID,
-		Interval:  d.Interval,
-		Rules:     rules,
+		Title:               d.Title,
+		FolderUID:           d.FolderUID,
+		Interval:            d.Interval,
+		Rules:               rules,
+		ActiveTimeIntervals: d.ActiveTimeIntervals,
 	}
 }
 
@@ -152,13 +216,14 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		rules = append(rules, alert)
 	}
 	return definitions.AlertRuleGroupExport{
-		OrgID:           d.OrgID,
-		Name:            d.Title,
-		Folder:          d.FolderTitle,
-		FolderUID:       d.FolderUID,
-		Interval:        model.Duration(time.Duration(d.Interval) * time.Second),
-		IntervalSeconds: d.Interval,
-		Rules:           rules,
+		OrgID:               d.OrgID,
+		Name:                d.Title,
+		Folder:              d.FolderTitle,
+		FolderUID:           d.FolderUID,
+		Interval:            model.Duration(time.Duration(d.Interval) * time.Second),
+		IntervalSeconds:     d.Interval,
+		Rules:               rules,
+		ActiveTimeIntervals: d.ActiveTimeIntervals,
 	}, nil
 }
 
@@ -174,20 +239,27 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	}
 
 	result := definitions.AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: rule.DashboardUID,
-		PanelID:      rule.PanelID,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState),
-		IsPaused:     rule.IsPaused,
+		UID:                 rule.UID,
+		Title:               rule.Title,
+		For:                 model.Duration(rule.For),
+		Condition:           rule.Condition,
+		Data:                data,
+		DashboardUID:        rule.DashboardUID,
+		PanelID:             rule.PanelID,
+		NoDataState:         definitions.NoDataState(rule.NoDataState),
+		ExecErrState:        definitions.ExecutionErrorState(rule.ExecErrState),
+		IsPaused:            rule.IsPaused,
+		Record:              ApiRecordFromModelRecord(rule.Record),
+		DependsOn:           ApiRuleDependenciesFromModelRuleDependencies(rule.DependsOn),
+		ActiveTimeIntervals: rule.ActiveTimeIntervals,
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringFor = util.Pointer(model.Duration(rule.KeepFiringFor))
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
@@ -18,15 +18,32 @@ import (
 )
 
 type HistoryApi interface {
+	RouteGetNotificationHistory(*contextmodel.ReqContext) response.Response
 	RouteGetStateHistory(*contextmodel.ReqContext) response.Response
 }
 
+func (f *HistoryApiHandler) RouteGetNotificationHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetNotificationHistory(ctx)
+}
+
 func (f *HistoryApiHandler) RouteGetStateHistory(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStateHistory(ctx)
 }
 
 func (api *API) RegisterHistoryApiEndpoints(srv HistoryApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/notifications/history"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/history",
+				api.Hooks.Wrap(srv.RouteGetNotificationHistory),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/rules/history"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -22,6 +22,7 @@ import (
 type TestingApi interface {
 	BacktestConfig(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
+	RouteExplainRule(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 }
@@ -42,6 +43,16 @@ func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) respo
 	}
 	return f.handleRouteEvalQueries(ctx, conf)
 }
+func (f *TestingApiHandler) RouteExplainRule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	// Parse Request Body
+	conf := apimodels.ExplainRulePayload{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteExplainRule(ctx, conf, ruleUIDParam)
+}
 func (f *TestingApiHandler) RouteTestRuleConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -87,6 +98,18 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/explain/{RuleUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/explain/{RuleUID}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/explain/{RuleUID}",
+				api.Hooks.Wrap(srv.RouteExplainRule),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/test/{DatasourceUID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
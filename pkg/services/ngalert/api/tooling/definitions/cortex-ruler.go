@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 )
 
@@ -193,6 +194,9 @@ type PostableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
 	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// ActiveTimeIntervals are only supported by Grafana-managed rules. They apply to the rules
+	// of the group that do not define their own active time intervals.
+	ActiveTimeIntervals []timeinterval.TimeInterval `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty"`
 }
 
 func (c *PostableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -231,6 +235,9 @@ func (c *PostableRuleGroupConfig) validate() error {
 	if hasGrafRules && hasLotexRules {
 		return fmt.Errorf("cannot mix Grafana & Prometheus style rules")
 	}
+	if hasLotexRules && len(c.ActiveTimeIntervals) > 0 {
+		return fmt.Errorf("active time intervals are only supported by Grafana-managed rules")
+	}
 	return nil
 }
 
@@ -240,6 +247,9 @@ type GettableRuleGroupConfig struct {
 	Interval      model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	SourceTenants []string                   `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
 	Rules         []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// ActiveTimeIntervals are only supported by Grafana-managed rules. They apply to the rules
+	// of the group that do not define their own active time intervals.
+	ActiveTimeIntervals []timeinterval.TimeInterval `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty"`
 }
 
 func (c *GettableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -398,6 +408,11 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn    []RuleDependency    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	// ActiveTimeIntervals are the time intervals in which the rule is evaluated. Outside of them,
+	// the evaluation of the rule is paused.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"active_time_intervals,omitempty" yaml:"active_time_intervals,omitempty"`
 }
 
 // swagger:model
@@ -418,6 +433,37 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn       []RuleDependency    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	// ActiveTimeIntervals are the time intervals in which the rule is evaluated. They do not include
+	// the ones of its rule group, which are returned with the group.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"active_time_intervals,omitempty" yaml:"active_time_intervals,omitempty"`
+}
+
+// RuleDependency references a Grafana-managed rule, or all rules of a rule group, that another rule depends on.
+// While any of them is firing, the alerts of the dependent rule are suppressed.
+// Either rule_uid, or namespace_uid and rule_group must be set.
+// swagger:model
+type RuleDependency struct {
+	// example: upstream-rule-uid
+	RuleUID string `json:"rule_uid,omitempty" yaml:"rule_uid,omitempty" hcl:"rule_uid"`
+	// example: upstream-folder-uid
+	NamespaceUID string `json:"namespace_uid,omitempty" yaml:"namespace_uid,omitempty" hcl:"namespace_uid"`
+	// example: upstream-group
+	RuleGroup string `json:"rule_group,omitempty" yaml:"rule_group,omitempty" hcl:"rule_group"`
+}
+
+// Record defines how a Grafana-managed recording rule writes its result.
+// swagger:model
+type Record struct {
+	// Name of the metric the result is written to.
+	// required: true
+	// example: grafana_job:requests:rate5m
+	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
+	// RefID of the query or expression whose result is written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from" hcl:"from"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
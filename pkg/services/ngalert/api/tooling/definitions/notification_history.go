@@ -0,0 +1,74 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/notifications/history history RouteGetNotificationHistory
+//
+// Query the history of the notifications sent by the Grafana Alertmanager.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: NotificationHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetNotificationHistory
+type NotificationHistoryParams struct {
+	// Only return notifications sent to this receiver.
+	// in:query
+	// required:false
+	Receiver string `json:"receiver"`
+	// Only return notifications sent by this type of integration, e.g. "email" or "slack".
+	// in:query
+	// required:false
+	Integration string `json:"integration"`
+	// Only return notifications of this aggregation group.
+	// in:query
+	// required:false
+	GroupKey string `json:"groupKey"`
+	// Only return notifications about the alert with this fingerprint.
+	// in:query
+	// required:false
+	Fingerprint string `json:"fingerprint"`
+	// Only return notifications with this status, either "success" or "failure".
+	// in:query
+	// required:false
+	Status string `json:"status"`
+	// Unix timestamp in seconds of the start of the time range.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Unix timestamp in seconds of the end of the time range.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// The maximum number of notifications to return.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:response NotificationHistory
+type NotificationHistory struct {
+	// in:body
+	Results []NotificationHistoryEntry `json:"results"`
+}
+
+// NotificationHistoryEntry is an attempt to send a notification to an integration of a receiver.
+// swagger:model
+type NotificationHistoryEntry struct {
+	Receiver          string   `json:"receiver"`
+	Integration       string   `json:"integration"`
+	IntegrationIndex  int      `json:"integrationIndex"`
+	GroupKey          string   `json:"groupKey"`
+	AlertFingerprints []string `json:"alertFingerprints"`
+	// Either "success" or "failure".
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// The time it took to send the notification, in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+	// The number of attempts to send the same notification that preceded this one.
+	Retry  int       `json:"retry"`
+	SentAt time.Time `json:"sentAt"`
+}
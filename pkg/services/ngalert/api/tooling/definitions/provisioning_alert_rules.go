@@ -3,6 +3,7 @@ package definitions
 import (
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 )
 
@@ -134,7 +135,7 @@ type ProvisionedAlertRule struct {
 	// maxLength: 190
 	// example: Always firing
 	Title string `json:"title"`
-	// required: true
+	// Condition is required for alerting rules, recording rules record Record.From instead.
 	// example: A
 	Condition string `json:"condition"`
 	// required: true
@@ -148,6 +149,8 @@ type ProvisionedAlertRule struct {
 	ExecErrState ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// KeepFiringFor is how long an alert keeps firing after its condition stopped being met.
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -156,6 +159,13 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// Record is set for recording rules. When set, Condition is not used.
+	Record *Record `json:"record,omitempty"`
+	// DependsOn are the rules or rule groups whose firing alerts suppress the alerts of this rule.
+	DependsOn []RuleDependency `json:"dependsOn,omitempty"`
+	// ActiveTimeIntervals are the time intervals in which the rule is evaluated. Outside of them,
+	// the evaluation of the rule is paused.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -219,6 +229,8 @@ type AlertRuleGroup struct {
 	FolderUID string                 `json:"folderUid"`
 	Interval  int64                  `json:"interval"`
 	Rules     []ProvisionedAlertRule `json:"rules"`
+	// ActiveTimeIntervals apply to the rules of the group that do not define their own.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals,omitempty"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
@@ -230,6 +242,8 @@ type AlertRuleGroupExport struct {
 	Interval        model.Duration    `json:"interval" yaml:"interval"`
 	IntervalSeconds int64             `json:"-" yaml:"-" hcl:"interval_seconds"`
 	Rules           []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
+	// ActiveTimeIntervals are not exported to HCL.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals,omitempty" yaml:"activeTimeIntervals,omitempty"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
@@ -246,10 +260,19 @@ type AlertRuleExport struct {
 	// ForString is used to:
 	// - Only export the for field for HCL if it is non-zero.
 	// - Format the Prometheus model.Duration type properly for HCL.
-	ForString   *string            `json:"-" yaml:"-" hcl:"for"`
-	Annotations *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
-	Labels      *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
-	IsPaused    bool               `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	ForString     *string         `json:"-" yaml:"-" hcl:"for"`
+	KeepFiringFor *model.Duration `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	// KeepFiringForString is used to:
+	// - Only export the keep_firing_for field for HCL if it is non-zero.
+	// - Format the Prometheus model.Duration type properly for HCL.
+	KeepFiringForString *string            `json:"-" yaml:"-" hcl:"keep_firing_for"`
+	Annotations         *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
+	Labels              *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
+	IsPaused            bool               `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	Record              *Record            `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	DependsOn           []RuleDependency   `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty" hcl:"depends_on,block"`
+	// ActiveTimeIntervals are not exported to HCL.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals,omitempty" yaml:"activeTimeIntervals,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
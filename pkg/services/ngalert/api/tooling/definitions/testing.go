@@ -68,6 +68,21 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /api/v1/rule/explain/{RuleUID} testing RouteExplainRule
+//
+// Evaluate a stored rule and explain the results of its queries and expressions, and the resulting state transitions.
+// Nothing is persisted.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ExplainRuleResponse
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -229,7 +244,81 @@ type BacktestConfig struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 
 	NoDataState NoDataState `json:"no_data_state"`
+
+	// Notifications enables routing the alerts through the notification policy tree of the organization.
+	// If enabled, the response is a BacktestNotificationsResult.
+	Notifications bool `json:"notifications,omitempty"`
+	// NamespaceUID is the UID of the folder of the rule. When notifications are enabled, the title of the folder is
+	// added to the alerts as the grafana_folder label, so that notification policies can match it.
+	// example: okrd3I0Vz
+	NamespaceUID string `json:"folderUid,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters RouteExplainRule
+type ExplainRuleRequest struct {
+	// in:path
+	RuleUID string
+	// in:body
+	Body ExplainRulePayload
+}
+
+// swagger:model
+type ExplainRulePayload struct {
+	// Now is the time at which the rule is evaluated. Defaults to the current time.
+	Now time.Time `json:"now"`
+}
+
+// swagger:model
+type ExplainRuleResponse struct {
+	EvaluatedAt time.Time `json:"evaluatedAt"`
+	// Nodes are the results of the queries and expressions of the rule, in the order they were executed.
+	Nodes []ExplainNode `json:"nodes"`
+	// Instances are the evaluation results of the alert instances.
+	Instances []ExplainInstance `json:"instances"`
+	// Transitions are the state transitions that would be applied to the alert instances.
+	Transitions []ExplainTransition `json:"transitions"`
+}
+
+type ExplainNode struct {
+	RefID string `json:"refId"`
+	// Type is either the type of the expression, or "datasource" for queries.
+	Type           string      `json:"type"`
+	DatasourceUID  string      `json:"datasourceUid,omitempty"`
+	DatasourceType string      `json:"datasourceType,omitempty"`
+	DurationMs     int64       `json:"durationMs"`
+	Frames         data.Frames `json:"frames"`
+	Error          string      `json:"error,omitempty"`
+}
+
+type ExplainInstance struct {
+	Labels map[string]string   `json:"labels"`
+	State  string              `json:"state"`
+	Error  string              `json:"error,omitempty"`
+	Values map[string]*float64 `json:"values,omitempty"`
+}
+
+type ExplainTransition struct {
+	Labels        map[string]string `json:"labels"`
+	PreviousState string            `json:"previousState"`
+	State         string            `json:"state"`
+	Changed       bool              `json:"changed"`
+}
+
+// swagger:model
+type BacktestNotificationsResult struct {
+	// States are the states of the alert instances in the same format as BacktestResult.
+	States json.RawMessage `json:"states"`
+	// Notifications are the notifications that would have been sent, sorted by time.
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+type BacktestNotification struct {
+	Time        time.Time           `json:"time"`
+	Receiver    string              `json:"receiver"`
+	GroupLabels map[string]string   `json:"groupLabels"`
+	Firing      []map[string]string `json:"firing"`
+	Resolved    []map[string]string `json:"resolved"`
+}
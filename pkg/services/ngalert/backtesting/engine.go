@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -62,6 +63,26 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	return e.test(ctx, user, rule, from, to, nil, nil)
+}
+
+// TestNotifications tests the rule like Test does, and in addition routes the alerts through the given notification
+// policy tree. The extra labels are added to the alerts like the scheduler does for stored rules. It returns the
+// notifications that would have been sent during the interval, sorted by time.
+func (e *Engine) TestNotifications(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, cfg apimodels.Config) (*data.Frame, []Notification, error) {
+	sim, err := newNotificationSimulator(cfg)
+	if err != nil {
+		return nil, nil, errors.Join(ErrInvalidInputData, err)
+	}
+	result, err := e.test(ctx, user, rule, from, to, extraLabels, sim)
+	if err != nil {
+		return nil, nil, err
+	}
+	sim.advance(to)
+	return result, sim.notifications, nil
+}
+
+func (e *Engine) test(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, sim *notificationSimulator) (*data.Frame, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -92,7 +113,11 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels)
+		if sim != nil {
+			sim.advance(currentTime)
+			sim.update(currentTime, states)
+		}
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -0,0 +1,293 @@
+package backtesting
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Notification is a notification that would have been sent to a receiver while backtesting a rule.
+type Notification struct {
+	Time        time.Time
+	Receiver    string
+	GroupLabels data.Labels
+	Firing      []data.Labels
+	Resolved    []data.Labels
+}
+
+// notificationSimulator replays alerts through a notification policy tree. It mimics the aggregation groups of the
+// Alertmanager dispatcher (group wait, group interval, repeat interval and mute timings), the inhibition rules and
+// the deduplication of notifications, assuming that every receiver sends resolved notifications.
+type notificationSimulator struct {
+	route         *dispatch.Route
+	muteTimes     map[string][]timeinterval.TimeInterval
+	inhibitRules  []*inhibit.InhibitRule
+	groups        map[string]*aggregationGroup
+	notifications []Notification
+	// firing are the alerts that are firing at the moment. They are the sources of inhibitions.
+	firing map[model.Fingerprint]model.LabelSet
+}
+
+type simulatedAlert struct {
+	labels model.LabelSet
+	firing bool
+}
+
+type aggregationGroup struct {
+	key    string
+	route  *dispatch.Route
+	labels model.LabelSet
+	alerts map[model.Fingerprint]*simulatedAlert
+	// next is the time of the next flush of the group.
+	next time.Time
+
+	// The alerts and the time of the last notification, used to deduplicate notifications.
+	notified     bool
+	lastNotified time.Time
+	lastFiring   map[model.Fingerprint]struct{}
+	lastResolved map[model.Fingerprint]struct{}
+}
+
+func newNotificationSimulator(cfg apimodels.Config) (*notificationSimulator, error) {
+	if cfg.Route == nil {
+		return nil, errors.New("the notification policy tree is empty")
+	}
+	muteTimes := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals))
+	for _, mt := range cfg.MuteTimeIntervals {
+		muteTimes[mt.Name] = mt.TimeIntervals
+	}
+	inhibitRules := make([]*inhibit.InhibitRule, 0, len(cfg.InhibitRules))
+	for _, r := range cfg.InhibitRules {
+		inhibitRules = append(inhibitRules, inhibit.NewInhibitRule(r))
+	}
+	return &notificationSimulator{
+		route:        dispatch.NewRoute(cfg.Route.AsAMRoute(), nil),
+		muteTimes:    muteTimes,
+		inhibitRules: inhibitRules,
+		groups:       map[string]*aggregationGroup{},
+		firing:       map[model.Fingerprint]model.LabelSet{},
+	}, nil
+}
+
+// update sends the alerts of the given state transitions to the aggregation groups of the matching routes.
+func (s *notificationSimulator) update(now time.Time, transitions []state.StateTransition) {
+	for _, t := range transitions {
+		firing := isFiring(t.State.State)
+		if !firing && !isFiring(t.PreviousState) {
+			continue
+		}
+		lbls := make(model.LabelSet, len(t.Labels))
+		for k, v := range t.Labels {
+			lbls[model.LabelName(k)] = model.LabelValue(v)
+		}
+		fp := lbls.Fingerprint()
+		if firing {
+			s.firing[fp] = lbls
+		} else {
+			delete(s.firing, fp)
+		}
+
+		for _, r := range s.route.Match(lbls) {
+			groupLabels := getGroupLabels(lbls, r)
+			key := fmt.Sprintf("%s:%s", r.Key(), groupLabels)
+			g, ok := s.groups[key]
+			if !ok {
+				if !firing {
+					// Resolved alerts do not create new groups.
+					continue
+				}
+				g = &aggregationGroup{
+					key:    key,
+					route:  r,
+					labels: groupLabels,
+					alerts: map[model.Fingerprint]*simulatedAlert{},
+					next:   now.Add(r.RouteOpts.GroupWait),
+				}
+				s.groups[key] = g
+			}
+			g.alerts[fp] = &simulatedAlert{labels: lbls, firing: firing}
+		}
+	}
+}
+
+// advance flushes, in chronological order, all aggregation groups that are due until the given time.
+func (s *notificationSimulator) advance(until time.Time) {
+	for {
+		var next *aggregationGroup
+		for _, g := range s.groups {
+			if g.next.After(until) {
+				continue
+			}
+			if next == nil || g.next.Before(next.next) || (g.next.Equal(next.next) && g.key < next.key) {
+				next = g
+			}
+		}
+		if next == nil {
+			return
+		}
+		s.flush(next)
+	}
+}
+
+func (s *notificationSimulator) flush(g *aggregationGroup) {
+	now := g.next
+	g.next = now.Add(g.route.RouteOpts.GroupInterval)
+
+	if s.isMuted(g.route, now) {
+		return
+	}
+
+	// Inhibited alerts stay in the group but are left out of the notifications, like in the Alertmanager.
+	alerts := make(map[model.Fingerprint]*simulatedAlert, len(g.alerts))
+	for fp, a := range g.alerts {
+		if !s.isInhibited(a.labels) {
+			alerts[fp] = a
+		}
+	}
+
+	firing := map[model.Fingerprint]struct{}{}
+	resolved := map[model.Fingerprint]struct{}{}
+	for fp, a := range alerts {
+		if a.firing {
+			firing[fp] = struct{}{}
+		} else {
+			resolved[fp] = struct{}{}
+		}
+	}
+
+	if g.needsUpdate(now, firing, resolved) {
+		n := Notification{
+			Time:        now,
+			Receiver:    g.route.RouteOpts.Receiver,
+			GroupLabels: toDataLabels(g.labels),
+		}
+		for _, a := range alerts {
+			if a.firing {
+				n.Firing = append(n.Firing, toDataLabels(a.labels))
+			} else {
+				n.Resolved = append(n.Resolved, toDataLabels(a.labels))
+			}
+		}
+		sortLabels(n.Firing)
+		sortLabels(n.Resolved)
+		s.notifications = append(s.notifications, n)
+
+		g.notified = true
+		g.lastNotified = now
+		g.lastFiring = firing
+		g.lastResolved = resolved
+	}
+
+	// Resolved alerts are removed from the group once they were flushed, like in the Alertmanager.
+	for fp := range resolved {
+		delete(g.alerts, fp)
+	}
+	if len(g.alerts) == 0 {
+		delete(s.groups, g.key)
+	}
+}
+
+// needsUpdate mirrors the deduplication of notifications by the notification log of the Alertmanager.
+func (g *aggregationGroup) needsUpdate(now time.Time, firing, resolved map[model.Fingerprint]struct{}) bool {
+	if !g.notified {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, g.lastFiring) {
+		return true
+	}
+	if len(firing) == 0 {
+		return len(g.lastFiring) > 0
+	}
+	if !isSubset(resolved, g.lastResolved) {
+		return true
+	}
+	return !now.Before(g.lastNotified.Add(g.route.RouteOpts.RepeatInterval))
+}
+
+func (s *notificationSimulator) isMuted(r *dispatch.Route, now time.Time) bool {
+	for _, name := range r.RouteOpts.MuteTimeIntervals {
+		for _, ti := range s.muteTimes[name] {
+			if ti.ContainsTime(now.UTC()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInhibited mirrors the Alertmanager inhibitor: the alert is inhibited if it matches the target matchers of a rule
+// and a firing alert that matches the source matchers has the same values for the labels in equal. Alerts that match
+// both the source and the target matchers do not inhibit each other.
+func (s *notificationSimulator) isInhibited(lbls model.LabelSet) bool {
+	for _, r := range s.inhibitRules {
+		if !r.TargetMatchers.Matches(lbls) {
+			continue
+		}
+		excludeTwoSidedMatch := r.SourceMatchers.Matches(lbls)
+	sources:
+		for _, source := range s.firing {
+			if !r.SourceMatchers.Matches(source) {
+				continue
+			}
+			if excludeTwoSidedMatch && r.TargetMatchers.Matches(source) {
+				continue
+			}
+			for name := range r.Equal {
+				if source[name] != lbls[name] {
+					continue sources
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func isFiring(s eval.State) bool {
+	return s == eval.Alerting || s == eval.NoData || s == eval.Error
+}
+
+// getGroupLabels returns the labels of the alert the route groups by.
+func getGroupLabels(lbls model.LabelSet, r *dispatch.Route) model.LabelSet {
+	groupLabels := model.LabelSet{}
+	for ln, lv := range lbls {
+		if _, ok := r.RouteOpts.GroupBy[ln]; ok || r.RouteOpts.GroupByAll {
+			groupLabels[ln] = lv
+		}
+	}
+	return groupLabels
+}
+
+func isSubset(set, of map[model.Fingerprint]struct{}) bool {
+	for fp := range set {
+		if _, ok := of[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func toDataLabels(lbls model.LabelSet) data.Labels {
+	result := make(data.Labels, len(lbls))
+	for k, v := range lbls {
+		result[string(k)] = string(v)
+	}
+	return result
+}
+
+func sortLabels(lbls []data.Labels) {
+	sort.Slice(lbls, func(i, j int) bool {
+		return lbls[i].String() < lbls[j].String()
+	})
+}
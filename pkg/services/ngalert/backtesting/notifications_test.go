@@ -0,0 +1,119 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestNotificationSimulator(t *testing.T) {
+	duration := func(d time.Duration) *model.Duration {
+		md := model.Duration(d)
+		return &md
+	}
+	cfg := apimodels.Config{
+		Route: &apimodels.Route{
+			Receiver:       "default",
+			GroupBy:        []model.LabelName{"alertname"},
+			GroupWait:      duration(30 * time.Second),
+			GroupInterval:  duration(5 * time.Minute),
+			RepeatInterval: duration(time.Hour),
+			Routes: []*apimodels.Route{
+				{Receiver: "team-a", Match: map[string]string{"team": "a"}},
+			},
+		},
+	}
+	transition := func(lbls data.Labels, previous, current eval.State) state.StateTransition {
+		return state.StateTransition{PreviousState: previous, State: &state.State{State: current, Labels: lbls}}
+	}
+	alertA := data.Labels{"alertname": "test", "team": "a", "instance": "1"}
+	alertB := data.Labels{"alertname": "test", "team": "b"}
+
+	t.Run("groups alerts and deduplicates notifications", func(t *testing.T) {
+		sim, err := newNotificationSimulator(cfg)
+		require.NoError(t, err)
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		sim.update(start, []state.StateTransition{
+			transition(alertA, eval.Normal, eval.Alerting),
+			transition(alertB, eval.Normal, eval.Pending),
+		})
+		sim.advance(start.Add(time.Minute))
+		sim.update(start.Add(time.Minute), []state.StateTransition{
+			transition(alertA, eval.Alerting, eval.Alerting),
+			transition(alertB, eval.Pending, eval.Alerting),
+		})
+		// The next flush of the group of alert A does not send a notification as nothing changed.
+		sim.advance(start.Add(6 * time.Minute))
+		sim.update(start.Add(6*time.Minute), []state.StateTransition{
+			transition(alertA, eval.Alerting, eval.Normal),
+			transition(alertB, eval.Alerting, eval.Alerting),
+		})
+		sim.advance(start.Add(2 * time.Hour))
+
+		require.Equal(t, []Notification{
+			{Time: start.Add(30 * time.Second), Receiver: "team-a", GroupLabels: data.Labels{"alertname": "test"}, Firing: []data.Labels{alertA}},
+			{Time: start.Add(90 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: []data.Labels{alertB}},
+			{Time: start.Add(10*time.Minute + 30*time.Second), Receiver: "team-a", GroupLabels: data.Labels{"alertname": "test"}, Resolved: []data.Labels{alertA}},
+			{Time: start.Add(time.Hour + 90*time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: []data.Labels{alertB}},
+		}, sim.notifications)
+	})
+
+	t.Run("does not notify about alerts that are not firing", func(t *testing.T) {
+		sim, err := newNotificationSimulator(cfg)
+		require.NoError(t, err)
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		sim.update(start, []state.StateTransition{
+			transition(alertA, eval.Normal, eval.Pending),
+			transition(alertB, eval.Normal, eval.Normal),
+		})
+		sim.advance(start.Add(time.Hour))
+
+		require.Empty(t, sim.notifications)
+		require.Empty(t, sim.groups)
+	})
+
+	t.Run("does not notify about inhibited alerts", func(t *testing.T) {
+		cfg := cfg
+		cfg.InhibitRules = []config.InhibitRule{{
+			SourceMatch: map[string]string{"severity": "critical"},
+			TargetMatch: map[string]string{"severity": "warning"},
+			Equal:       model.LabelNames{"alertname"},
+		}}
+		sim, err := newNotificationSimulator(cfg)
+		require.NoError(t, err)
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		critical := data.Labels{"alertname": "test", "severity": "critical"}
+		warning := data.Labels{"alertname": "test", "severity": "warning"}
+
+		sim.update(start, []state.StateTransition{
+			transition(critical, eval.Normal, eval.Alerting),
+			transition(warning, eval.Normal, eval.Alerting),
+		})
+		sim.advance(start.Add(time.Minute))
+		sim.update(start.Add(time.Minute), []state.StateTransition{
+			transition(critical, eval.Alerting, eval.Normal),
+			transition(warning, eval.Alerting, eval.Alerting),
+		})
+		sim.advance(start.Add(6 * time.Minute))
+
+		require.Equal(t, []Notification{
+			{Time: start.Add(30 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: []data.Labels{critical}},
+			{Time: start.Add(5*time.Minute + 30*time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: []data.Labels{warning}, Resolved: []data.Labels{critical}},
+		}, sim.notifications)
+	})
+
+	t.Run("fails without a notification policy tree", func(t *testing.T) {
+		_, err := newNotificationSimulator(apimodels.Config{})
+		require.Error(t, err)
+	})
+}
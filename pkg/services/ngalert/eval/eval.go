@@ -82,8 +82,13 @@ func (r *conditionEvaluator) Evaluate(ctx context.Context, now time.Time) (Resul
 	if err != nil {
 		return nil, err
 	}
-	execResults := queryDataResponseToExecutionResults(r.condition, response)
-	return evaluateExecutionResult(execResults, now), nil
+	return ResultsFromRawResponse(r.condition, response, now), nil
+}
+
+// ResultsFromRawResponse converts the response of ConditionEvaluator.EvaluateRaw to Results.
+func ResultsFromRawResponse(condition models.Condition, response *backend.QueryDataResponse, now time.Time) Results {
+	execResults := queryDataResponseToExecutionResults(condition, response)
+	return evaluateExecutionResult(execResults, now)
 }
 
 type evaluatorImpl struct {
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type RemoteAlertmanager struct {
+	ShadowMirrorFailures     *prometheus.CounterVec
+	ShadowComparisons        *prometheus.CounterVec
+	ShadowComparisonsFailed  *prometheus.CounterVec
+	ShadowDivergences        *prometheus.GaugeVec
+	ShadowLastComparisonTime *prometheus.GaugeVec
+}
+
+func NewRemoteAlertmanagerMetrics(r prometheus.Registerer) *RemoteAlertmanager {
+	return &RemoteAlertmanager{
+		ShadowMirrorFailures: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_alertmanager_shadow_mirror_failures_total",
+			Help:      "The total number of operations that failed to be mirrored to the remote Alertmanager in shadow mode.",
+		}, []string{"org", "operation"}),
+		ShadowComparisons: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_alertmanager_shadow_comparisons_total",
+			Help:      "The total number of comparisons between the internal and the remote Alertmanager in shadow mode.",
+		}, []string{"org"}),
+		ShadowComparisonsFailed: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_alertmanager_shadow_comparisons_failed_total",
+			Help:      "The total number of comparisons between the internal and the remote Alertmanager that failed in shadow mode.",
+		}, []string{"org"}),
+		ShadowDivergences: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_alertmanager_shadow_divergences",
+			Help:      "The number of divergences between the internal and the remote Alertmanager found by the last comparison in shadow mode.",
+		}, []string{"org", "type"}),
+		ShadowLastComparisonTime: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_alertmanager_shadow_last_comparison_timestamp_seconds",
+			Help:      "Timestamp of the last successful comparison between the internal and the remote Alertmanager in shadow mode.",
+		}, []string{"org"}),
+	}
+}
@@ -27,6 +27,9 @@ type Scheduler struct {
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
+	ShardMembers                        prometheus.Gauge
+	ShardOwnedAlertRules                prometheus.Gauge
+	ShardRebalances                     prometheus.Counter
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -143,5 +146,26 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "name"},
 		),
+		ShardMembers: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_members",
+				Help:      "The number of replicas the evaluation of alert rules is sharded across.",
+			}),
+		ShardOwnedAlertRules: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_owned_alert_rules",
+				Help:      "The number of alert rules that are evaluated by this replica.",
+			}),
+		ShardRebalances: promauto.With(r).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_rebalances_total",
+				Help:      "The total number of times alert rules were redistributed because the replicas changed.",
+			}),
 	}
 }
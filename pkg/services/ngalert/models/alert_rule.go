@@ -12,6 +12,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	prommodel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -113,6 +115,8 @@ const (
 	StateReasonPaused        = "Paused"
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
+	StateReasonKeepFiring    = "KeepFiring"
+	StateReasonSuppressed    = "Suppressed"
 )
 
 var (
@@ -135,6 +139,8 @@ type AlertRuleGroup struct {
 	Interval   int64
 	Provenance Provenance
 	Rules      []AlertRule
+	// ActiveTimeIntervals apply to the rules of the group that do not have their own.
+	ActiveTimeIntervals []timeinterval.TimeInterval
 }
 
 // AlertRuleGroupWithFolderTitle extends AlertRuleGroup with orgID and folder title
@@ -147,15 +153,18 @@ type AlertRuleGroupWithFolderTitle struct {
 func NewAlertRuleGroupWithFolderTitle(groupKey AlertRuleGroupKey, rules []AlertRule, folderTitle string) AlertRuleGroupWithFolderTitle {
 	SortAlertRulesByGroupIndex(rules)
 	var interval int64
+	var activeTimeIntervals []timeinterval.TimeInterval
 	if len(rules) > 0 {
 		interval = rules[0].IntervalSeconds
+		activeTimeIntervals = rules[0].GroupActiveTimeIntervals
 	}
 	var result = AlertRuleGroupWithFolderTitle{
 		AlertRuleGroup: &AlertRuleGroup{
-			Title:     groupKey.RuleGroup,
-			FolderUID: groupKey.NamespaceUID,
-			Interval:  interval,
-			Rules:     rules,
+			Title:               groupKey.RuleGroup,
+			FolderUID:           groupKey.NamespaceUID,
+			Interval:            interval,
+			Rules:               rules,
+			ActiveTimeIntervals: activeTimeIntervals,
 		},
 		FolderTitle: folderTitle,
 		OrgID:       groupKey.OrgID,
@@ -201,12 +210,121 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is how long an alerting instance keeps firing after the condition stopped being met.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// Record is set if the rule is a recording rule. Recording rules write the result of
+	// the expression pipeline as a new metric instead of producing alerts.
+	Record *Record `xorm:"json 'record'"`
+	// DependsOn are the rules this rule depends on. While any of them is firing,
+	// the alerting instances of this rule are suppressed and not sent.
+	DependsOn []RuleDependency `xorm:"json 'depends_on'"`
+	// ActiveTimeIntervals are the time intervals in which the rule is evaluated. Outside of them, the
+	// evaluation of the rule is paused. The rule is always evaluated if neither the rule nor its group have any.
+	ActiveTimeIntervals []timeinterval.TimeInterval `xorm:"json 'active_time_intervals'"`
+	// GroupActiveTimeIntervals are the active time intervals of the rule group. Like the interval, they are
+	// stored with every rule of the group. They apply if the rule does not have its own.
+	GroupActiveTimeIntervals []timeinterval.TimeInterval `xorm:"json 'group_active_time_intervals'"`
+}
+
+// EffectiveActiveTimeIntervals returns the active time intervals of the rule, or the ones of its group
+// if the rule does not have its own.
+func (alertRule *AlertRule) EffectiveActiveTimeIntervals() []timeinterval.TimeInterval {
+	if len(alertRule.ActiveTimeIntervals) > 0 {
+		return alertRule.ActiveTimeIntervals
+	}
+	return alertRule.GroupActiveTimeIntervals
+}
+
+// IsActiveAt returns true if the rule has no effective active time intervals or if one of them contains the given time.
+func (alertRule *AlertRule) IsActiveAt(t time.Time) bool {
+	intervals := alertRule.EffectiveActiveTimeIntervals()
+	if len(intervals) == 0 {
+		return true
+	}
+	for _, ti := range intervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleDependency references a rule, or all rules of a rule group, that another rule depends on.
+// Either RuleUID, or NamespaceUID and RuleGroup are set.
+type RuleDependency struct {
+	RuleUID      string `json:"rule_uid,omitempty"`
+	NamespaceUID string `json:"namespace_uid,omitempty"`
+	RuleGroup    string `json:"rule_group,omitempty"`
+}
+
+// Matches returns true if the rule is the dependency or belongs to the rule group of the dependency.
+func (d RuleDependency) Matches(rule *AlertRule) bool {
+	if d.RuleUID != "" {
+		return rule.UID == d.RuleUID
+	}
+	return rule.NamespaceUID == d.NamespaceUID && rule.RuleGroup == d.RuleGroup
+}
+
+func (d RuleDependency) String() string {
+	if d.RuleUID != "" {
+		return "rule " + d.RuleUID
+	}
+	return fmt.Sprintf("rule group %s in namespace %s", d.RuleGroup, d.NamespaceUID)
+}
+
+// ValidateDependencies checks that each dependency references either a rule or a rule group, and that
+// the rule does not depend on itself.
+func (alertRule *AlertRule) ValidateDependencies() error {
+	for _, d := range alertRule.DependsOn {
+		if d.RuleUID != "" && (d.NamespaceUID != "" || d.RuleGroup != "") {
+			return fmt.Errorf("%w: a dependency must reference either a rule or a rule group, not both", ErrAlertRuleFailedValidation)
+		}
+		if d.RuleUID == "" && (d.NamespaceUID == "" || d.RuleGroup == "") {
+			return fmt.Errorf("%w: a dependency must reference a rule by its UID or a rule group by its namespace UID and name", ErrAlertRuleFailedValidation)
+		}
+		if d.Matches(alertRule) {
+			return fmt.Errorf("%w: a rule cannot depend on itself or its own rule group", ErrAlertRuleFailedValidation)
+		}
+	}
+	return nil
 }
 
+// Record contains the settings of a recording rule.
+type Record struct {
+	// Metric is the name of the metric the result is written to.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose result is written.
+	From string `json:"from"`
+}
+
+// Validate checks that the metric name is a valid Prometheus metric name and that From refers to one of the queries.
+func (r *Record) Validate(data []AlertQuery) error {
+	if !prommodel.IsValidMetricName(prommodel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: metric name %q is not a valid Prometheus metric name", ErrAlertRuleFailedValidation, r.Metric)
+	}
+	if r.From == "" {
+		return fmt.Errorf("%w: recording rule must specify the query or expression to record", ErrAlertRuleFailedValidation)
+	}
+	for _, q := range data {
+		if q.RefID == r.From {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: recorded query or expression %s does not exist", ErrAlertRuleFailedValidation, r.From)
+}
+
+// RuleType is the type of an alert rule.
+type RuleType string
+
+const (
+	RuleTypeAlerting  RuleType = "alerting"
+	RuleTypeRecording RuleType = "recording"
+)
+
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
 // object is created in an early validation step without knowledge about current alert rule fields or if they need to be
 // overridden. This is done in a later step and, in that step, we did not have knowledge about if a field was optional
@@ -277,6 +395,19 @@ func WithoutInternalLabels() LabelOption {
 	}
 }
 
+// IsRecordingRule returns true if the rule is a recording rule.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
+}
+
+// Type returns the type of the rule.
+func (alertRule *AlertRule) Type() RuleType {
+	if alertRule.IsRecordingRule() {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
+}
+
 // GetLabels returns the labels specified as part of the alert rule.
 func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	labels := alertRule.Labels
@@ -288,7 +419,14 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// GetEvalCondition returns the condition to evaluate. For recording rules, this is the recorded query or expression.
 func (alertRule *AlertRule) GetEvalCondition() Condition {
+	if alertRule.IsRecordingRule() {
+		return Condition{
+			Condition: alertRule.Record.From,
+			Data:      alertRule.Data,
+		}
+	}
 	return Condition{
 		Condition: alertRule.Condition,
 		Data:      alertRule.Data,
@@ -304,7 +442,11 @@ func (alertRule *AlertRule) Diff(rule *AlertRule, ignore ...string) cmputil.Diff
 	var jsonCmp = cmp.Transformer("", func(in json.RawMessage) string {
 		return string(in)
 	})
-	ops = append(ops, cmp.Reporter(&reporter), cmpopts.IgnoreFields(AlertQuery{}, "modelProps"), jsonCmp, cmpopts.EquateEmpty())
+	// the locations of time intervals wrap a time.Location, which has unexported fields
+	var locationCmp = cmp.Comparer(func(a, b timeinterval.Location) bool {
+		return a.Location.String() == b.Location.String()
+	})
+	ops = append(ops, cmp.Reporter(&reporter), cmpopts.IgnoreFields(AlertQuery{}, "modelProps"), jsonCmp, locationCmp, cmpopts.EquateEmpty())
 
 	if len(ignore) > 0 {
 		ops = append(ops, cmpopts.IgnoreFields(AlertRule{}, ignore...))
@@ -456,10 +598,16 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For           time.Duration
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	Record        *Record          `xorm:"json 'record'"`
+	DependsOn     []RuleDependency `xorm:"json 'depends_on'"`
+	// ActiveTimeIntervals are kept in the versions so that restoring a version restores them.
+	ActiveTimeIntervals      []timeinterval.TimeInterval `xorm:"json 'active_time_intervals'"`
+	GroupActiveTimeIntervals []timeinterval.TimeInterval `xorm:"json 'group_active_time_intervals'"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -546,16 +694,17 @@ func (c Condition) IsValid() bool {
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
 // 2. There are fields that are patched together:
-//   - AlertRule.Condition and AlertRule.Data
+//   - AlertRule.Condition, AlertRule.Data and AlertRule.Record
 //
-// If either of the pair is specified, neither is patched.
+// If either the condition or the data (or the record for recording rules) is specified, none of them is patched.
 func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOptionals) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
 	}
-	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
+	if (ruleToPatch.Condition == "" && ruleToPatch.Record == nil) || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
 		ruleToPatch.Data = existingRule.Data
+		ruleToPatch.Record = existingRule.Record
 	}
 	if ruleToPatch.IntervalSeconds == 0 {
 		ruleToPatch.IntervalSeconds = existingRule.IntervalSeconds
@@ -575,6 +724,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -729,3 +730,64 @@ func TestTimeRangeYAML(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, yamlRaw, string(serialized))
 }
+
+func TestValidateDependencies(t *testing.T) {
+	rule := AlertRuleGen()()
+
+	testCases := []struct {
+		name       string
+		dependency RuleDependency
+		valid      bool
+	}{
+		{name: "rule", dependency: RuleDependency{RuleUID: "upstream"}, valid: true},
+		{name: "rule group", dependency: RuleDependency{NamespaceUID: rule.NamespaceUID, RuleGroup: "upstream"}, valid: true},
+		{name: "rule and rule group", dependency: RuleDependency{RuleUID: "upstream", NamespaceUID: "folder", RuleGroup: "group"}},
+		{name: "rule group without namespace", dependency: RuleDependency{RuleGroup: "group"}},
+		{name: "namespace without rule group", dependency: RuleDependency{NamespaceUID: "folder"}},
+		{name: "itself", dependency: RuleDependency{RuleUID: rule.UID}},
+		{name: "its own rule group", dependency: RuleDependency{NamespaceUID: rule.NamespaceUID, RuleGroup: rule.RuleGroup}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := CopyRule(rule)
+			r.DependsOn = []RuleDependency{tc.dependency}
+			err := r.ValidateDependencies()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			}
+		})
+	}
+}
+
+func TestIsActiveAt(t *testing.T) {
+	var weekdays []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte("- weekdays: ['monday:friday']\n  times:\n  - start_time: '09:00'\n    end_time: '17:00'\n"), &weekdays))
+
+	var evenings []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte("- times:\n  - start_time: '18:00'\n    end_time: '23:00'\n"), &evenings))
+
+	monday := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		intervals      []timeinterval.TimeInterval
+		groupIntervals []timeinterval.TimeInterval
+		t              time.Time
+		expected       bool
+	}{
+		{name: "rule without intervals is always active", t: monday, expected: true},
+		{name: "rule is active within an interval", intervals: weekdays, t: monday, expected: true},
+		{name: "rule is inactive outside of the times of an interval", intervals: weekdays, t: monday.Add(8 * time.Hour), expected: false},
+		{name: "rule is inactive outside of the weekdays of an interval", intervals: weekdays, t: monday.AddDate(0, 0, 5), expected: false},
+		{name: "time is compared in UTC", intervals: weekdays, t: monday.In(time.FixedZone("UTC+10", 10*60*60)), expected: true},
+		{name: "rule without intervals uses the ones of its group", groupIntervals: evenings, t: monday, expected: false},
+		{name: "rule intervals take precedence over the ones of its group", intervals: weekdays, groupIntervals: evenings, t: monday, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := &AlertRule{ActiveTimeIntervals: tc.intervals, GroupActiveTimeIntervals: tc.groupIntervals}
+			require.Equal(t, tc.expected, rule.IsActiveAt(tc.t))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationStatus is the outcome of an attempt to send a notification.
+type NotificationStatus string
+
+const (
+	NotificationStatusSuccess NotificationStatus = "success"
+	NotificationStatusFailure NotificationStatus = "failure"
+)
+
+// NotificationHistoryEntry is an attempt of the Alertmanager to send a notification
+// to one of the integrations of a receiver.
+type NotificationHistoryEntry struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	Receiver string `xorm:"receiver"`
+	// Integration is the type of the integration, e.g. "email" or "slack".
+	Integration string `xorm:"integration"`
+	// IntegrationIndex is the position of the integration in the receiver.
+	IntegrationIndex  int                `xorm:"integration_index"`
+	GroupKey          string             `xorm:"group_key"`
+	AlertFingerprints []string           `xorm:"alert_fingerprints"`
+	Status            NotificationStatus `xorm:"status"`
+	Error             string             `xorm:"error"`
+	Duration          time.Duration      `xorm:"duration"`
+	// Retry is the number of attempts to send the same notification that preceded this one.
+	Retry  int       `xorm:"retry"`
+	SentAt time.Time `xorm:"sent_at"`
+}
+
+func (e *NotificationHistoryEntry) TableName() string {
+	return "alert_notification_history"
+}
+
+// NotificationHistoryQuery represents a query for the history of notifications of an organization.
+type NotificationHistoryQuery struct {
+	OrgID            int64
+	Receiver         string
+	Integration      string
+	GroupKey         string
+	AlertFingerprint string
+	Status           NotificationStatus
+	From             time.Time
+	To               time.Time
+	Limit            int
+}
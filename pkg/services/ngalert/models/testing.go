@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/timeinterval"
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -182,6 +183,12 @@ func WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
 func WithForNTimes(timesOfInterval int64) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.For = time.Duration(rule.IntervalSeconds*timesOfInterval) * time.Second
@@ -253,6 +260,16 @@ func WithQuery(query ...AlertQuery) AlertRuleMutator {
 	}
 }
 
+// WithRecord makes the rule a recording rule that writes the result of the condition query to the given metric.
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Record = &Record{
+			Metric: metric,
+			From:   rule.Condition,
+		}
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -345,6 +362,7 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
 	}
 
 	if r.DashboardUID != nil {
@@ -382,6 +400,26 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
+
+	if r.DependsOn != nil {
+		result.DependsOn = make([]RuleDependency, len(r.DependsOn))
+		copy(result.DependsOn, r.DependsOn)
+	}
+
+	if r.ActiveTimeIntervals != nil {
+		result.ActiveTimeIntervals = make([]timeinterval.TimeInterval, len(r.ActiveTimeIntervals))
+		copy(result.ActiveTimeIntervals, r.ActiveTimeIntervals)
+	}
+
+	if r.GroupActiveTimeIntervals != nil {
+		result.GroupActiveTimeIntervals = make([]timeinterval.TimeInterval, len(r.GroupActiveTimeIntervals))
+		copy(result.GroupActiveTimeIntervals, r.GroupActiveTimeIntervals)
+	}
+
 	return &result
 }
 
@@ -39,6 +39,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -175,9 +176,34 @@ func (ng *AlertNG) init() error {
 
 	var overrides []notifier.Option
 	if ng.Cfg.UnifiedAlerting.RemoteAlertmanager.Enable {
-		override := notifier.WithAlertmanagerOverride(func(ctx context.Context, orgID int64) (notifier.Alertmanager, error) {
-			externalAMCfg := remote.AlertmanagerConfig{}
-			return remote.NewAlertmanager(externalAMCfg, orgID)
+		override := notifier.WithAlertmanagerOverride(func(embedded notifier.OrgAlertmanagerFactory) notifier.OrgAlertmanagerFactory {
+			remoteCfg := ng.Cfg.UnifiedAlerting.RemoteAlertmanager
+			return func(ctx context.Context, orgID int64) (notifier.Alertmanager, error) {
+				internal, err := embedded(ctx, orgID)
+				if err != nil {
+					return nil, err
+				}
+				externalAMCfg := remote.AlertmanagerConfig{
+					URL:               remoteCfg.URL,
+					TenantID:          remoteCfg.TenantID,
+					BasicAuthPassword: remoteCfg.Password,
+					Decrypt:           ng.SecretsService.Decrypt,
+				}
+				if !remoteCfg.ShadowMode {
+					// The embedded Alertmanager tests receivers and templates if the remote Alertmanager cannot do it.
+					externalAMCfg.Local = internal
+				}
+				remoteAM, err := remote.NewAlertmanager(externalAMCfg, orgID)
+				if err != nil {
+					internal.StopAndWait()
+					return nil, err
+				}
+				if !remoteCfg.ShadowMode {
+					return remoteAM, nil
+				}
+				// In shadow mode the embedded Alertmanager stays authoritative.
+				return remote.NewShadowAlertmanager(internal, remoteAM, orgID, ng.Cfg.UnifiedAlerting.DefaultConfiguration, remoteCfg.ShadowCompareInterval, ng.Metrics.GetRemoteAlertmanagerMetrics()), nil
+			}
 		})
 
 		overrides = append(overrides, override)
@@ -216,6 +242,11 @@ func (ng *AlertNG) init() error {
 
 	ng.AlertsRouter = alertsRouter
 
+	recordingWriter, err := createRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules, log.New("ngalert.writer"))
+	if err != nil {
+		return fmt.Errorf("failed to initialize recording rules writer: %w", err)
+	}
+
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 	schedCfg := schedule.SchedulerCfg{
 		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
@@ -228,14 +259,21 @@ func (ng *AlertNG) init() error {
 		RuleStore:            ng.store,
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
+		RecordingWriter:      recordingWriter,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 	}
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		if len(ng.Cfg.UnifiedAlerting.HAPeers) == 0 && ng.Cfg.UnifiedAlerting.HARedisAddr == "" {
+			ng.Log.Warn("Evaluation sharding is enabled but high availability is not configured. All rules are evaluated by this instance")
+		}
+		schedCfg.Membership = ng.MultiOrgAlertmanager
+	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
 	// If any are set, override the config accordingly.
 	applyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
-	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log)
+	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.SQLStore, ng.Metrics.GetHistorianMetrics(), ng.Log)
 	if err != nil {
 		return err
 	}
@@ -297,6 +335,7 @@ func (ng *AlertNG) init() error {
 		FeatureManager:       ng.FeatureToggles,
 		AppUrl:               appUrl,
 		Historian:            history,
+		NotificationHistory:  ng.store,
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 	}
@@ -419,7 +458,7 @@ type Historian interface {
 	state.Historian
 }
 
-func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, met *metrics.Historian, l log.Logger) (Historian, error) {
+func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, store db.DB, met *metrics.Historian, l log.Logger) (Historian, error) {
 	if !cfg.Enabled {
 		met.Info.WithLabelValues("noop").Set(0)
 		return historian.NewNopHistorian(), nil
@@ -434,7 +473,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l)
+		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, store, met, l)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -443,7 +482,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l)
+			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, store, met, l)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -472,6 +511,9 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		}
 		return backend, nil
 	}
+	if backend == historian.BackendTypeSQL {
+		return historian.NewSQLBackend(store, met), nil
+	}
 
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
@@ -520,3 +562,10 @@ func applyStateHistoryFeatureToggles(cfg *setting.UnifiedAlertingStateHistorySet
 		return
 	}
 }
+
+func createRecordingWriter(cfg setting.RecordingRuleSettings, l log.Logger) (writer.Writer, error) {
+	if !cfg.Enabled {
+		return writer.NoopWriter{}, nil
+	}
+	return writer.NewPrometheusWriter(cfg, l)
+}
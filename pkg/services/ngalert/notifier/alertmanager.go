@@ -42,6 +42,7 @@ var silenceMaintenanceInterval = 15 * time.Minute
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationHistoryStore
 }
 
 type alertmanager struct {
@@ -56,6 +57,13 @@ type alertmanager struct {
 
 	decryptFn alertingNotify.GetDecryptedValueFn
 	orgID     int64
+
+	// notificationHistory saves the attempts to send notifications. It is nil unless the notification history is enabled.
+	notificationHistory *notificationHistoryWriter
+
+	// forwardAlerts sends the alerts to the Alertmanagers of the other replicas. It is nil unless the evaluation
+	// of alert rules is sharded.
+	forwardAlerts func([]byte)
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -144,6 +152,13 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		fileStore:           fileStore,
 		logger:              l,
 	}
+	if cfg.UnifiedAlerting.NotificationHistory.Enabled {
+		am.notificationHistory = newNotificationHistoryWriter(store, l)
+	}
+	if cfg.UnifiedAlerting.HAEvaluationSharding {
+		c := peer.AddState(fmt.Sprintf("alerts:%d", orgID), &forwardedAlerts{put: gam.PutAlerts}, m.Registerer)
+		am.forwardAlerts = c.Broadcast
+	}
 
 	return am, nil
 }
@@ -157,6 +172,9 @@ func (am *alertmanager) Ready() bool {
 
 func (am *alertmanager) StopAndWait() {
 	am.Base.StopAndWait()
+	if am.notificationHistory != nil {
+		am.notificationHistory.stop()
+	}
 }
 
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
@@ -377,6 +395,9 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
+	if am.notificationHistory != nil {
+		integrations = withNotificationHistory(integrations, receiver.Name, am.orgID, am.notificationHistory, am.logger)
+	}
 	return integrations, nil
 }
 
@@ -392,6 +413,15 @@ func (am *alertmanager) PutAlerts(_ context.Context, postableAlerts apimodels.Po
 		})
 	}
 
+	if am.forwardAlerts != nil && len(alerts) > 0 {
+		b, err := json.Marshal(alerts)
+		if err != nil {
+			am.logger.Warn("Failed to forward alerts to the other replicas", "error", err)
+		} else {
+			am.forwardAlerts(b)
+		}
+	}
+
 	return am.Base.PutAlerts(alerts)
 }
 
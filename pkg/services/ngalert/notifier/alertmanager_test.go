@@ -5,12 +5,15 @@ import (
 	"testing"
 	"time"
 
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
@@ -50,3 +53,32 @@ func TestAlertmanager_newAlertmanager(t *testing.T) {
 	am := setupAMTest(t)
 	require.False(t, am.Ready())
 }
+
+func TestAlertmanager_forwardAlerts(t *testing.T) {
+	am := setupAMTest(t)
+	var forwarded [][]byte
+	am.forwardAlerts = func(b []byte) {
+		forwarded = append(forwarded, b)
+	}
+
+	alert := amv2.PostableAlert{
+		Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "test"}},
+	}
+	require.NoError(t, am.PutAlerts(context.Background(), apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{alert}}))
+	require.Len(t, forwarded, 1)
+
+	var received alertingNotify.PostableAlerts
+	state := &forwardedAlerts{put: func(alerts alertingNotify.PostableAlerts) error {
+		received = alerts
+		return nil
+	}}
+	require.NoError(t, state.Merge(forwarded[0]))
+	require.Len(t, received, 1)
+	require.Equal(t, alert.Labels, received[0].Labels)
+
+	t.Run("ignores the empty full state", func(t *testing.T) {
+		b, err := state.MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, state.Merge(b))
+	})
+}
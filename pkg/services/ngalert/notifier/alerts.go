@@ -2,6 +2,8 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	alertingNotify "github.com/grafana/alerting/notify"
 )
@@ -13,3 +15,29 @@ func (am *alertmanager) GetAlerts(_ context.Context, active, silenced, inhibited
 func (am *alertmanager) GetAlertGroups(_ context.Context, active, silenced, inhibited bool, filter []string, receivers string) (alertingNotify.AlertGroups, error) {
 	return am.Base.GetAlertGroups(active, silenced, inhibited, filter, receivers)
 }
+
+// forwardedAlerts receives the alerts that the Alertmanagers of the other replicas forward to the cluster. When the
+// evaluation of alert rules is sharded, every replica only sends the alerts of the rules it evaluates to its own
+// Alertmanager, so the alerts are forwarded to make every Alertmanager of the cluster aware of all alerts.
+// It implements the cluster.State interface.
+type forwardedAlerts struct {
+	put func(alertingNotify.PostableAlerts) error
+}
+
+// MarshalBinary returns no state. Alerts are not part of the full state synchronization, the rules send them again
+// periodically while they are firing.
+func (f *forwardedAlerts) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+// Merge puts the alerts forwarded by another replica into the Alertmanager.
+func (f *forwardedAlerts) Merge(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var alerts alertingNotify.PostableAlerts
+	if err := json.Unmarshal(b, &alerts); err != nil {
+		return fmt.Errorf("failed to decode forwarded alerts: %w", err)
+	}
+	return f.put(alerts)
+}
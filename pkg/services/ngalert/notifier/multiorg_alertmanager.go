@@ -76,7 +76,7 @@ type MultiOrgAlertmanager struct {
 	configStore AlertingStore
 	orgStore    store.OrgStore
 	kvStore     kvstore.KVStore
-	factory     orgAlertmanagerFactory
+	factory     OrgAlertmanagerFactory
 
 	decryptFn alertingNotify.GetDecryptedValueFn
 
@@ -84,13 +84,16 @@ type MultiOrgAlertmanager struct {
 	ns      notifications.Service
 }
 
-type orgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
+// OrgAlertmanagerFactory creates the Alertmanager of an organization.
+type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
 
 type Option func(*MultiOrgAlertmanager)
 
-func WithAlertmanagerOverride(f orgAlertmanagerFactory) Option {
+// WithAlertmanagerOverride replaces the per tenant Alertmanager factory. The function receives the default factory,
+// which creates embedded Alertmanagers, so that the override can make use of it.
+func WithAlertmanagerOverride(f func(OrgAlertmanagerFactory) OrgAlertmanagerFactory) Option {
 	return func(moa *MultiOrgAlertmanager) {
-		moa.factory = f
+		moa.factory = f(moa.factory)
 	}
 }
 
@@ -397,6 +400,34 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// Self returns the name of this replica in the Alertmanager cluster. It is empty if clustering is not configured.
+func (moa *MultiOrgAlertmanager) Self() string {
+	switch p := moa.peer.(type) {
+	case *cluster.Peer:
+		return p.Name()
+	case *redisPeer:
+		return p.withPrefix(p.name)
+	}
+	return ""
+}
+
+// Members returns the names of the live replicas in the Alertmanager cluster, including this one.
+// It is empty if clustering is not configured.
+func (moa *MultiOrgAlertmanager) Members() []string {
+	switch p := moa.peer.(type) {
+	case *cluster.Peer:
+		peers := p.Peers()
+		members := make([]string, 0, len(peers))
+		for _, peer := range peers {
+			members = append(members, peer.Name())
+		}
+		return members
+	case *redisPeer:
+		return p.Members()
+	}
+	return nil
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
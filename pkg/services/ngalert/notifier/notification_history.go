@@ -0,0 +1,222 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// notificationHistorySaveTimeout is how long saving a batch of attempts to send notifications can take.
+	notificationHistorySaveTimeout = 5 * time.Second
+	// notificationHistoryQueueSize is the number of attempts that can wait to be saved. Attempts are dropped
+	// when the queue is full, so that sending notifications never waits for the database.
+	notificationHistoryQueueSize = 1000
+	// notificationHistoryBatchSize is the maximum number of attempts saved at once.
+	notificationHistoryBatchSize = 100
+	// notificationHistoryFlushInterval is how often the queued attempts are saved if the batch is not full.
+	notificationHistoryFlushInterval = 5 * time.Second
+
+	// notificationAttemptsRetention is how long the failed attempts of an aggregation group are counted. Retries of
+	// a notification stop when the next flush of the group is due, so the counts of groups that were deleted or that
+	// did not notify again are dropped after this time.
+	notificationAttemptsRetention = 24 * time.Hour
+	// notificationAttemptsEvictionInterval is how often the counts older than the retention are dropped.
+	notificationAttemptsEvictionInterval = time.Hour
+)
+
+// withNotificationHistory wraps the integrations of a receiver so that every attempt to send a notification
+// is recorded in the notification history.
+func withNotificationHistory(integrations []*alertingNotify.Integration, receiver string, orgID int64, w *notificationHistoryWriter, logger log.Logger) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		r := &notificationHistoryRecorder{
+			integration: integration,
+			receiver:    receiver,
+			orgID:       orgID,
+			writer:      w,
+			logger:      logger,
+			attempts:    map[string]notificationAttempts{},
+			now:         time.Now,
+		}
+		result = append(result, notify.NewIntegration(r, integration, integration.Name(), integration.Index(), receiver))
+	}
+	return result
+}
+
+// notificationHistoryRecorder is a notifier that records the attempts of an integration to send notifications.
+type notificationHistoryRecorder struct {
+	integration *alertingNotify.Integration
+	receiver    string
+	orgID       int64
+	writer      *notificationHistoryWriter
+	logger      log.Logger
+	now         func() time.Time
+
+	mtx sync.Mutex
+	// attempts holds the number of failed attempts to send the current notification of each aggregation group.
+	attempts     map[string]notificationAttempts
+	lastEviction time.Time
+}
+
+type notificationAttempts struct {
+	// flushTime identifies the notification. It is the same for all attempts to send a notification.
+	flushTime time.Time
+	count     int
+}
+
+func (r *notificationHistoryRecorder) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := r.now()
+	retry, err := r.integration.Notify(ctx, alerts...)
+	duration := r.now().Sub(start)
+
+	groupKey, _ := notify.GroupKey(ctx)
+	fingerprints := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		fingerprints = append(fingerprints, a.Fingerprint().String())
+	}
+	entry := ngmodels.NotificationHistoryEntry{
+		OrgID:             r.orgID,
+		Receiver:          r.receiver,
+		Integration:       r.integration.Name(),
+		IntegrationIndex:  r.integration.Index(),
+		GroupKey:          groupKey,
+		AlertFingerprints: fingerprints,
+		Status:            ngmodels.NotificationStatusSuccess,
+		Duration:          duration,
+		Retry:             r.countAttempt(ctx, groupKey, err == nil),
+		SentAt:            start.UTC(),
+	}
+	if err != nil {
+		entry.Status = ngmodels.NotificationStatusFailure
+		entry.Error = err.Error()
+	}
+
+	r.writer.write(entry)
+
+	return retry, err
+}
+
+// countAttempt returns the number of attempts that preceded the current attempt to send the notification
+// of the aggregation group. Retries of a notification share the flush time of the aggregation group.
+func (r *notificationHistoryRecorder) countAttempt(ctx context.Context, groupKey string, success bool) int {
+	flushTime, _ := notify.Now(ctx)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.evictAttempts()
+	previous := 0
+	if a, ok := r.attempts[groupKey]; ok && a.flushTime.Equal(flushTime) {
+		previous = a.count
+	}
+	if success {
+		delete(r.attempts, groupKey)
+	} else {
+		r.attempts[groupKey] = notificationAttempts{flushTime: flushTime, count: previous + 1}
+	}
+	return previous
+}
+
+// evictAttempts drops the counts of notifications that were flushed longer ago than the retention. It must be
+// called with the lock held.
+func (r *notificationHistoryRecorder) evictAttempts() {
+	now := r.now()
+	if now.Sub(r.lastEviction) < notificationAttemptsEvictionInterval {
+		return
+	}
+	r.lastEviction = now
+	for groupKey, a := range r.attempts {
+		if now.Sub(a.flushTime) > notificationAttemptsRetention {
+			delete(r.attempts, groupKey)
+		}
+	}
+}
+
+// notificationHistoryWriter saves the attempts to send notifications in batches in the background.
+type notificationHistoryWriter struct {
+	store  store.NotificationHistoryStore
+	logger log.Logger
+
+	entries chan ngmodels.NotificationHistoryEntry
+	stopc   chan struct{}
+	done    chan struct{}
+}
+
+func newNotificationHistoryWriter(st store.NotificationHistoryStore, logger log.Logger) *notificationHistoryWriter {
+	w := &notificationHistoryWriter{
+		store:   st,
+		logger:  logger,
+		entries: make(chan ngmodels.NotificationHistoryEntry, notificationHistoryQueueSize),
+		stopc:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// write queues the entry to be saved. The entry is dropped if the queue is full.
+func (w *notificationHistoryWriter) write(entry ngmodels.NotificationHistoryEntry) {
+	select {
+	case w.entries <- entry:
+	default:
+		w.logger.Warn("Dropping notification history entry because too many entries are waiting to be saved", "receiver", entry.Receiver, "integration", entry.Integration)
+	}
+}
+
+// stop saves the queued entries and stops the writer.
+func (w *notificationHistoryWriter) stop() {
+	close(w.stopc)
+	<-w.done
+}
+
+func (w *notificationHistoryWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(notificationHistoryFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ngmodels.NotificationHistoryEntry, 0, notificationHistoryBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), notificationHistorySaveTimeout)
+		defer cancel()
+		if err := w.store.SaveNotificationHistory(ctx, batch...); err != nil {
+			w.logger.Error("Failed to save notification history", "entries", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+	add := func(entry ngmodels.NotificationHistoryEntry) {
+		batch = append(batch, entry)
+		if len(batch) == notificationHistoryBatchSize {
+			flush()
+		}
+	}
+	for {
+		select {
+		case entry := <-w.entries:
+			add(entry)
+		case <-ticker.C:
+			flush()
+		case <-w.stopc:
+			// save what is left in the queue
+			for {
+				select {
+				case entry := <-w.entries:
+					add(entry)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeIntegrationNotifier struct {
+	errs []error
+}
+
+func (f *fakeIntegrationNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	var err error
+	if len(f.errs) > 0 {
+		err, f.errs = f.errs[0], f.errs[1:]
+	}
+	return err != nil, err
+}
+
+func (f *fakeIntegrationNotifier) SendResolved() bool {
+	return true
+}
+
+func TestNotificationHistoryRecorder(t *testing.T) {
+	configStore := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	writer := newNotificationHistoryWriter(configStore, log.NewNopLogger())
+	n := &fakeIntegrationNotifier{errs: []error{errors.New("timeout"), errors.New("timeout"), nil, nil}}
+	integrations := withNotificationHistory([]*notify.Integration{
+		notify.NewIntegration(n, n, "slack", 1, "team-a"),
+	}, "team-a", 1, writer, log.NewNopLogger())
+	require.Len(t, integrations, 1)
+	require.Equal(t, "slack", integrations[0].Name())
+	require.Equal(t, 1, integrations[0].Index())
+	require.True(t, integrations[0].SendResolved())
+
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test"}}}
+	flush := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+
+	// The first notification is sent after two failed attempts.
+	for i := 0; i < 3; i++ {
+		_, _ = integrations[0].Notify(notify.WithNow(ctx, flush), alert)
+	}
+	// The next notification is sent at the first attempt.
+	_, err := integrations[0].Notify(notify.WithNow(ctx, flush.Add(time.Minute)), alert)
+	require.NoError(t, err)
+	// stopping the writer saves the queued entries
+	writer.stop()
+
+	entries, err := configStore.GetNotificationHistory(context.Background(), &models.NotificationHistoryQuery{OrgID: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	// Entries are returned with the most recent first.
+	expected := []struct {
+		status models.NotificationStatus
+		err    string
+		retry  int
+	}{
+		{status: models.NotificationStatusSuccess, retry: 0},
+		{status: models.NotificationStatusSuccess, retry: 2},
+		{status: models.NotificationStatusFailure, err: "timeout", retry: 1},
+		{status: models.NotificationStatusFailure, err: "timeout", retry: 0},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.status, entries[i].Status)
+		require.Equal(t, e.err, entries[i].Error)
+		require.Equal(t, e.retry, entries[i].Retry)
+		require.Equal(t, "team-a", entries[i].Receiver)
+		require.Equal(t, "slack", entries[i].Integration)
+		require.Equal(t, 1, entries[i].IntegrationIndex)
+		require.Equal(t, "{}:{alertname=\"test\"}", entries[i].GroupKey)
+		require.Equal(t, []string{alert.Fingerprint().String()}, entries[i].AlertFingerprints)
+	}
+}
+
+func TestNotificationHistoryRecorder_evictsAttempts(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &notificationHistoryRecorder{
+		attempts: map[string]notificationAttempts{},
+		now:      func() time.Time { return now },
+	}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"deleted\"}")
+	require.Equal(t, 0, r.countAttempt(notify.WithNow(ctx, now), "deleted", false))
+	require.Len(t, r.attempts, 1)
+
+	// the group does not notify again, its count is dropped once it is older than the retention
+	now = now.Add(notificationAttemptsRetention)
+	r.countAttempt(notify.WithNow(ctx, now), "other", true)
+	require.Len(t, r.attempts, 1)
+
+	now = now.Add(notificationAttemptsEvictionInterval)
+	r.countAttempt(notify.WithNow(ctx, now), "other", true)
+	require.Empty(t, r.attempts)
+}
@@ -31,7 +31,7 @@ var (
 // If an existing template of the same filename as the one being tested is found, it will not be used as context.
 func (am *alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*TestTemplatesResults, error) {
 	for _, alert := range c.Alerts {
-		addDefaultLabelsAndAnnotations(alert)
+		AddDefaultLabelsAndAnnotations(alert)
 	}
 
 	return am.Base.TestTemplate(ctx, alertingNotify.TestTemplatesConfigBodyParams{
@@ -41,8 +41,8 @@ func (am *alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTempla
 	})
 }
 
-// addDefaultLabelsAndAnnotations is a slimmed down version of state.StateToPostableAlert and state.GetRuleExtraLabels using default values.
-func addDefaultLabelsAndAnnotations(alert *amv2.PostableAlert) {
+// AddDefaultLabelsAndAnnotations is a slimmed down version of state.StateToPostableAlert and state.GetRuleExtraLabels using default values.
+func AddDefaultLabelsAndAnnotations(alert *amv2.PostableAlert) {
 	if alert.Labels == nil {
 		alert.Labels = make(map[string]string)
 	}
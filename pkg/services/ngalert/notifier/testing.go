@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -19,6 +20,9 @@ type fakeConfigStore struct {
 
 	// historicConfigs stores configs by orgID.
 	historicConfigs map[int64][]*models.HistoricAlertConfiguration
+
+	notificationHistoryMtx sync.Mutex
+	notificationHistory    []models.NotificationHistoryEntry
 }
 
 // Saves the image or returns an error.
@@ -176,6 +180,25 @@ func (f *fakeConfigStore) GetHistoricalConfiguration(_ context.Context, orgID in
 	return &models.HistoricAlertConfiguration{}, store.ErrNoAlertmanagerConfiguration
 }
 
+func (f *fakeConfigStore) SaveNotificationHistory(_ context.Context, entries ...models.NotificationHistoryEntry) error {
+	f.notificationHistoryMtx.Lock()
+	defer f.notificationHistoryMtx.Unlock()
+	f.notificationHistory = append(f.notificationHistory, entries...)
+	return nil
+}
+
+func (f *fakeConfigStore) GetNotificationHistory(_ context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	f.notificationHistoryMtx.Lock()
+	defer f.notificationHistoryMtx.Unlock()
+	var entries []models.NotificationHistoryEntry
+	for i := len(f.notificationHistory) - 1; i >= 0; i-- {
+		if f.notificationHistory[i].OrgID == query.OrgID {
+			entries = append(entries, f.notificationHistory[i])
+		}
+	}
+	return entries, nil
+}
+
 type FakeOrgStore struct {
 	orgs []int64
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -114,21 +116,23 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 
 // CreateAlertRule creates a new alert rule. This function will ignore any
 // interval that is set in the rule struct and use the already existing group
-// interval or the default one.
+// interval or the default one. The rule also gets the active time intervals
+// of an existing group.
 func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (models.AlertRule, error) {
 	if rule.UID == "" {
 		rule.UID = util.GenerateShortUID()
 	} else if err := util.ValidateUID(rule.UID); err != nil {
 		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, fmt.Errorf("cannot create rule with UID '%s': %w", rule.UID, err))
 	}
-	interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+	group, err := service.GetRuleGroup(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
 	// if the alert group does not exists we just use the default interval
 	if err != nil && errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-		interval = service.defaultIntervalSeconds
+		group.Interval = service.defaultIntervalSeconds
 	} else if err != nil {
 		return models.AlertRule{}, err
 	}
-	rule.IntervalSeconds = interval
+	rule.IntervalSeconds = group.Interval
+	rule.GroupActiveTimeIntervals = group.ActiveTimeIntervals
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -179,10 +183,11 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 		return models.AlertRuleGroup{}, store.ErrAlertRuleGroupNotFound
 	}
 	res := models.AlertRuleGroup{
-		Title:     ruleList[0].RuleGroup,
-		FolderUID: ruleList[0].NamespaceUID,
-		Interval:  ruleList[0].IntervalSeconds,
-		Rules:     []models.AlertRule{},
+		Title:               ruleList[0].RuleGroup,
+		FolderUID:           ruleList[0].NamespaceUID,
+		Interval:            ruleList[0].IntervalSeconds,
+		Rules:               []models.AlertRule{},
+		ActiveTimeIntervals: ruleList[0].GroupActiveTimeIntervals,
 	}
 	for _, r := range ruleList {
 		if r != nil {
@@ -192,8 +197,8 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 	return res, nil
 }
 
-// UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+// UpdateRuleGroup will update the interval and the active time intervals for all rules in the group.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64, activeTimeIntervals []timeinterval.TimeInterval) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -209,11 +214,12 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 		}
 		updateRules := make([]models.UpdateRule, 0, len(ruleList))
 		for _, rule := range ruleList {
-			if rule.IntervalSeconds == intervalSeconds {
-				continue
-			}
 			newRule := *rule
 			newRule.IntervalSeconds = intervalSeconds
+			newRule.GroupActiveTimeIntervals = activeTimeIntervals
+			if len(rule.Diff(&newRule)) == 0 {
+				continue
+			}
 			updateRules = append(updateRules, models.UpdateRule{
 				Existing: rule,
 				New:      newRule,
@@ -349,6 +355,7 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	rule.Updated = time.Now()
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
+	rule.GroupActiveTimeIntervals = storedRule.GroupActiveTimeIntervals
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -517,6 +524,7 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.AlertRuleGroup {
 	for i := range group.Rules {
 		group.Rules[i].IntervalSeconds = group.Interval
+		group.Rules[i].GroupActiveTimeIntervals = group.ActiveTimeIntervals
 		group.Rules[i].RuleGroup = group.Title
 		group.Rules[i].NamespaceUID = group.FolderUID
 		group.Rules[i].OrgID = orgID
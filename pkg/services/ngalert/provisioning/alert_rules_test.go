@@ -47,7 +47,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120, nil)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
@@ -90,7 +90,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.NoError(t, err)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120, nil)
 		require.NoError(t, err)
 
 		rule = dummyRule("test#4-1", orgID)
@@ -120,7 +120,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(1), rule.Version)
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, namespaceUID, ruleGroup, newInterval)
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, namespaceUID, ruleGroup, newInterval, nil)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, ruleUID)
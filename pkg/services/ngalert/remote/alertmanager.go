@@ -1,16 +1,22 @@
 package remote
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -19,11 +25,24 @@ import (
 	amclient "github.com/prometheus/alertmanager/api/v2/client"
 	amalert "github.com/prometheus/alertmanager/api/v2/client/alert"
 	amalertgroup "github.com/prometheus/alertmanager/api/v2/client/alertgroup"
+	amgeneral "github.com/prometheus/alertmanager/api/v2/client/general"
 	amreceiver "github.com/prometheus/alertmanager/api/v2/client/receiver"
 	amsilence "github.com/prometheus/alertmanager/api/v2/client/silence"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"golang.org/x/exp/slices"
 )
 
-const readyPath = "/-/ready"
+const (
+	readyPath               = "/-/ready"
+	configPath              = "/api/v1/grafana/config"
+	testReceiversPath       = "/api/v1/grafana/receivers/test"
+	testTemplatesPath       = "/api/v1/grafana/templates/test"
+	defaultGetStatusTimeout = 10 * time.Second
+)
+
+// errNotSupported is returned if the remote Alertmanager does not implement an endpoint.
+var errNotSupported = errors.New("not supported by the remote Alertmanager")
 
 type Alertmanager struct {
 	log      log.Logger
@@ -35,12 +54,34 @@ type Alertmanager struct {
 	httpClient *http.Client
 	ready      bool
 	sender     *sender.ExternalAlertmanager
+
+	local   notifier.Alertmanager
+	decrypt func(ctx context.Context, payload []byte) ([]byte, error)
+
+	// configMtx guards configHash, the hash of the last configuration sent to the remote Alertmanager.
+	configMtx  sync.Mutex
+	configHash string
+}
+
+// userGrafanaConfig is the payload of the Grafana Alertmanager configuration accepted by the remote Alertmanager.
+type userGrafanaConfig struct {
+	GrafanaAlertmanagerConfig string `json:"grafana_alertmanager_config"`
+	Hash                      string `json:"hash"`
+	CreatedAt                 int64  `json:"created"`
+	Default                   bool   `json:"default"`
 }
 
 type AlertmanagerConfig struct {
 	URL               string
 	TenantID          string
 	BasicAuthPassword string
+
+	// Local is an embedded Alertmanager that tests receivers and templates and provides the status
+	// if the remote Alertmanager does not support it. Optional.
+	Local notifier.Alertmanager
+	// Decrypt decrypts the secure settings of receivers before they are sent to the remote Alertmanager to be tested.
+	// Receivers with secure settings are tested by the embedded Alertmanager if it is not set.
+	Decrypt func(ctx context.Context, payload []byte) ([]byte, error)
 }
 
 func NewAlertmanager(cfg AlertmanagerConfig, orgID int64) (*Alertmanager, error) {
@@ -86,15 +127,62 @@ func NewAlertmanager(cfg AlertmanagerConfig, orgID int64) (*Alertmanager, error)
 		orgID:      orgID,
 		tenantID:   cfg.TenantID,
 		url:        cfg.URL,
+		local:      cfg.Local,
+		decrypt:    cfg.Decrypt,
 	}, nil
 }
 
 func (am *Alertmanager) ApplyConfig(ctx context.Context, config *models.AlertConfiguration) error {
-	if am.ready {
+	// The embedded Alertmanager needs the configuration to test receivers and templates.
+	if am.local != nil {
+		if err := am.local.ApplyConfig(ctx, config); err != nil {
+			am.log.Warn("Failed to apply the configuration to the embedded Alertmanager", "err", err)
+		}
+	}
+
+	if !am.ready {
+		if err := am.checkReadiness(ctx); err != nil {
+			return err
+		}
+	}
+
+	return am.sendConfiguration(ctx, config)
+}
+
+// sendConfiguration sends the configuration to the remote Alertmanager unless it has been sent already.
+func (am *Alertmanager) sendConfiguration(ctx context.Context, config *models.AlertConfiguration) error {
+	am.configMtx.Lock()
+	defer am.configMtx.Unlock()
+	if config.ConfigurationHash != "" && config.ConfigurationHash == am.configHash {
 		return nil
 	}
 
-	return am.checkReadiness(ctx)
+	cfg, err := notifier.Load([]byte(config.AlertmanagerConfiguration))
+	if err != nil {
+		return fmt.Errorf("failed to parse Alertmanager config: %w", err)
+	}
+	// The remote Alertmanager cannot decrypt the secure settings of the receivers.
+	if err := am.decryptSecureSettings(ctx, cfg.AlertmanagerConfig.Receivers); err != nil {
+		return err
+	}
+	rawConfig, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the Alertmanager config: %w", err)
+	}
+
+	payload := userGrafanaConfig{
+		GrafanaAlertmanagerConfig: string(rawConfig),
+		Hash:                      config.ConfigurationHash,
+		CreatedAt:                 config.CreatedAt,
+		Default:                   config.Default,
+	}
+	if _, err := am.sendRequest(ctx, configPath, payload, nil, http.StatusCreated, http.StatusAccepted); err != nil {
+		return fmt.Errorf("failed to send the configuration to the remote Alertmanager: %w", err)
+	}
+
+	am.log.Debug("Configuration sent to the remote Alertmanager", "hash", config.ConfigurationHash)
+	am.configHash = config.ConfigurationHash
+	return nil
 }
 
 func (am *Alertmanager) checkReadiness(ctx context.Context) error {
@@ -261,9 +349,46 @@ func (am *Alertmanager) PutAlerts(ctx context.Context, alerts apimodels.Postable
 }
 
 func (am *Alertmanager) GetStatus() apimodels.GettableStatus {
+	status, err := am.getStatus()
+	if err == nil {
+		return status
+	}
+
+	am.log.Warn("Failed to get the status of the remote Alertmanager", "err", err)
+	if am.local != nil {
+		return am.local.GetStatus()
+	}
 	return apimodels.GettableStatus{}
 }
 
+func (am *Alertmanager) getStatus() (status apimodels.GettableStatus, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			am.log.Error("Panic while getting status", "err", r)
+			err = fmt.Errorf("panic while getting status: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultGetStatusTimeout)
+	defer cancel()
+
+	params := amgeneral.NewGetStatusParamsWithContext(ctx)
+	res, err := am.amClient.General.GetStatus(params)
+	if err != nil {
+		return status, err
+	}
+
+	// GettableStatus parses the configuration of the Alertmanager status, which is returned as a YAML string.
+	b, err := json.Marshal(res.Payload)
+	if err != nil {
+		return status, err
+	}
+	if err := json.Unmarshal(b, &status); err != nil {
+		return status, fmt.Errorf("failed to parse status: %w", err)
+	}
+	return status, nil
+}
+
 func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver, error) {
 	params := amreceiver.NewGetReceiversParamsWithContext(ctx)
 	res, err := am.amClient.Receiver.GetReceivers(params)
@@ -278,16 +403,189 @@ func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver,
 	return rcvs, nil
 }
 
+// TestReceivers sends a test notification to the given receivers using the remote Alertmanager.
+// If the remote Alertmanager does not support testing Grafana receivers, they are tested by the embedded Alertmanager.
 func (am *Alertmanager) TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error) {
-	return &notifier.TestReceiversResult{}, nil
+	res, err := am.testReceivers(ctx, c)
+	if errors.Is(err, errNotSupported) && am.local != nil {
+		am.log.Debug("Testing receivers with the embedded Alertmanager", "reason", err)
+		return am.local.TestReceivers(ctx, c)
+	}
+	return res, err
+}
+
+func (am *Alertmanager) testReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error) {
+	body, err := am.decryptReceivers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var res apimodels.TestReceiversResult
+	// A partial failure is reported with the results of all receivers, and a status code that is not 200.
+	status, err := am.sendRequest(ctx, testReceiversPath, body, &res, http.StatusMultiStatus, http.StatusBadRequest, http.StatusRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusBadRequest && len(res.Receivers) == 0 {
+		return nil, alertingNotify.ErrNoReceivers
+	}
+
+	result := &notifier.TestReceiversResult{
+		Alert: types.Alert{
+			Alert: model.Alert{
+				Labels:      res.Alert.Labels,
+				Annotations: res.Alert.Annotations,
+			},
+		},
+		Receivers: make([]notifier.TestReceiverResult, 0, len(res.Receivers)),
+		NotifedAt: res.NotifiedAt,
+	}
+	for _, r := range res.Receivers {
+		configs := make([]notifier.TestReceiverConfigResult, 0, len(r.Configs))
+		for _, cfg := range r.Configs {
+			next := notifier.TestReceiverConfigResult{
+				Name:   cfg.Name,
+				UID:    cfg.UID,
+				Status: cfg.Status,
+			}
+			if cfg.Error != "" {
+				next.Error = errors.New(cfg.Error)
+			}
+			configs = append(configs, next)
+		}
+		result.Receivers = append(result.Receivers, notifier.TestReceiverResult{Name: r.Name, Configs: configs})
+	}
+	return result, nil
 }
 
+// decryptReceivers returns a copy of the request with the secure settings of the receivers decrypted.
+func (am *Alertmanager) decryptReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (apimodels.TestReceiversConfigBodyParams, error) {
+	var body apimodels.TestReceiversConfigBodyParams
+	b, err := json.Marshal(c)
+	if err != nil {
+		return body, err
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return body, err
+	}
+	return body, am.decryptSecureSettings(ctx, body.Receivers)
+}
+
+// decryptSecureSettings decrypts the secure settings of the receivers in place, as the remote Alertmanager cannot
+// decrypt them.
+func (am *Alertmanager) decryptSecureSettings(ctx context.Context, receivers []*apimodels.PostableApiReceiver) error {
+	for _, r := range receivers {
+		for _, gr := range r.GrafanaManagedReceivers {
+			for k, v := range gr.SecureSettings {
+				if am.decrypt == nil {
+					return fmt.Errorf("%w: receiver %q has secure settings", errNotSupported, r.Name)
+				}
+				encrypted, err := base64.StdEncoding.DecodeString(v)
+				if err != nil {
+					return fmt.Errorf("failed to decode secure setting %q of receiver %q: %w", k, r.Name, err)
+				}
+				decrypted, err := am.decrypt(ctx, encrypted)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt secure setting %q of receiver %q: %w", k, r.Name, err)
+				}
+				gr.SecureSettings[k] = string(decrypted)
+			}
+		}
+	}
+	return nil
+}
+
+// TestTemplate tests the given template using the remote Alertmanager.
+// If the remote Alertmanager does not support testing templates, it is tested by the embedded Alertmanager.
 func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
-	return &notifier.TestTemplatesResults{}, nil
+	res, err := am.testTemplate(ctx, c)
+	if errors.Is(err, errNotSupported) && am.local != nil {
+		am.log.Debug("Testing template with the embedded Alertmanager", "reason", err)
+		return am.local.TestTemplate(ctx, c)
+	}
+	return res, err
+}
+
+func (am *Alertmanager) testTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
+	for _, alert := range c.Alerts {
+		notifier.AddDefaultLabelsAndAnnotations(alert)
+	}
+
+	var res apimodels.TestTemplatesResults
+	if _, err := am.sendRequest(ctx, testTemplatesPath, c, &res); err != nil {
+		return nil, err
+	}
+
+	result := &notifier.TestTemplatesResults{}
+	for _, r := range res.Results {
+		result.Results = append(result.Results, alertingNotify.TestTemplatesResult{
+			Name: r.Name,
+			Text: r.Text,
+		})
+	}
+	for _, e := range res.Errors {
+		result.Errors = append(result.Errors, alertingNotify.TestTemplatesErrorResult{
+			Name:  e.Name,
+			Kind:  alertingNotify.TemplateErrorKind(e.Kind),
+			Error: errors.New(e.Message),
+		})
+	}
+	return result, nil
+}
+
+// sendRequest posts the payload as JSON to the given path of the remote Alertmanager and decodes the response into
+// result, unless it is nil. Responses with a status code other than 200 and the accepted ones are returned as error. It returns
+// errNotSupported if the remote Alertmanager does not implement the endpoint.
+func (am *Alertmanager) sendRequest(ctx context.Context, path string, payload, result any, accepted ...int) (int, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding request: %w", err)
+	}
+
+	u := strings.TrimSuffix(strings.TrimSuffix(am.url, "/"), "/alertmanager") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := am.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			am.log.Warn("Error while closing body", "err", err)
+		}
+	}()
+
+	switch res.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return res.StatusCode, fmt.Errorf("%w: status code %d", errNotSupported, res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, fmt.Errorf("error reading request response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK && !slices.Contains(accepted, res.StatusCode) {
+		return res.StatusCode, fmt.Errorf("request failed with status code %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if result == nil {
+		return res.StatusCode, nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return res.StatusCode, fmt.Errorf("error decoding response with status code %d: %w", res.StatusCode, err)
+	}
+	return res.StatusCode, nil
 }
 
 func (am *Alertmanager) StopAndWait() {
 	am.sender.Stop()
+	if am.local != nil {
+		am.local.StopAndWait()
+	}
 }
 
 func (am *Alertmanager) Ready() bool {
@@ -2,15 +2,25 @@ package remote
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-openapi/strfmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/util"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -66,6 +76,51 @@ func TestNewAlertmanager(t *testing.T) {
 	}
 }
 
+func TestApplyConfig(t *testing.T) {
+	var received []userGrafanaConfig
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, configPath, r.URL.Path)
+		var cfg userGrafanaConfig
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&cfg))
+		received = append(received, cfg)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	local := &fakeLocalAlertmanager{}
+	am := newTestAlertmanager(t, server.URL+"/alertmanager", local)
+	am.ready = true
+	am.decrypt = func(_ context.Context, payload []byte) ([]byte, error) {
+		return append([]byte("decrypted-"), payload...), nil
+	}
+
+	rawConfig := fmt.Sprintf(`{"alertmanager_config": {"route": {"receiver": "slack"}, "receivers": [{"name": "slack", "grafana_managed_receiver_configs": [{"uid": "uid", "name": "slack", "type": "slack", "settings": {}, "secureSettings": {"token": %q}}]}]}}`,
+		base64.StdEncoding.EncodeToString([]byte("encrypted")))
+	config := &models.AlertConfiguration{AlertmanagerConfiguration: rawConfig, ConfigurationHash: "hash", CreatedAt: 1}
+	require.NoError(t, am.ApplyConfig(context.Background(), config))
+	require.Equal(t, []*models.AlertConfiguration{config}, local.applied)
+	require.Len(t, received, 1)
+	require.Equal(t, "hash", received[0].Hash)
+	require.Equal(t, int64(1), received[0].CreatedAt)
+
+	sent, err := notifier.Load([]byte(received[0].GrafanaAlertmanagerConfig))
+	require.NoError(t, err)
+	require.Equal(t, "decrypted-encrypted", sent.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+
+	t.Run("does not send the same configuration again", func(t *testing.T) {
+		require.NoError(t, am.ApplyConfig(context.Background(), config))
+		require.Len(t, received, 1)
+	})
+
+	t.Run("sends a changed configuration", func(t *testing.T) {
+		changed := *config
+		changed.ConfigurationHash = "changed"
+		require.NoError(t, am.ApplyConfig(context.Background(), &changed))
+		require.Len(t, received, 2)
+		require.Equal(t, "changed", received[1].Hash)
+	})
+}
+
 func TestIntegrationRemoteAlertmanagerSilences(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -241,6 +296,271 @@ func TestIntegrationRemoteAlertmanagerReceivers(t *testing.T) {
 	}, 16*time.Second, 1*time.Second)
 }
 
+func TestTestReceivers(t *testing.T) {
+	body := apimodels.TestReceiversConfigBodyParams{
+		Receivers: []*apimodels.PostableApiReceiver{{
+			Receiver: config.Receiver{Name: "slack"},
+			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
+				GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{{
+					UID:            "uid",
+					Name:           "slack",
+					Type:           "slack",
+					SecureSettings: map[string]string{"token": base64.StdEncoding.EncodeToString([]byte("encrypted"))},
+				}},
+			},
+		}},
+	}
+
+	t.Run("tests receivers with the remote Alertmanager", func(t *testing.T) {
+		var received apimodels.TestReceiversConfigBodyParams
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, testReceiversPath, r.URL.Path)
+			require.Equal(t, "1234", r.Header.Get("X-Scope-OrgID"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusMultiStatus)
+			require.NoError(t, json.NewEncoder(w).Encode(apimodels.TestReceiversResult{
+				Alert: apimodels.TestReceiversConfigAlertParams{Labels: model.LabelSet{"alertname": "TestAlert"}},
+				Receivers: []apimodels.TestReceiverResult{{
+					Name:    "slack",
+					Configs: []apimodels.TestReceiverConfigResult{{Name: "slack", UID: "uid", Status: "failed", Error: "invalid token"}},
+				}},
+			}))
+		}))
+		t.Cleanup(server.Close)
+
+		am := newTestAlertmanager(t, server.URL+"/alertmanager", nil)
+		am.decrypt = func(_ context.Context, payload []byte) ([]byte, error) {
+			return append([]byte("decrypted-"), payload...), nil
+		}
+		res, err := am.TestReceivers(context.Background(), body)
+		require.NoError(t, err)
+		require.Equal(t, "decrypted-encrypted", received.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+		require.Equal(t, model.LabelSet{"alertname": "TestAlert"}, res.Alert.Labels)
+		require.Len(t, res.Receivers, 1)
+		require.Equal(t, "slack", res.Receivers[0].Name)
+		require.EqualError(t, res.Receivers[0].Configs[0].Error, "invalid token")
+		// The request must not be modified.
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("encrypted")), body.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+	})
+
+	t.Run("falls back to the embedded Alertmanager if the endpoint does not exist", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		local := &fakeLocalAlertmanager{receiversResult: &notifier.TestReceiversResult{Receivers: []notifier.TestReceiverResult{{Name: "local"}}}}
+		am := newTestAlertmanager(t, server.URL, local)
+		res, err := am.TestReceivers(context.Background(), body)
+		require.NoError(t, err)
+		require.Equal(t, "local", res.Receivers[0].Name)
+	})
+
+	t.Run("falls back to the embedded Alertmanager if secure settings cannot be decrypted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("secure settings must not be sent encrypted")
+		}))
+		t.Cleanup(server.Close)
+
+		local := &fakeLocalAlertmanager{receiversResult: &notifier.TestReceiversResult{Receivers: []notifier.TestReceiverResult{{Name: "local"}}}}
+		am := newTestAlertmanager(t, server.URL, local)
+		res, err := am.TestReceivers(context.Background(), body)
+		require.NoError(t, err)
+		require.Equal(t, "local", res.Receivers[0].Name)
+	})
+
+	t.Run("returns errors of the remote Alertmanager", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+
+		am := newTestAlertmanager(t, server.URL, &fakeLocalAlertmanager{})
+		_, err := am.TestReceivers(context.Background(), apimodels.TestReceiversConfigBodyParams{})
+		require.ErrorContains(t, err, "status code 500: internal error")
+	})
+}
+
+func TestTestTemplate(t *testing.T) {
+	body := apimodels.TestTemplatesConfigBodyParams{
+		Alerts:   []*amv2.PostableAlert{{Alert: amv2.Alert{Labels: amv2.LabelSet{"instance": "a"}}}},
+		Template: `{{ define "test" }}{{ .CommonLabels.alertname }}{{ end }}`,
+		Name:     "test",
+	}
+
+	t.Run("tests the template with the remote Alertmanager", func(t *testing.T) {
+		var received apimodels.TestTemplatesConfigBodyParams
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, testTemplatesPath, r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			require.NoError(t, json.NewEncoder(w).Encode(apimodels.TestTemplatesResults{
+				Results: []apimodels.TestTemplatesResult{{Name: "test", Text: "alert title"}},
+				Errors:  []apimodels.TestTemplatesErrorResult{{Name: "other", Kind: apimodels.ExecutionError, Message: "failed"}},
+			}))
+		}))
+		t.Cleanup(server.Close)
+
+		am := newTestAlertmanager(t, server.URL, nil)
+		res, err := am.TestTemplate(context.Background(), body)
+		require.NoError(t, err)
+		require.Equal(t, "alert title", received.Alerts[0].Labels["alertname"])
+		require.Equal(t, "a", received.Alerts[0].Labels["instance"])
+		require.Len(t, res.Results, 1)
+		require.Equal(t, "alert title", res.Results[0].Text)
+		require.Len(t, res.Errors, 1)
+		require.EqualError(t, res.Errors[0].Error, "failed")
+	})
+
+	t.Run("falls back to the embedded Alertmanager if the endpoint does not exist", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		local := &fakeLocalAlertmanager{templatesResult: &notifier.TestTemplatesResults{}}
+		am := newTestAlertmanager(t, server.URL, local)
+		res, err := am.TestTemplate(context.Background(), body)
+		require.NoError(t, err)
+		require.Same(t, local.templatesResult, res)
+	})
+
+	t.Run("returns an error if the endpoint does not exist and there is no embedded Alertmanager", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		am := newTestAlertmanager(t, server.URL, nil)
+		_, err := am.TestTemplate(context.Background(), body)
+		require.ErrorIs(t, err, errNotSupported)
+	})
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Run("returns the status of the remote Alertmanager", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v2/status", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{
+				"cluster": {"status": "ready", "peers": []},
+				"config": {"original": "route:\n  receiver: test\nreceivers:\n- name: test\n"},
+				"uptime": "2023-01-01T00:00:00.000Z",
+				"versionInfo": {"branch": "main", "buildDate": "", "buildUser": "", "goVersion": "", "revision": "", "version": "1.0.0"}
+			}`))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		am := newTestAlertmanager(t, server.URL, nil)
+		status := am.GetStatus()
+		require.Equal(t, "ready", *status.Cluster.Status)
+		require.Equal(t, "1.0.0", *status.VersionInfo.Version)
+		require.Equal(t, "test", status.Config.Route.Receiver)
+	})
+
+	t.Run("returns the status of the embedded Alertmanager if the request fails", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		version := "local"
+		local := &fakeLocalAlertmanager{status: apimodels.GettableStatus{VersionInfo: &amv2.VersionInfo{Version: &version}}}
+		am := newTestAlertmanager(t, server.URL, local)
+		require.Equal(t, local.status, am.GetStatus())
+	})
+}
+
+func newTestAlertmanager(t *testing.T, url string, local notifier.Alertmanager) *Alertmanager {
+	t.Helper()
+	am, err := NewAlertmanager(AlertmanagerConfig{URL: url, TenantID: "1234", Local: local}, 1)
+	require.NoError(t, err)
+	t.Cleanup(am.sender.Stop)
+	return am
+}
+
+// fakeLocalAlertmanager is an embedded Alertmanager that only implements the methods used by the remote Alertmanager.
+type fakeLocalAlertmanager struct {
+	notifier.Alertmanager
+	receiversResult *notifier.TestReceiversResult
+	templatesResult *notifier.TestTemplatesResults
+	status          apimodels.GettableStatus
+	alerts          apimodels.GettableAlerts
+	applied         []*models.AlertConfiguration
+	stopped         bool
+
+	silenceMtx sync.Mutex
+	silences   apimodels.GettableSilences
+}
+
+func (f *fakeLocalAlertmanager) ApplyConfig(_ context.Context, config *models.AlertConfiguration) error {
+	f.applied = append(f.applied, config)
+	return nil
+}
+
+func (f *fakeLocalAlertmanager) SaveAndApplyDefaultConfig(_ context.Context) error {
+	return nil
+}
+
+func (f *fakeLocalAlertmanager) CreateSilence(_ context.Context, silence *apimodels.PostableSilence) (string, error) {
+	f.silenceMtx.Lock()
+	defer f.silenceMtx.Unlock()
+	if s := f.findSilence(silence.ID); s != nil {
+		s.Silence = silence.Silence
+		return silence.ID, nil
+	}
+	id := util.GenerateShortUID()
+	state := amv2.SilenceStatusStateActive
+	f.silences = append(f.silences, &amv2.GettableSilence{ID: &id, Status: &amv2.SilenceStatus{State: &state}, Silence: silence.Silence})
+	return id, nil
+}
+
+func (f *fakeLocalAlertmanager) DeleteSilence(_ context.Context, id string) error {
+	f.silenceMtx.Lock()
+	defer f.silenceMtx.Unlock()
+	if s := f.findSilence(id); s != nil {
+		state := amv2.SilenceStatusStateExpired
+		s.Status = &amv2.SilenceStatus{State: &state}
+	}
+	return nil
+}
+
+func (f *fakeLocalAlertmanager) GetSilence(_ context.Context, id string) (apimodels.GettableSilence, error) {
+	f.silenceMtx.Lock()
+	defer f.silenceMtx.Unlock()
+	if s := f.findSilence(id); s != nil {
+		return *s, nil
+	}
+	return apimodels.GettableSilence{}, fmt.Errorf("silence %s not found", id)
+}
+
+func (f *fakeLocalAlertmanager) findSilence(id string) *apimodels.GettableSilence {
+	for _, s := range f.silences {
+		if s.ID != nil && *s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *fakeLocalAlertmanager) GetAlerts(_ context.Context, _, _, _ bool, _ []string, _ string) (apimodels.GettableAlerts, error) {
+	return f.alerts, nil
+}
+
+func (f *fakeLocalAlertmanager) ListSilences(_ context.Context, _ []string) (apimodels.GettableSilences, error) {
+	f.silenceMtx.Lock()
+	defer f.silenceMtx.Unlock()
+	return append(apimodels.GettableSilences{}, f.silences...), nil
+}
+
+func (f *fakeLocalAlertmanager) StopAndWait() {
+	f.stopped = true
+}
+
+func (f *fakeLocalAlertmanager) TestReceivers(_ context.Context, _ apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error) {
+	return f.receiversResult, nil
+}
+
+func (f *fakeLocalAlertmanager) TestTemplate(_ context.Context, _ apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
+	return f.templatesResult, nil
+}
+
+func (f *fakeLocalAlertmanager) GetStatus() apimodels.GettableStatus {
+	return f.status
+}
+
 func genSilence(createdBy string) apimodels.PostableSilence {
 	starts := strfmt.DateTime(time.Now().Add(time.Duration(rand.Int63n(9)+1) * time.Second))
 	ends := strfmt.DateTime(time.Now().Add(time.Duration(rand.Int63n(9)+10) * time.Second))
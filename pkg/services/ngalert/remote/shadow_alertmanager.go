@@ -0,0 +1,521 @@
+package remote
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// mirrorTimeout is the maximum time an operation of the internal Alertmanager waits for it to be mirrored.
+const mirrorTimeout = 10 * time.Second
+
+type divergenceType string
+
+const (
+	// divergenceMissingAlert is an alert of the internal Alertmanager that the remote one does not have.
+	divergenceMissingAlert divergenceType = "missing_alert"
+	// divergenceUnexpectedAlert is an alert of the remote Alertmanager that the internal one does not have.
+	divergenceUnexpectedAlert divergenceType = "unexpected_alert"
+	// divergenceAlertState is an alert that is active in one Alertmanager but silenced or inhibited in the other.
+	divergenceAlertState divergenceType = "alert_state"
+	// divergenceRouting is an alert that is routed to different receivers.
+	divergenceRouting divergenceType = "routing"
+	// divergenceMissingSilence is an active silence of the internal Alertmanager that the remote one does not have.
+	divergenceMissingSilence divergenceType = "missing_silence"
+	// divergenceUnexpectedSilence is an active silence of the remote Alertmanager that the internal one does not have.
+	divergenceUnexpectedSilence divergenceType = "unexpected_silence"
+)
+
+var divergenceTypes = []divergenceType{
+	divergenceMissingAlert,
+	divergenceUnexpectedAlert,
+	divergenceAlertState,
+	divergenceRouting,
+	divergenceMissingSilence,
+	divergenceUnexpectedSilence,
+}
+
+type divergence struct {
+	typ      divergenceType
+	subject  string
+	internal string
+	remote   string
+}
+
+func (d divergence) key() string {
+	return string(d.typ) + "/" + d.subject + "/" + d.internal + "/" + d.remote
+}
+
+// ShadowAlertmanager keeps the internal Alertmanager authoritative and mirrors configuration, silences and alerts to
+// the remote Alertmanager. All reads are served by the internal Alertmanager. The active silences of the remote
+// Alertmanager are synced with the internal one on start. Silences are matched by their content, as the same silence
+// has different IDs in both Alertmanagers. Both Alertmanagers are compared periodically and divergences in routing
+// decisions and notification state are reported in logs and metrics, so that the remote Alertmanager can be
+// validated before it replaces the internal one.
+type ShadowAlertmanager struct {
+	notifier.Alertmanager
+
+	log           log.Logger
+	orgID         int64
+	remote        *Alertmanager
+	metrics       *metrics.RemoteAlertmanager
+	interval      time.Duration
+	defaultConfig string
+
+	// silenceMtx serializes changes to silences, so that the copy of a silence in the remote Alertmanager can be
+	// looked up by its content, and so that silences are not changed while they are synced.
+	silenceMtx sync.Mutex
+
+	// observed holds the divergences of the previous comparison. A divergence is only reported if it is observed
+	// by two consecutive comparisons, as alerts and silences are mirrored asynchronously.
+	observed map[string]struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewShadowAlertmanager(internal notifier.Alertmanager, remote *Alertmanager, orgID int64, defaultConfig string, compareInterval time.Duration, m *metrics.RemoteAlertmanager) *ShadowAlertmanager {
+	ctx, cancel := context.WithCancel(context.Background())
+	am := &ShadowAlertmanager{
+		Alertmanager:  internal,
+		log:           log.New("ngalert.remote.shadow", "org", orgID),
+		orgID:         orgID,
+		remote:        remote,
+		metrics:       m,
+		interval:      compareInterval,
+		defaultConfig: defaultConfig,
+		observed:      map[string]struct{}{},
+		cancel:        cancel,
+	}
+
+	am.wg.Add(1)
+	go func() {
+		defer am.wg.Done()
+		am.run(ctx)
+	}()
+	return am
+}
+
+func (am *ShadowAlertmanager) ApplyConfig(ctx context.Context, config *models.AlertConfiguration) error {
+	if err := am.Alertmanager.ApplyConfig(ctx, config); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+	defer cancel()
+	if err := am.remote.ApplyConfig(ctx, config); err != nil {
+		am.mirrorFailed("apply_config", err)
+	}
+	return nil
+}
+
+func (am *ShadowAlertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig) error {
+	if err := am.Alertmanager.SaveAndApplyConfig(ctx, cfg); err != nil {
+		return err
+	}
+
+	rawConfig, err := json.Marshal(cfg)
+	if err != nil {
+		am.mirrorFailed("apply_config", err)
+		return nil
+	}
+	am.mirrorConfig(ctx, rawConfig, false)
+	return nil
+}
+
+func (am *ShadowAlertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
+	if err := am.Alertmanager.SaveAndApplyDefaultConfig(ctx); err != nil {
+		return err
+	}
+	am.mirrorConfig(ctx, []byte(am.defaultConfig), true)
+	return nil
+}
+
+// mirrorConfig applies a configuration that was saved to the internal Alertmanager to the remote one.
+func (am *ShadowAlertmanager) mirrorConfig(ctx context.Context, rawConfig []byte, isDefault bool) {
+	ctx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+	defer cancel()
+	err := am.remote.ApplyConfig(ctx, &models.AlertConfiguration{
+		AlertmanagerConfiguration: string(rawConfig),
+		ConfigurationHash:         fmt.Sprintf("%x", md5.Sum(rawConfig)),
+		CreatedAt:                 time.Now().Unix(),
+		Default:                   isDefault,
+		OrgID:                     am.orgID,
+	})
+	if err != nil {
+		am.mirrorFailed("apply_config", err)
+	}
+}
+
+func (am *ShadowAlertmanager) CreateSilence(ctx context.Context, silence *apimodels.PostableSilence) (string, error) {
+	am.silenceMtx.Lock()
+	defer am.silenceMtx.Unlock()
+
+	// The silence might update an existing one, whose copy is found by the content of the silence before the update.
+	var previous *apimodels.GettableSilence
+	if silence.ID != "" {
+		if s, err := am.Alertmanager.GetSilence(ctx, silence.ID); err == nil {
+			previous = &s
+		}
+	}
+
+	id, err := am.Alertmanager.CreateSilence(ctx, silence)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+	defer cancel()
+
+	mirrored := *silence
+	mirrored.ID = ""
+	if previous != nil {
+		remoteID, err := am.findRemoteSilence(ctx, silenceKey(previous))
+		if err != nil {
+			am.mirrorFailed("create_silence", err)
+			return id, nil
+		}
+		mirrored.ID = remoteID
+	}
+
+	if _, err := am.remote.CreateSilence(ctx, &mirrored); err != nil {
+		am.mirrorFailed("create_silence", err)
+	}
+	return id, nil
+}
+
+func (am *ShadowAlertmanager) DeleteSilence(ctx context.Context, silenceID string) error {
+	am.silenceMtx.Lock()
+	defer am.silenceMtx.Unlock()
+
+	silence, getErr := am.Alertmanager.GetSilence(ctx, silenceID)
+	if err := am.Alertmanager.DeleteSilence(ctx, silenceID); err != nil {
+		return err
+	}
+	if getErr != nil {
+		am.mirrorFailed("delete_silence", getErr)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+	defer cancel()
+	remoteID, err := am.findRemoteSilence(ctx, silenceKey(&silence))
+	if err != nil {
+		am.mirrorFailed("delete_silence", err)
+		return nil
+	}
+	if remoteID == "" {
+		am.mirrorFailed("delete_silence", fmt.Errorf("silence %s was not found in the remote Alertmanager", silenceID))
+		return nil
+	}
+	if err := am.remote.DeleteSilence(ctx, remoteID); err != nil {
+		am.mirrorFailed("delete_silence", err)
+	}
+	return nil
+}
+
+// findRemoteSilence returns the ID of the active silence of the remote Alertmanager with the given key,
+// or an empty string if there is none.
+func (am *ShadowAlertmanager) findRemoteSilence(ctx context.Context, key string) (string, error) {
+	silences, err := am.remote.ListSilences(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get silences of the remote Alertmanager: %w", err)
+	}
+	for _, silence := range silences {
+		if isActiveSilence(silence) && silenceKey(silence) == key {
+			return stringValue(silence.ID), nil
+		}
+	}
+	return "", nil
+}
+
+// syncSilences creates the active silences of the internal Alertmanager that the remote one does not have, and
+// expires the active silences of the remote Alertmanager that the internal one does not have.
+func (am *ShadowAlertmanager) syncSilences(ctx context.Context) error {
+	am.silenceMtx.Lock()
+	defer am.silenceMtx.Unlock()
+
+	internal, err := am.Alertmanager.ListSilences(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get silences of the internal Alertmanager: %w", err)
+	}
+	remote, err := am.remote.ListSilences(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get silences of the remote Alertmanager: %w", err)
+	}
+
+	remoteIDs := make(map[string][]string, len(remote))
+	for _, silence := range remote {
+		if isActiveSilence(silence) {
+			key := silenceKey(silence)
+			remoteIDs[key] = append(remoteIDs[key], stringValue(silence.ID))
+		}
+	}
+
+	created := 0
+	for _, silence := range internal {
+		if !isActiveSilence(silence) {
+			continue
+		}
+		key := silenceKey(silence)
+		if len(remoteIDs[key]) > 0 {
+			remoteIDs[key] = remoteIDs[key][1:]
+			continue
+		}
+		if _, err := am.remote.CreateSilence(ctx, &apimodels.PostableSilence{Silence: silence.Silence}); err != nil {
+			return fmt.Errorf("failed to create silence in the remote Alertmanager: %w", err)
+		}
+		created++
+	}
+
+	deleted := 0
+	for _, ids := range remoteIDs {
+		for _, id := range ids {
+			if err := am.remote.DeleteSilence(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete silence %s of the remote Alertmanager: %w", id, err)
+			}
+			deleted++
+		}
+	}
+	am.log.Debug("Synced silences with the remote Alertmanager", "created", created, "deleted", deleted)
+	return nil
+}
+
+func (am *ShadowAlertmanager) PutAlerts(ctx context.Context, alerts apimodels.PostableAlerts) error {
+	if err := am.Alertmanager.PutAlerts(ctx, alerts); err != nil {
+		return err
+	}
+
+	if err := am.remote.PutAlerts(ctx, alerts); err != nil {
+		am.mirrorFailed("put_alerts", err)
+	}
+	return nil
+}
+
+func (am *ShadowAlertmanager) StopAndWait() {
+	am.cancel()
+	am.wg.Wait()
+	am.Alertmanager.StopAndWait()
+	am.remote.StopAndWait()
+}
+
+func (am *ShadowAlertmanager) mirrorFailed(operation string, err error) {
+	am.log.Warn("Failed to mirror operation to the remote Alertmanager", "operation", operation, "err", err)
+	am.metrics.ShadowMirrorFailures.WithLabelValues(fmt.Sprint(am.orgID), operation).Inc()
+}
+
+func (am *ShadowAlertmanager) run(ctx context.Context) {
+	syncCtx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+	if err := am.syncSilences(syncCtx); err != nil {
+		am.mirrorFailed("sync_silences", err)
+	}
+	cancel()
+
+	ticker := time.NewTicker(am.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			am.compareAndReport(ctx)
+		}
+	}
+}
+
+// compareAndReport compares the internal and the remote Alertmanager and reports the divergences that were also
+// observed by the previous comparison.
+func (am *ShadowAlertmanager) compareAndReport(ctx context.Context) {
+	org := fmt.Sprint(am.orgID)
+	am.metrics.ShadowComparisons.WithLabelValues(org).Inc()
+
+	ctx, cancel := context.WithTimeout(ctx, am.interval)
+	defer cancel()
+	divergences, err := am.compare(ctx)
+	if err != nil {
+		am.log.Warn("Failed to compare the internal and the remote Alertmanager", "err", err)
+		am.metrics.ShadowComparisonsFailed.WithLabelValues(org).Inc()
+		return
+	}
+
+	observed := make(map[string]struct{}, len(divergences))
+	counts := make(map[divergenceType]int, len(divergenceTypes))
+	for _, d := range divergences {
+		observed[d.key()] = struct{}{}
+		if _, ok := am.observed[d.key()]; !ok {
+			continue
+		}
+		counts[d.typ]++
+		am.log.Debug("Internal and remote Alertmanager diverge", "type", d.typ, "subject", d.subject, "internal", d.internal, "remote", d.remote)
+	}
+	am.observed = observed
+
+	total := 0
+	logCtx := make([]any, 0, 2*len(divergenceTypes))
+	for _, typ := range divergenceTypes {
+		am.metrics.ShadowDivergences.WithLabelValues(org, string(typ)).Set(float64(counts[typ]))
+		total += counts[typ]
+		logCtx = append(logCtx, string(typ), counts[typ])
+	}
+	am.metrics.ShadowLastComparisonTime.WithLabelValues(org).SetToCurrentTime()
+	if total > 0 {
+		am.log.Warn("Internal and remote Alertmanager diverge", logCtx...)
+	}
+}
+
+// compare returns the differences between the alerts and the active silences of the internal and the remote Alertmanager.
+func (am *ShadowAlertmanager) compare(ctx context.Context) ([]divergence, error) {
+	internalAlerts, err := am.Alertmanager.GetAlerts(ctx, true, true, true, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts of the internal Alertmanager: %w", err)
+	}
+	remoteAlerts, err := am.remote.GetAlerts(ctx, true, true, true, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts of the remote Alertmanager: %w", err)
+	}
+	internalSilences, err := am.Alertmanager.ListSilences(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silences of the internal Alertmanager: %w", err)
+	}
+	remoteSilences, err := am.remote.ListSilences(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silences of the remote Alertmanager: %w", err)
+	}
+
+	divergences := compareAlerts(internalAlerts, remoteAlerts)
+	return append(divergences, compareSilences(internalSilences, remoteSilences)...), nil
+}
+
+func compareAlerts(internal, remote apimodels.GettableAlerts) []divergence {
+	remoteByFingerprint := make(map[string]*amv2.GettableAlert, len(remote))
+	for _, alert := range remote {
+		remoteByFingerprint[stringValue(alert.Fingerprint)] = alert
+	}
+
+	var divergences []divergence
+	for _, alert := range internal {
+		fingerprint := stringValue(alert.Fingerprint)
+		subject := labelsString(alert.Labels)
+		remoteAlert, ok := remoteByFingerprint[fingerprint]
+		if !ok {
+			divergences = append(divergences, divergence{typ: divergenceMissingAlert, subject: subject})
+			continue
+		}
+		delete(remoteByFingerprint, fingerprint)
+
+		if internalState, remoteState := alertState(alert), alertState(remoteAlert); internalState != remoteState {
+			divergences = append(divergences, divergence{typ: divergenceAlertState, subject: subject, internal: internalState, remote: remoteState})
+		}
+		if internalReceivers, remoteReceivers := alertReceivers(alert), alertReceivers(remoteAlert); internalReceivers != remoteReceivers {
+			divergences = append(divergences, divergence{typ: divergenceRouting, subject: subject, internal: internalReceivers, remote: remoteReceivers})
+		}
+	}
+	for _, alert := range remoteByFingerprint {
+		divergences = append(divergences, divergence{typ: divergenceUnexpectedAlert, subject: labelsString(alert.Labels)})
+	}
+	return divergences
+}
+
+func compareSilences(internal, remote apimodels.GettableSilences) []divergence {
+	remoteSilences := make(map[string]int, len(remote))
+	for _, silence := range remote {
+		if isActiveSilence(silence) {
+			remoteSilences[silenceKey(silence)]++
+		}
+	}
+
+	var divergences []divergence
+	for _, silence := range internal {
+		if !isActiveSilence(silence) {
+			continue
+		}
+		key := silenceKey(silence)
+		if remoteSilences[key] == 0 {
+			divergences = append(divergences, divergence{typ: divergenceMissingSilence, subject: key})
+			continue
+		}
+		remoteSilences[key]--
+	}
+	for key, count := range remoteSilences {
+		for i := 0; i < count; i++ {
+			divergences = append(divergences, divergence{typ: divergenceUnexpectedSilence, subject: key})
+		}
+	}
+	return divergences
+}
+
+func alertState(alert *amv2.GettableAlert) string {
+	if alert.Status == nil {
+		return ""
+	}
+	return stringValue(alert.Status.State)
+}
+
+// alertReceivers returns the sorted names of the receivers the alert is routed to.
+func alertReceivers(alert *amv2.GettableAlert) string {
+	names := make([]string, 0, len(alert.Receivers))
+	for _, r := range alert.Receivers {
+		names = append(names, stringValue(r.Name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func isActiveSilence(silence *amv2.GettableSilence) bool {
+	return silence.Status == nil || stringValue(silence.Status.State) != amv2.SilenceStatusStateExpired
+}
+
+// silenceKey identifies a silence by its content, as the same silence has different IDs in both Alertmanagers.
+func silenceKey(silence *amv2.GettableSilence) string {
+	matchers := make([]string, 0, len(silence.Matchers))
+	for _, m := range silence.Matchers {
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		op := "="
+		switch {
+		case isEqual && isRegex:
+			op = "=~"
+		case !isEqual && isRegex:
+			op = "!~"
+		case !isEqual:
+			op = "!="
+		}
+		matchers = append(matchers, fmt.Sprintf("%s%s%q", stringValue(m.Name), op, stringValue(m.Value)))
+	}
+	sort.Strings(matchers)
+
+	var endsAt string
+	if silence.EndsAt != nil {
+		endsAt = time.Time(*silence.EndsAt).UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("{%s} until %s by %s: %s", strings.Join(matchers, ", "), endsAt, stringValue(silence.CreatedBy), stringValue(silence.Comment))
+}
+
+func labelsString(labels amv2.LabelSet) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
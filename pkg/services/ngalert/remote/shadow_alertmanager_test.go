@@ -0,0 +1,286 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeRemoteAlertmanager serves the alerts, silences and configuration of a remote Alertmanager.
+type fakeRemoteAlertmanager struct {
+	mtx      sync.Mutex
+	alerts   apimodels.GettableAlerts
+	silences apimodels.GettableSilences
+	created  int
+	deleted  []string
+	configs  []userGrafanaConfig
+}
+
+func (f *fakeRemoteAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/alerts":
+		_ = json.NewEncoder(w).Encode(f.alerts)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		_ = json.NewEncoder(w).Encode(f.silences)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		var silence apimodels.PostableSilence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id := silence.ID
+		if s := f.silence(id); s != nil {
+			s.Silence = silence.Silence
+		} else {
+			f.created++
+			id = fmt.Sprintf("remote-%d", f.created)
+			state := amv2.SilenceStatusStateActive
+			f.silences = append(f.silences, &amv2.GettableSilence{ID: &id, Status: &amv2.SilenceStatus{State: &state}, Silence: silence.Silence})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": id})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")
+		if s := f.silence(id); s != nil {
+			state := amv2.SilenceStatusStateExpired
+			s.Status = &amv2.SilenceStatus{State: &state}
+		}
+		f.deleted = append(f.deleted, id)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPost && r.URL.Path == configPath:
+		var cfg userGrafanaConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.configs = append(f.configs, cfg)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRemoteAlertmanager) silence(id string) *amv2.GettableSilence {
+	for _, s := range f.silences {
+		if *s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// activeSilences returns the keys of the active silences of the remote Alertmanager.
+func (f *fakeRemoteAlertmanager) activeSilences() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var keys []string
+	for _, s := range f.silences {
+		if isActiveSilence(s) {
+			keys = append(keys, silenceKey(s))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newTestShadowAlertmanager(t *testing.T, internal *fakeLocalAlertmanager, remoteSilences ...*amv2.GettableSilence) (*ShadowAlertmanager, *fakeRemoteAlertmanager, *metrics.RemoteAlertmanager) {
+	t.Helper()
+	fakeRemote := &fakeRemoteAlertmanager{silences: remoteSilences}
+	server := httptest.NewServer(fakeRemote)
+	t.Cleanup(server.Close)
+
+	m := metrics.NewRemoteAlertmanagerMetrics(prometheus.NewPedanticRegistry())
+	remote := newTestAlertmanager(t, server.URL, nil)
+	remote.ready = true
+	// The comparison is triggered by the tests.
+	am := NewShadowAlertmanager(internal, remote, 1, setting.GetAlertmanagerDefaultConfiguration(), time.Hour, m)
+	t.Cleanup(func() {
+		am.cancel()
+		am.wg.Wait()
+	})
+	return am, fakeRemote, m
+}
+
+func TestShadowAlertmanager_SyncSilences(t *testing.T) {
+	a, b := genGettableSilence("a", "active"), genGettableSilence("b", "active")
+	internal := &fakeLocalAlertmanager{silences: apimodels.GettableSilences{a, b, genGettableSilence("c", "expired")}}
+	_, fakeRemote, _ := newTestShadowAlertmanager(t, internal, genGettableSilence("a", "active"), genGettableSilence("d", "active"))
+
+	// The remote Alertmanager gets the missing silence and loses the one the internal Alertmanager does not have.
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{silenceKey(a), silenceKey(b)}, fakeRemote.activeSilences())
+	}, 5*time.Second, 10*time.Millisecond)
+	fakeRemote.mtx.Lock()
+	defer fakeRemote.mtx.Unlock()
+	require.Equal(t, []string{"id-d"}, fakeRemote.deleted)
+}
+
+func TestShadowAlertmanager_Silences(t *testing.T) {
+	am, fakeRemote, m := newTestShadowAlertmanager(t, &fakeLocalAlertmanager{})
+
+	silence := genSilence("test")
+	id, err := am.CreateSilence(context.Background(), &silence)
+	require.NoError(t, err)
+	require.Equal(t, []string{silenceKey(&amv2.GettableSilence{Silence: silence.Silence})}, fakeRemote.activeSilences())
+
+	t.Run("updates the copy of the silence", func(t *testing.T) {
+		updated := genSilence("test")
+		comment := "updated comment"
+		updated.Comment = &comment
+		updated.ID = id
+		_, err := am.CreateSilence(context.Background(), &updated)
+		require.NoError(t, err)
+		require.Equal(t, []string{silenceKey(&amv2.GettableSilence{Silence: updated.Silence})}, fakeRemote.activeSilences())
+		require.Equal(t, 1, fakeRemote.created)
+	})
+
+	t.Run("deletes the copy of the silence", func(t *testing.T) {
+		require.NoError(t, am.DeleteSilence(context.Background(), id))
+		require.Empty(t, fakeRemote.activeSilences())
+		require.Equal(t, []string{"remote-1"}, fakeRemote.deleted)
+	})
+
+	t.Run("counts silences that cannot be deleted in the remote Alertmanager", func(t *testing.T) {
+		require.NoError(t, am.DeleteSilence(context.Background(), "unknown"))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.ShadowMirrorFailures.WithLabelValues("1", "delete_silence")))
+	})
+}
+
+func TestShadowAlertmanager_SaveAndApplyDefaultConfig(t *testing.T) {
+	am, fakeRemote, m := newTestShadowAlertmanager(t, &fakeLocalAlertmanager{})
+
+	require.NoError(t, am.SaveAndApplyDefaultConfig(context.Background()))
+	require.Zero(t, testutil.ToFloat64(m.ShadowMirrorFailures.WithLabelValues("1", "apply_config")))
+
+	fakeRemote.mtx.Lock()
+	defer fakeRemote.mtx.Unlock()
+	require.Len(t, fakeRemote.configs, 1)
+	require.True(t, fakeRemote.configs[0].Default)
+}
+
+func TestShadowAlertmanager_Compare(t *testing.T) {
+	internal := &fakeLocalAlertmanager{
+		alerts: apimodels.GettableAlerts{
+			genGettableAlert("a", amv2.AlertStatusStateActive, "slack"),
+			genGettableAlert("b", amv2.AlertStatusStateActive, "slack"),
+		},
+	}
+	am, fakeRemote, m := newTestShadowAlertmanager(t, internal)
+	fakeRemote.alerts = apimodels.GettableAlerts{
+		genGettableAlert("a", amv2.AlertStatusStateActive, "email"),
+		genGettableAlert("c", amv2.AlertStatusStateActive, "slack"),
+	}
+
+	divergences := func(typ divergenceType) float64 {
+		return testutil.ToFloat64(m.ShadowDivergences.WithLabelValues("1", string(typ)))
+	}
+
+	// Divergences are only reported if they persist.
+	am.compareAndReport(context.Background())
+	require.Equal(t, 1.0, testutil.ToFloat64(m.ShadowComparisons.WithLabelValues("1")))
+	for _, typ := range divergenceTypes {
+		require.Zero(t, divergences(typ))
+	}
+
+	am.compareAndReport(context.Background())
+	require.Equal(t, 1.0, divergences(divergenceRouting))
+	require.Equal(t, 1.0, divergences(divergenceMissingAlert))
+	require.Equal(t, 1.0, divergences(divergenceUnexpectedAlert))
+	require.Zero(t, divergences(divergenceAlertState))
+
+	// The divergences are resolved once the remote Alertmanager catches up.
+	fakeRemote.mtx.Lock()
+	fakeRemote.alerts = internal.alerts
+	fakeRemote.mtx.Unlock()
+	am.compareAndReport(context.Background())
+	for _, typ := range divergenceTypes {
+		require.Zero(t, divergences(typ))
+	}
+}
+
+func TestCompareAlerts(t *testing.T) {
+	internal := apimodels.GettableAlerts{
+		genGettableAlert("a", amv2.AlertStatusStateActive, "slack", "email"),
+		genGettableAlert("b", amv2.AlertStatusStateSuppressed, "slack"),
+	}
+	remote := apimodels.GettableAlerts{
+		genGettableAlert("b", amv2.AlertStatusStateActive, "slack"),
+		genGettableAlert("a", amv2.AlertStatusStateActive, "email", "slack"),
+	}
+	require.Equal(t, []divergence{
+		{typ: divergenceAlertState, subject: `{alertname="b"}`, internal: "suppressed", remote: "active"},
+	}, compareAlerts(internal, remote))
+}
+
+func TestCompareSilences(t *testing.T) {
+	s1, s2, s3 := genGettableSilence("a", "active"), genGettableSilence("b", "active"), genGettableSilence("c", "expired")
+	internal := apimodels.GettableSilences{s1, s2, s3}
+	remote := apimodels.GettableSilences{genGettableSilence("a", "pending")}
+
+	require.Equal(t, []divergence{
+		{typ: divergenceMissingSilence, subject: silenceKey(s2)},
+	}, compareSilences(internal, remote))
+	require.Equal(t, []divergence{
+		{typ: divergenceUnexpectedSilence, subject: silenceKey(s2)},
+	}, compareSilences(remote, internal))
+}
+
+func genGettableAlert(name, state string, receivers ...string) *amv2.GettableAlert {
+	now := strfmt.DateTime(time.Now())
+	fingerprint := "fingerprint-" + name
+	rcvs := make([]*amv2.Receiver, 0, len(receivers))
+	for _, r := range receivers {
+		r := r
+		rcvs = append(rcvs, &amv2.Receiver{Name: &r})
+	}
+	return &amv2.GettableAlert{
+		Annotations: amv2.LabelSet{},
+		EndsAt:      &now,
+		Fingerprint: &fingerprint,
+		Receivers:   rcvs,
+		StartsAt:    &now,
+		Status: &amv2.AlertStatus{
+			State:       &state,
+			SilencedBy:  []string{},
+			InhibitedBy: []string{},
+		},
+		UpdatedAt: &now,
+		Alert: amv2.Alert{
+			Labels: amv2.LabelSet{"alertname": name},
+		},
+	}
+}
+
+func genGettableSilence(value, state string) *amv2.GettableSilence {
+	silence := genSilence("test")
+	silence.Matchers[0].Value = &value
+	endsAt := strfmt.DateTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	silence.EndsAt = &endsAt
+	id := "id-" + value
+	return &amv2.GettableSilence{
+		ID:      &id,
+		Status:  &amv2.SilenceStatus{State: &state},
+		Silence: silence.Silence,
+	}
+}
@@ -3,6 +3,7 @@ package schedule
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
@@ -18,6 +19,9 @@ import (
 
 var errRuleDeleted = errors.New("rule deleted")
 
+// errRuleReassigned is the cause of stopping the evaluation of a rule that is evaluated by another replica now.
+var errRuleReassigned = errors.New("rule reassigned to another replica")
+
 type alertRuleInfoRegistry struct {
 	mu            sync.Mutex
 	alertRuleInfo map[models.AlertRuleKey]*alertRuleInfo
@@ -130,6 +134,8 @@ type evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// inactive is set if the evaluation is scheduled outside the active time intervals of the rule.
+	inactive bool
 }
 
 type alertRulesRegistry struct {
@@ -329,6 +335,24 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeLabels(rule.Labels)
 	writeString(rule.Condition)
 	writeQuery()
+	if rule.Record != nil {
+		writeString(rule.Record.Metric)
+		writeString(rule.Record.From)
+	}
+	for _, d := range rule.DependsOn {
+		writeString(d.RuleUID)
+		writeString(d.NamespaceUID)
+		writeString(d.RuleGroup)
+	}
+	// Time intervals do not contain types that fail to marshal.
+	if len(rule.ActiveTimeIntervals) > 0 {
+		b, _ := json.Marshal(rule.ActiveTimeIntervals)
+		writeBytes(b)
+	}
+	if len(rule.GroupActiveTimeIntervals) > 0 {
+		b, _ := json.Marshal(rule.GroupActiveTimeIntervals)
+		writeBytes(b)
+	}
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -342,6 +366,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
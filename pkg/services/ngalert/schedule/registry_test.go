@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -408,6 +409,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata",
 			ExecErrState:    "test-err",
 			For:             12,
+			KeepFiringFor:   13,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
 			},
@@ -415,6 +417,19 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				"key-label": "value-label",
 			},
 			IsPaused: false,
+			Record: &models.Record{
+				Metric: "test_metric",
+				From:   "1",
+			},
+			DependsOn: []models.RuleDependency{
+				{RuleUID: "upstream-uid"},
+			},
+			ActiveTimeIntervals: []timeinterval.TimeInterval{
+				{Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}}},
+			},
+			GroupActiveTimeIntervals: []timeinterval.TimeInterval{
+				{Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}}},
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -443,6 +458,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata2",
 			ExecErrState:    "test-err2",
 			For:             1141,
+			KeepFiringFor:   1142,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
@@ -450,6 +466,19 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				"key-label": "value-label23",
 			},
 			IsPaused: true,
+			Record: &models.Record{
+				Metric: "test_metric_2",
+				From:   "2",
+			},
+			DependsOn: []models.RuleDependency{
+				{NamespaceUID: "upstream-ns", RuleGroup: "upstream-group"},
+			},
+			ActiveTimeIntervals: []timeinterval.TimeInterval{
+				{Times: []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 120}}},
+			},
+			GroupActiveTimeIntervals: []timeinterval.TimeInterval{
+				{Times: []timeinterval.TimeRange{{StartMinute: 0, EndMinute: 30}}},
+			},
 		}
 
 		excludedFields := map[string]struct{}{
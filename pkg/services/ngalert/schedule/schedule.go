@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -21,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util/ticker"
@@ -91,6 +94,11 @@ type schedule struct {
 	schedulableAlertRules alertRulesRegistry
 
 	tracer tracing.Tracer
+
+	recordingWriter writer.Writer
+
+	// sharder partitions the rules across the replicas of Grafana. It is nil if every replica evaluates all rules.
+	sharder *ruleSharder
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -105,8 +113,12 @@ type SchedulerCfg struct {
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
+	RecordingWriter      writer.Writer
 	Tracer               tracing.Tracer
 	Log                  log.Logger
+	// Membership enables sharding of rule evaluation across the replicas it provides. If it is nil,
+	// all rules are evaluated by this replica.
+	Membership Membership
 }
 
 // NewScheduler returns a new schedule.
@@ -127,6 +139,15 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		recordingWriter:       cfg.RecordingWriter,
+	}
+
+	if sch.recordingWriter == nil {
+		sch.recordingWriter = writer.NoopWriter{}
+	}
+
+	if cfg.Membership != nil {
+		sch.sharder = newRuleSharder(cfg.Membership, cfg.Log, cfg.Metrics)
 	}
 
 	return &sch
@@ -166,6 +187,21 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 	sch.updateRulesMetrics(alertRules)
 }
 
+// releaseAlertRule stops evaluation of rules that are evaluated by another replica now. Unlike deleteAlertRule,
+// the rules stay schedulable and their state is kept in the database for the replica that takes over.
+func (sch *schedule) releaseAlertRule(keys ...ngmodels.AlertRuleKey) {
+	for _, key := range keys {
+		ruleInfo, ok := sch.registry.del(key)
+		if !ok {
+			// the rule was not evaluated by this replica since it started, the state was loaded at startup though.
+			sch.stateManager.ForgetRule(key)
+			continue
+		}
+		// the rule routine removes the state from the cache when it stops
+		ruleInfo.stop(errRuleReassigned)
+	}
+}
+
 func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 	dispatcherGroup, ctx := errgroup.WithContext(ctx)
 	for {
@@ -221,6 +257,11 @@ func (sch *schedule) updateRulesMetrics(alertRules []*ngmodels.AlertRule) {
 func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.Group, tick time.Time) ([]readyToRunItem, map[ngmodels.AlertRuleKey]struct{}, []ngmodels.AlertRuleKeyWithVersion) {
 	tickNum := tick.Unix() / int64(sch.baseInterval.Seconds())
 
+	// the rules are redistributed if replicas joined or left the cluster since the last tick
+	if sch.sharder != nil {
+		sch.sharder.update()
+	}
+
 	// update the local registry. If there was a difference between the previous state and the current new state, rulesDiff will contains keys of rules that were updated.
 	rulesDiff, err := sch.updateSchedulableAlertRules(ctx)
 	updated := rulesDiff.updated
@@ -245,8 +286,24 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
+	var released []ngmodels.AlertRuleKey
+	var dependencies map[ngmodels.AlertRuleKey]struct{}
+	if sch.sharder != nil {
+		dependencies = dependencyRules(alertRules)
+	}
+	owned := 0
 	for _, item := range alertRules {
 		key := item.GetKey()
+		if _, ok := dependencies[key]; !ok && !sch.sharder.owns(key) {
+			if sch.sharder.release(key) {
+				released = append(released, key)
+			}
+			// the rule is not deleted, it is evaluated by another replica
+			delete(registeredDefinitions, key)
+			continue
+		}
+		owned++
+		sch.sharder.claim(key)
 		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
 		// enforce minimum evaluation interval
@@ -258,7 +315,14 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 		if newRoutine && !invalidInterval {
+			// The state of all rules is loaded at startup. Rules that this replica takes over from another one later
+			// continue from the state that the other replica saved last.
+			takeOver := sch.sharder != nil && sch.sharder.generation > 1
+			rule := item
 			dispatcherGroup.Go(func() error {
+				if takeOver {
+					sch.stateManager.WarmRule(ruleInfo.ctx, rule)
+				}
 				return sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
 			})
 		}
@@ -288,6 +352,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 				scheduledAt: tick,
 				rule:        item,
 				folderTitle: folderTitle,
+				inactive:    !item.IsActiveAt(tick),
 			}})
 		}
 		if _, isUpdated := updated[key]; isUpdated && !isReadyToRun {
@@ -313,6 +378,14 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
+	if sch.sharder != nil {
+		sch.metrics.ShardOwnedAlertRules.Set(float64(owned))
+		if len(released) > 0 {
+			sch.log.Info("Stopping evaluation of rules that are evaluated by another replica now", "rules", len(released))
+			sch.releaseAlertRule(released...)
+		}
+	}
+
 	var step int64 = 0
 	if len(readyToRun) > 0 {
 		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
@@ -374,8 +447,65 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		notify(states)
 	}
 
+	// record evaluates a recording rule and writes the result of the recorded query or expression.
+	// Recording rules do not have state, so the state manager and the notification pipeline are skipped.
+	record := func(ctx context.Context, e *evaluation, logger log.Logger, span trace.Span) {
+		start := sch.clock.Now()
+
+		var frames data.Frames
+		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
+		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		if err == nil {
+			var resp *backend.QueryDataResponse
+			resp, err = ruleEval.EvaluateRaw(ctx, e.scheduledAt)
+			if err == nil {
+				result, ok := resp.Responses[e.rule.Record.From]
+				if !ok {
+					err = fmt.Errorf("no result for the recorded query or expression %s", e.rule.Record.From)
+				} else {
+					frames, err = result.Frames, result.Error
+				}
+			}
+		}
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to evaluate recording rule", "error", err, "duration", dur)
+			span.SetStatus(codes.Error, "rule evaluation failed")
+			span.RecordError(err)
+			return
+		}
+		logger.Debug("Recording rule evaluated", "frames", len(frames), "duration", dur)
+		span.AddEvent("rule evaluated", trace.WithAttributes(
+			attribute.Int64("frames", int64(len(frames))),
+		))
+
+		if ctx.Err() != nil { // check if the context is not cancelled. The evaluation can be a long-running task.
+			logger.Debug("Skip writing the result because the context has been cancelled")
+			return
+		}
+
+		start = sch.clock.Now()
+		if err := sch.recordingWriter.Write(ctx, e.rule.Record.Metric, e.scheduledAt, frames, e.rule.Labels); err != nil {
+			logger.Error("Failed to write the result of recording rule", "error", err)
+			span.SetStatus(codes.Error, "failed to write recording rule result")
+			span.RecordError(err)
+			return
+		}
+		span.AddEvent("results written")
+		sendDuration.Observe(sch.clock.Now().Sub(start).Seconds())
+	}
+
 	evaluate := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span trace.Span) {
 		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
+		if e.rule.IsRecordingRule() {
+			record(ctx, e, logger, span)
+			return
+		}
 		start := sch.clock.Now()
 
 		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
@@ -455,6 +585,8 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 
 	evalRunning := false
+	// inactive is set while the rule is evaluated outside its active time intervals.
+	inactive := false
 	var currentFingerprint fingerprint
 	defer sch.stopApplied(key)
 	for {
@@ -508,6 +640,16 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 						logger.Debug("Skip rule evaluation because it is paused")
 						return nil
 					}
+					if ctx.inactive {
+						// The alerts of the rule are resolved as paused once, when the rule leaves its active time intervals.
+						if !inactive {
+							logger.Debug("Pause rule evaluation outside of its active time intervals")
+							resetState(grafanaCtx, true)
+							inactive = true
+						}
+						return nil
+					}
+					inactive = false
 
 					fpStr := currentFingerprint.String()
 					utcTick := ctx.scheduledAt.UTC().Format(time.RFC3339Nano)
@@ -528,6 +670,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				}
 			}()
 		case <-grafanaCtx.Done():
+			// the state is kept in the database for the replica that evaluates the rule now
+			if errors.Is(grafanaCtx.Err(), errRuleReassigned) {
+				sch.stateManager.ForgetRule(key)
+			}
 			// clean up the state only if the reason for stopping the evaluation loop is that the rule was deleted
 			if errors.Is(grafanaCtx.Err(), errRuleDeleted) {
 				// We do not want a context to be unbounded which could potentially cause a go routine running
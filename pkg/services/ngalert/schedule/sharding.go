@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// shardTokensPerMember is the number of positions every replica takes on the hash ring.
+// More positions spread the rules more evenly across replicas.
+const shardTokensPerMember = 128
+
+// Membership provides the replicas that share the evaluation of alert rules.
+type Membership interface {
+	// Self returns the name of this replica.
+	Self() string
+	// Members returns the names of all live replicas, including this one.
+	Members() []string
+}
+
+type shardToken struct {
+	hash   uint64
+	member string
+}
+
+// ruleSharder partitions alert rules across the members of the cluster using consistent hashing over the rule key,
+// so that only a small share of the rules moves to another replica when a replica joins or leaves the cluster.
+// It is not safe for concurrent use, it is only used by the scheduling loop.
+type ruleSharder struct {
+	membership Membership
+	self       string
+	members    []string
+	ring       []shardToken
+	// generation is incremented every time the members change.
+	generation int
+	// released are the rules that were handed over to other replicas since the members changed last.
+	released map[ngmodels.AlertRuleKey]struct{}
+
+	log     log.Logger
+	metrics *metrics.Scheduler
+}
+
+func newRuleSharder(membership Membership, logger log.Logger, m *metrics.Scheduler) *ruleSharder {
+	return &ruleSharder{
+		membership: membership,
+		log:        logger,
+		metrics:    m,
+	}
+}
+
+// update refreshes the members of the cluster and rebuilds the ring if they changed. It returns true if the ring
+// was rebuilt.
+func (s *ruleSharder) update() bool {
+	self := s.membership.Self()
+	members := append([]string(nil), s.membership.Members()...)
+	sort.Strings(members)
+	if s.generation > 0 && self == s.self && equalStrings(members, s.members) {
+		return false
+	}
+
+	s.self = self
+	s.members = members
+	s.ring = make([]shardToken, 0, len(members)*shardTokensPerMember)
+	for _, member := range members {
+		for i := 0; i < shardTokensPerMember; i++ {
+			s.ring = append(s.ring, shardToken{hash: hashString(member + "-" + strconv.Itoa(i)), member: member})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool {
+		if s.ring[i].hash == s.ring[j].hash {
+			return s.ring[i].member < s.ring[j].member
+		}
+		return s.ring[i].hash < s.ring[j].hash
+	})
+	s.generation++
+	s.released = make(map[ngmodels.AlertRuleKey]struct{})
+
+	s.log.Info("Alert rules are redistributed across replicas", "self", self, "members", members)
+	s.metrics.ShardMembers.Set(float64(len(members)))
+	if s.generation > 1 {
+		s.metrics.ShardRebalances.Inc()
+	}
+	return true
+}
+
+// owns returns true if the rule should be evaluated by this replica. If this replica is not a member of the cluster,
+// for example because it has not joined it yet, it owns all rules. Rules are rather evaluated twice than not at all.
+func (s *ruleSharder) owns(key ngmodels.AlertRuleKey) bool {
+	if s == nil || len(s.ring) == 0 || !s.isMember() {
+		return true
+	}
+	h := hashString(strconv.FormatInt(key.OrgID, 10) + "/" + key.UID)
+	idx := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= h })
+	if idx == len(s.ring) {
+		idx = 0
+	}
+	return s.ring[idx].member == s.self
+}
+
+// dependencyRules returns the rules that depend on other rules and the rules they depend on. These rules are evaluated
+// by every replica because the state manager checks dependencies against the states of its own cache, and an upstream
+// rule evaluated by another replica would never look firing.
+func dependencyRules(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleKey]struct{} {
+	type groupKey struct{ namespaceUID, ruleGroup string }
+	uids := make(map[string]struct{})
+	groups := make(map[groupKey]struct{})
+	result := make(map[ngmodels.AlertRuleKey]struct{})
+	for _, rule := range rules {
+		if len(rule.DependsOn) == 0 {
+			continue
+		}
+		result[rule.GetKey()] = struct{}{}
+		for _, d := range rule.DependsOn {
+			if d.RuleUID != "" {
+				uids[d.RuleUID] = struct{}{}
+				continue
+			}
+			groups[groupKey{namespaceUID: d.NamespaceUID, ruleGroup: d.RuleGroup}] = struct{}{}
+		}
+	}
+	if len(result) == 0 {
+		return result
+	}
+	for _, rule := range rules {
+		_, byUID := uids[rule.UID]
+		_, byGroup := groups[groupKey{namespaceUID: rule.NamespaceUID, ruleGroup: rule.RuleGroup}]
+		if byUID || byGroup {
+			result[rule.GetKey()] = struct{}{}
+		}
+	}
+	return result
+}
+
+// release records that the rule is evaluated by another replica. It returns true if the rule was not released since
+// the members changed last, so that every rule this replica does not own is released exactly once per generation,
+// including rules that this replica did not know about when the members changed.
+func (s *ruleSharder) release(key ngmodels.AlertRuleKey) bool {
+	if _, ok := s.released[key]; ok {
+		return false
+	}
+	s.released[key] = struct{}{}
+	return true
+}
+
+// claim records that the rule is evaluated by this replica, so that it is released again if it stops being so.
+func (s *ruleSharder) claim(key ngmodels.AlertRuleKey) {
+	if s != nil {
+		delete(s.released, key)
+	}
+}
+
+func (s *ruleSharder) isMember() bool {
+	idx := sort.SearchStrings(s.members, s.self)
+	return idx < len(s.members) && s.members[idx] == s.self
+}
+
+// hashString returns the FNV-1a hash of the string. Because the tokens of a member only differ in the last characters,
+// the bits of the hash are mixed so that the tokens spread over the whole ring.
+func hashString(str string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(str))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeMembership struct {
+	self    string
+	members []string
+}
+
+func (f *fakeMembership) Self() string {
+	return f.self
+}
+
+func (f *fakeMembership) Members() []string {
+	return f.members
+}
+
+func newTestSharder(membership Membership) *ruleSharder {
+	return newRuleSharder(membership, log.NewNopLogger(), metrics.NewSchedulerMetrics(prometheus.NewPedanticRegistry()))
+}
+
+func TestRuleSharder(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+	members := []string{"grafana-0", "grafana-1", "grafana-2"}
+
+	ownersOf := func(members []string) map[models.AlertRuleKey]string {
+		owners := make(map[models.AlertRuleKey]string, len(keys))
+		for _, member := range members {
+			sharder := newTestSharder(&fakeMembership{self: member, members: members})
+			require.True(t, sharder.update())
+			for _, key := range keys {
+				if sharder.owns(key) {
+					_, ok := owners[key]
+					require.Falsef(t, ok, "rule %s is owned by more than one member", key)
+					owners[key] = member
+				}
+			}
+		}
+		return owners
+	}
+
+	t.Run("every rule is owned by exactly one member", func(t *testing.T) {
+		owners := ownersOf(members)
+		require.Len(t, owners, len(keys))
+		perMember := map[string]int{}
+		for _, member := range owners {
+			perMember[member]++
+		}
+		for _, member := range members {
+			require.InDeltaf(t, len(keys)/len(members), perMember[member], float64(len(keys))/10, "rules are not evenly distributed: %v", perMember)
+		}
+	})
+
+	t.Run("only the rules of the member that left move", func(t *testing.T) {
+		before := ownersOf(members)
+		after := ownersOf(members[:2])
+		require.Len(t, after, len(keys))
+		for key, owner := range before {
+			if owner != members[2] {
+				require.Equalf(t, owner, after[key], "rule %s moved from %s to %s", key, owner, after[key])
+			}
+		}
+	})
+
+	t.Run("owns all rules if it is not a member", func(t *testing.T) {
+		sharder := newTestSharder(&fakeMembership{self: "grafana-3", members: members})
+		sharder.update()
+		for _, key := range keys {
+			require.True(t, sharder.owns(key))
+		}
+	})
+
+	t.Run("owns all rules if there are no members", func(t *testing.T) {
+		sharder := newTestSharder(&fakeMembership{})
+		sharder.update()
+		for _, key := range keys {
+			require.True(t, sharder.owns(key))
+		}
+	})
+
+	t.Run("rebuilds the ring only if members change", func(t *testing.T) {
+		membership := &fakeMembership{self: members[0], members: members}
+		sharder := newTestSharder(membership)
+		require.True(t, sharder.update())
+		require.False(t, sharder.update())
+		membership.members = []string{members[2], members[1], members[0]}
+		require.False(t, sharder.update())
+		membership.members = members[:2]
+		require.True(t, sharder.update())
+		require.Equal(t, 2, sharder.generation)
+	})
+}
+
+func TestDependencyRules(t *testing.T) {
+	newRule := func(uid, namespaceUID, group string, dependencies ...models.RuleDependency) *models.AlertRule {
+		return &models.AlertRule{OrgID: 1, UID: uid, NamespaceUID: namespaceUID, RuleGroup: group, DependsOn: dependencies}
+	}
+	upstream := newRule("upstream", "ns", "upstream-group")
+	groupMember := newRule("group-member", "ns", "group")
+	otherNamespace := newRule("other-namespace", "other-ns", "group")
+	independent := newRule("independent", "ns", "independent-group")
+	byUID := newRule("by-uid", "ns", "dependent-group", models.RuleDependency{RuleUID: upstream.UID})
+	byGroup := newRule("by-group", "ns", "dependent-group", models.RuleDependency{NamespaceUID: "ns", RuleGroup: "group"})
+
+	t.Run("returns dependent rules and the rules they depend on", func(t *testing.T) {
+		result := dependencyRules([]*models.AlertRule{upstream, groupMember, otherNamespace, independent, byUID, byGroup})
+		require.Equal(t, map[models.AlertRuleKey]struct{}{
+			upstream.GetKey():    {},
+			groupMember.GetKey(): {},
+			byUID.GetKey():       {},
+			byGroup.GetKey():     {},
+		}, result)
+	})
+
+	t.Run("returns no rules without dependencies", func(t *testing.T) {
+		require.Empty(t, dependencyRules([]*models.AlertRule{upstream, groupMember, independent}))
+	})
+}
+
+func TestProcessTicks_Sharding(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	// the test checks which rules are scheduled, the results of the evaluations do not matter
+	evaluator := &eval_mocks.ConditionEvaluatorMock{}
+	evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{}, nil).Maybe()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, eval_mocks.NewEvaluatorFactory(evaluator))
+	membership := &fakeMembership{self: "grafana-0", members: []string{"grafana-0", "grafana-1"}}
+	sch.sharder = newTestSharder(membership)
+
+	rules := models.GenerateAlertRules(50, models.AlertRuleGen(models.WithInterval(time.Second)))
+	ruleStore.PutRule(context.Background(), rules...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	tick := time.Time{}
+
+	scheduledKeys := func(items []readyToRunItem) map[models.AlertRuleKey]struct{} {
+		keys := make(map[models.AlertRuleKey]struct{}, len(items))
+		for _, item := range items {
+			keys[item.rule.GetKey()] = struct{}{}
+		}
+		return keys
+	}
+
+	tick = tick.Add(time.Second)
+	scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped)
+	require.NotEmpty(t, scheduled)
+	require.Less(t, len(scheduled), len(rules))
+	owned := scheduledKeys(scheduled)
+	for _, rule := range rules {
+		_, ok := owned[rule.GetKey()]
+		require.Equal(t, sch.sharder.owns(rule.GetKey()), ok)
+		require.Equal(t, ok, sch.registry.exists(rule.GetKey()))
+	}
+
+	t.Run("takes over all rules when the other member leaves", func(t *testing.T) {
+		membership.members = []string{"grafana-0"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped)
+		require.Len(t, scheduled, len(rules))
+	})
+
+	t.Run("releases rules when the other member joins", func(t *testing.T) {
+		infos := make(map[models.AlertRuleKey]*alertRuleInfo, len(rules))
+		for _, rule := range rules {
+			info, _ := sch.registry.getOrCreateInfo(ctx, rule.GetKey())
+			infos[rule.GetKey()] = info
+		}
+
+		membership.members = []string{"grafana-0", "grafana-1"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped, "rules evaluated by another replica must not be deleted")
+		require.Equal(t, owned, scheduledKeys(scheduled))
+		for key, info := range infos {
+			if _, ok := owned[key]; ok {
+				require.NoError(t, info.ctx.Err())
+				continue
+			}
+			require.ErrorIs(t, info.ctx.Err(), errRuleReassigned)
+			require.False(t, sch.registry.exists(key))
+		}
+	})
+
+	var notOwned, upstream *models.AlertRule
+	for _, rule := range rules {
+		if _, ok := owned[rule.GetKey()]; ok {
+			upstream = rule
+		} else {
+			notOwned = rule
+		}
+	}
+
+	t.Run("evaluates rules with dependencies and releases them once they have none", func(t *testing.T) {
+		dependent := models.CopyRule(notOwned)
+		dependent.Version++
+		dependent.DependsOn = []models.RuleDependency{{RuleUID: upstream.UID}}
+		ruleStore.PutRule(ctx, dependent)
+
+		tick = tick.Add(time.Second)
+		scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Contains(t, scheduledKeys(scheduled), dependent.GetKey())
+		info, _ := sch.registry.getOrCreateInfo(ctx, dependent.GetKey())
+
+		independent := models.CopyRule(dependent)
+		independent.Version++
+		independent.DependsOn = nil
+		ruleStore.PutRule(ctx, independent)
+
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped)
+		require.NotContains(t, scheduledKeys(scheduled), independent.GetKey())
+		require.ErrorIs(t, info.ctx.Err(), errRuleReassigned)
+		require.False(t, sch.registry.exists(independent.GetKey()))
+	})
+
+	t.Run("releases rules once per generation", func(t *testing.T) {
+		require.False(t, sch.sharder.release(notOwned.GetKey()))
+
+		membership.members = []string{"grafana-0", "grafana-1", "grafana-2"}
+		require.True(t, sch.sharder.update())
+		require.True(t, sch.sharder.release(notOwned.GetKey()))
+		require.False(t, sch.sharder.release(notOwned.GetKey()))
+	})
+}
@@ -21,6 +21,10 @@ import (
 
 type ruleStates struct {
 	states map[string]*State
+	// namespaceUID and ruleGroup are the rule group of the rule the states belong to.
+	// They are used to find the states of rules that other rules depend on.
+	namespaceUID string
+	ruleGroup    string
 }
 
 type cache struct {
@@ -92,6 +96,9 @@ func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngMo
 		states = &ruleStates{states: make(map[string]*State)}
 		c.states[stateCandidate.OrgID][stateCandidate.AlertRuleUID] = states
 	}
+	// the rule can be moved to another group at any time
+	states.namespaceUID = alertRule.NamespaceUID
+	states.ruleGroup = alertRule.RuleGroup
 	return states.getOrAdd(stateCandidate)
 }
 
@@ -244,6 +251,15 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+func (c *cache) setRuleStates(orgID int64, ruleUID string, states *ruleStates) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]*ruleStates)
+	}
+	c.states[orgID][ruleUID] = states
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -306,6 +322,30 @@ func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string, skipNormal
 	return result
 }
 
+// isFiring returns true if any rule that matches the dependency has an Alerting state.
+func (c *cache) isFiring(orgID int64, dependency ngModels.RuleDependency) bool {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	hasAlerting := func(rs *ruleStates) bool {
+		for _, state := range rs.states {
+			if state.State == eval.Alerting {
+				return true
+			}
+		}
+		return false
+	}
+	if dependency.RuleUID != "" {
+		rs, ok := c.states[orgID][dependency.RuleUID]
+		return ok && hasAlerting(rs)
+	}
+	for _, rs := range c.states[orgID] {
+		if rs.namespaceUID == dependency.NamespaceUID && rs.ruleGroup == dependency.RuleGroup && hasAlerting(rs) {
+			return true
+		}
+	}
+	return false
+}
+
 // removeByRuleUID deletes all entries in the state cache that match the given UID. Returns removed states
 func (c *cache) removeByRuleUID(orgID int64, uid string) []*State {
 	c.mtxStates.Lock()
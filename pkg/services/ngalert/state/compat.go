@@ -160,6 +160,10 @@ func FromAlertsStateToStoppedAlert(firingStates []StateTransition, appURL *url.U
 		if transition.PreviousState == eval.Normal || transition.PreviousState == eval.Pending {
 			continue
 		}
+		// suppressed alerts were not sent, so there is nothing to stop
+		if transition.PreviousStateReason == ngModels.StateReasonSuppressed {
+			continue
+		}
 		postableAlert := StateToPostableAlert(transition.State, appURL)
 		postableAlert.EndsAt = strfmt.DateTime(ts)
 		alerts.PostableAlerts = append(alerts.PostableAlerts, *postableAlert)
@@ -0,0 +1,355 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/weaveworks/common/instrument"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+const (
+	defaultSQLQueryLimit = 1000
+	// sqlLabelValueMaxLength is the length of the label column of the alert_state_history_label table. Longer label
+	// values are truncated in that table, the entries are filtered by the complete labels after they are read.
+	sqlLabelValueMaxLength = 190
+)
+
+// sqlHistoryEntry is a state transition stored in the alert_state_history table.
+type sqlHistoryEntry struct {
+	ID                int64  `xorm:"pk autoincr 'id'"`
+	OrgID             int64  `xorm:"org_id"`
+	RuleUID           string `xorm:"rule_uid"`
+	RuleGroup         string `xorm:"rule_group"`
+	NamespaceUID      string `xorm:"namespace_uid"`
+	DashboardUID      string `xorm:"dashboard_uid"`
+	PanelID           int64  `xorm:"panel_id"`
+	Condition         string `xorm:"rule_condition"`
+	Labels            string `xorm:"labels"`
+	LabelsFingerprint string `xorm:"labels_fingerprint"`
+	PreviousState     string `xorm:"previous_state"`
+	CurrentState      string `xorm:"current_state"`
+	Reason            string `xorm:"reason"`
+	Values            string `xorm:"state_values"`
+	Error             string `xorm:"error"`
+	// EvalTime is the time of the evaluation that caused the transition in Unix milliseconds.
+	EvalTime int64 `xorm:"eval_time"`
+}
+
+func (e sqlHistoryEntry) TableName() string {
+	return "alert_state_history"
+}
+
+// sqlHistoryLabel is a label of a state transition stored in the alert_state_history_label table.
+type sqlHistoryLabel struct {
+	HistoryID int64  `xorm:"history_id"`
+	Name      string `xorm:"name"`
+	Value     string `xorm:"value"`
+	EvalTime  int64  `xorm:"eval_time"`
+}
+
+func (l sqlHistoryLabel) TableName() string {
+	return "alert_state_history_label"
+}
+
+// SQLBackend is a state.Historian that records state history in a dedicated table of Grafana's database.
+type SQLBackend struct {
+	db      db.DB
+	clock   clock.Clock
+	metrics *metrics.Historian
+	log     log.Logger
+}
+
+func NewSQLBackend(store db.DB, metrics *metrics.Historian) *SQLBackend {
+	return &SQLBackend{
+		db:      store,
+		clock:   clock.New(),
+		metrics: metrics,
+		log:     log.New("ngalert.state.historian", "backend", "sql"),
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	entries, labels := statesToSQLEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		if err := h.save(ctx, entries, labels); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch")
+	}(writeCtx)
+	return errCh
+}
+
+func (h *SQLBackend) save(ctx context.Context, entries []sqlHistoryEntry, labels [][]sqlHistoryLabel) error {
+	return instrument.CollectedRequest(ctx, "sql.write", h.metrics.WriteDuration, instrument.ErrorCode, func(ctx context.Context) error {
+		return h.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			for i := range entries {
+				if _, err := sess.Insert(&entries[i]); err != nil {
+					return fmt.Errorf("failed to insert state history entry: %w", err)
+				}
+				if len(labels[i]) == 0 {
+					continue
+				}
+				for j := range labels[i] {
+					labels[i][j].HistoryID = entries[i].ID
+				}
+				if _, err := sess.Insert(&labels[i]); err != nil {
+					return fmt.Errorf("failed to insert labels of state history entry: %w", err)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+// The dataframe has the same format as the one of the Loki backend.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSQLQueryLimit
+	}
+
+	var entries []sqlHistoryEntry
+	err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(sqlHistoryEntry{}.TableName()).
+			Where("org_id = ?", query.OrgID).
+			And("eval_time >= ?", query.From.UnixMilli()).
+			And("eval_time <= ?", query.To.UnixMilli())
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			q = q.And("panel_id = ?", query.PanelID)
+		}
+
+		// Ensure that all queries we build are deterministic.
+		labelKeys := make([]string, 0, len(query.Labels))
+		for k := range query.Labels {
+			labelKeys = append(labelKeys, k)
+		}
+		sort.Strings(labelKeys)
+		for _, k := range labelKeys {
+			q = q.And("id IN (SELECT history_id FROM alert_state_history_label WHERE name = ? AND value = ?)", k, truncateLabelValue(query.Labels[k]))
+		}
+
+		return q.Desc("eval_time", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+
+	return sqlEntriesToFrame(entries, query.Labels)
+}
+
+// DeleteExpiredSQLStateHistory deletes the state history entries of the SQL backend that were recorded before the given time.
+func DeleteExpiredSQLStateHistory(ctx context.Context, store db.DB, before time.Time) (int64, error) {
+	var affected int64
+	err := store.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_state_history_label WHERE eval_time < ?", before.UnixMilli()); err != nil {
+			return err
+		}
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE eval_time < ?", before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+func statesToSQLEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) ([]sqlHistoryEntry, [][]sqlHistoryLabel) {
+	entries := make([]sqlHistoryEntry, 0, len(states))
+	labels := make([][]sqlHistoryLabel, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		sanitizedLabels := removePrivateLabels(state.Labels)
+		lblsJSON, err := json.Marshal(sanitizedLabels)
+		if err != nil {
+			logger.Error("Failed to serialize labels of state, skipping", "error", err)
+			continue
+		}
+		// states without values are stored with empty values
+		var valuesJSON []byte
+		if values := valuesAsDataBlob(state.State); values != nil {
+			valuesJSON, err = values.MarshalJSON()
+			if err != nil {
+				logger.Error("Failed to serialize values of state, skipping", "error", err)
+				continue
+			}
+		}
+
+		evalTime := state.State.LastEvaluationTime.UnixMilli()
+		entry := sqlHistoryEntry{
+			OrgID:             rule.OrgID,
+			RuleUID:           rule.UID,
+			RuleGroup:         rule.Group,
+			NamespaceUID:      rule.NamespaceUID,
+			DashboardUID:      rule.DashboardUID,
+			PanelID:           rule.PanelID,
+			Condition:         rule.Condition,
+			Labels:            string(lblsJSON),
+			LabelsFingerprint: labelFingerprint(sanitizedLabels),
+			PreviousState:     state.PreviousFormatted(),
+			CurrentState:      state.Formatted(),
+			Reason:            state.StateReason,
+			Values:            string(valuesJSON),
+			EvalTime:          evalTime,
+		}
+		if state.State.State == eval.Error && state.Error != nil {
+			entry.Error = state.Error.Error()
+		}
+
+		entryLabels := make([]sqlHistoryLabel, 0, len(sanitizedLabels))
+		for k, v := range sanitizedLabels {
+			entryLabels = append(entryLabels, sqlHistoryLabel{Name: k, Value: truncateLabelValue(v), EvalTime: evalTime})
+		}
+
+		entries = append(entries, entry)
+		labels = append(labels, entryLabels)
+	}
+	return entries, labels
+}
+
+// sqlEntriesToFrame converts the entries, which are sorted by time in descending order, into a single linear history
+// that is sorted by time in ascending order. Entries that do not match all the given labels are dropped.
+func sqlEntriesToFrame(entries []sqlHistoryEntry, matchLabels map[string]string) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+
+		var instanceLabels map[string]string
+		if err := json.Unmarshal([]byte(e.Labels), &instanceLabels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels of state history entry %d: %w", e.ID, err)
+		}
+		if !matchesLabels(instanceLabels, matchLabels) {
+			continue
+		}
+
+		values := simplejson.New()
+		if e.Values != "" {
+			var err error
+			if values, err = simplejson.NewJson([]byte(e.Values)); err != nil {
+				return nil, fmt.Errorf("failed to parse values of state history entry %d: %w", e.ID, err)
+			}
+		}
+
+		line, err := json.Marshal(lokiEntry{
+			SchemaVersion:  1,
+			Previous:       e.PreviousState,
+			Current:        e.CurrentState,
+			Error:          e.Error,
+			Values:         values,
+			Condition:      e.Condition,
+			DashboardUID:   e.DashboardUID,
+			PanelID:        e.PanelID,
+			Fingerprint:    e.LabelsFingerprint,
+			RuleUID:        e.RuleUID,
+			InstanceLabels: instanceLabels,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state history entry %d: %w", e.ID, err)
+		}
+		// The labels of the history, equivalent to the labels of the streams of the Loki backend.
+		streamLabels, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.NamespaceUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize labels of state history entry %d: %w", e.ID, err)
+		}
+
+		times = append(times, time.UnixMilli(e.EvalTime))
+		lines = append(lines, line)
+		labels = append(labels, streamLabels)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+func matchesLabels(labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateLabelValue truncates the value to the length of the value column of the alert_state_history_label table.
+func truncateLabelValue(v string) string {
+	if utf8.RuneCountInString(v) <= sqlLabelValueMaxLength {
+		return v
+	}
+	return string([]rune(v)[:sqlLabelValueMaxLength])
+}
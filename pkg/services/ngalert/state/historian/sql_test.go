@@ -0,0 +1,142 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestStatesToSQLEntries(t *testing.T) {
+	t.Run("skips non-transitory states", func(t *testing.T) {
+		entries, labels := statesToSQLEntries(createTestRule(), singleFromNormal(&state.State{State: eval.Normal}), log.NewNopLogger())
+
+		require.Empty(t, entries)
+		require.Empty(t, labels)
+	})
+
+	t.Run("maps transitions and their labels", func(t *testing.T) {
+		evalTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		states := singleFromNormal(&state.State{
+			State:              eval.Error,
+			Error:              errors.New("oh no"),
+			LastEvaluationTime: evalTime,
+			Labels:             data.Labels{"a": "b", "__private__": "c", "long": strings.Repeat("x", 200)},
+		})
+
+		entries, labels := statesToSQLEntries(createTestRule(), states, log.NewNopLogger())
+
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		require.Equal(t, "rule-uid", entry.RuleUID)
+		require.Equal(t, "Normal", entry.PreviousState)
+		require.Equal(t, "Error", entry.CurrentState)
+		require.Equal(t, "oh no", entry.Error)
+		require.Equal(t, evalTime.UnixMilli(), entry.EvalTime)
+		require.NotContains(t, entry.Labels, "__private__")
+		require.NotEmpty(t, entry.LabelsFingerprint)
+
+		require.Len(t, labels[0], 2)
+		for _, l := range labels[0] {
+			require.LessOrEqual(t, len(l.Value), sqlLabelValueMaxLength)
+			require.Equal(t, evalTime.UnixMilli(), l.EvalTime)
+		}
+	})
+
+	t.Run("maps states without values", func(t *testing.T) {
+		entries, _ := statesToSQLEntries(createTestRule(), singleFromNormal(&state.State{State: eval.Alerting}), log.NewNopLogger())
+
+		require.Len(t, entries, 1)
+		require.Empty(t, entries[0].Values)
+	})
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	backend := NewSQLBackend(sqlStore, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+	rule := createTestRule()
+
+	now := time.Now().Truncate(time.Millisecond)
+	record := func(evalTime time.Time, lbls data.Labels, st eval.State) {
+		t.Helper()
+		states := singleFromNormal(&state.State{State: st, LastEvaluationTime: evalTime, Labels: lbls})
+		require.NoError(t, <-backend.Record(context.Background(), rule, states))
+	}
+	record(now.Add(-3*time.Hour), data.Labels{"team": "a"}, eval.Alerting)
+	record(now.Add(-2*time.Hour), data.Labels{"team": "b"}, eval.Alerting)
+	record(now.Add(-1*time.Hour), data.Labels{"team": "a", "env": "prod"}, eval.Pending)
+
+	query := func(q models.HistoryQuery) []lokiEntry {
+		t.Helper()
+		q.OrgID = rule.OrgID
+		frame, err := backend.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+
+		entries := make([]lokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry lokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("returns entries in ascending order", func(t *testing.T) {
+		res := query(models.HistoryQuery{RuleUID: rule.UID})
+
+		require.Len(t, res, 3)
+		require.Equal(t, "b", res[1].InstanceLabels["team"])
+		require.Equal(t, "Pending", res[2].Current)
+	})
+
+	t.Run("filters by labels", func(t *testing.T) {
+		res := query(models.HistoryQuery{Labels: map[string]string{"team": "a"}})
+		require.Len(t, res, 2)
+
+		res = query(models.HistoryQuery{Labels: map[string]string{"team": "a", "env": "prod"}})
+		require.Len(t, res, 1)
+	})
+
+	t.Run("filters by time range", func(t *testing.T) {
+		res := query(models.HistoryQuery{From: now.Add(-150 * time.Minute), To: now})
+		require.Len(t, res, 2)
+	})
+
+	t.Run("returns the latest entries if limited", func(t *testing.T) {
+		res := query(models.HistoryQuery{Limit: 1})
+		require.Len(t, res, 1)
+		require.Equal(t, "Pending", res[0].Current)
+	})
+
+	t.Run("filters by other organizations", func(t *testing.T) {
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID + 1})
+		require.NoError(t, err)
+		require.Zero(t, frame.Rows())
+	})
+
+	t.Run("deletes expired entries", func(t *testing.T) {
+		deleted, err := DeleteExpiredSQLStateHistory(context.Background(), sqlStore, now.Add(-90*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		res := query(models.HistoryQuery{})
+		require.Len(t, res, 1)
+	})
+}
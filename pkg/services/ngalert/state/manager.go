@@ -149,28 +149,16 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 
 			rulesStates, ok := orgStates[entry.RuleUID]
 			if !ok {
-				rulesStates = &ruleStates{states: make(map[string]*State)}
+				rulesStates = &ruleStates{
+					states:       make(map[string]*State),
+					namespaceUID: ruleForEntry.NamespaceUID,
+					ruleGroup:    ruleForEntry.RuleGroup,
+				}
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
+			state := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[state.CacheID] = state
 			statesCount++
 		}
 	}
@@ -178,6 +166,69 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// WarmRule replaces the states of the rule in the cache with the states persisted in the instance store.
+// It is used when the evaluation of the rule is handed over from another replica, so that the alert instances
+// continue from the state they had when the other replica evaluated the rule last.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.FromContext(ctx)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+	rulesStates := &ruleStates{
+		states:       make(map[string]*State, len(alertInstances)),
+		namespaceUID: rule.NamespaceUID,
+		ruleGroup:    rule.RuleGroup,
+	}
+	for _, entry := range alertInstances {
+		state := st.stateFromInstance(entry, rule)
+		rulesStates.states[state.CacheID] = state
+	}
+	st.cache.setRuleStates(rule.OrgID, rule.UID, rulesStates)
+	logger.Debug("State of the rule has been loaded", "states", len(rulesStates.states))
+}
+
+// ForgetRule removes the states of the rule from the cache. Unlike DeleteStateByRuleUID, the states are kept
+// in the instance store and no notifications are sent. It is used when the evaluation of the rule is handed over
+// to another replica.
+func (st *Manager) ForgetRule(key ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(key.OrgID, key.UID)
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	lbs := map[string]string(entry.Labels)
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("Error getting cacheId for entry", "error", err)
+	}
+	state := &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          rule.Annotations,
+	}
+	// The time the state started to keep firing is not persisted. The last evaluation is the closest
+	// approximation we have, and it can only make the state fire for longer, not resolve early.
+	if state.State == eval.Alerting && state.StateReason == ngModels.StateReasonKeepFiring {
+		state.KeepFiringSince = entry.LastEvalTime
+	}
+	return state
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -264,6 +315,7 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	logger := st.log.FromContext(tracingCtx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
 	states := st.setNextStateForRule(tracingCtx, alertRule, results, extraLabels, logger)
+	st.applyDependencies(alertRule, states, logger)
 	span.AddEvent("results processed", trace.WithAttributes(
 		attribute.Int64("state_transitions", int64(len(states))),
 	))
@@ -287,6 +339,28 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	return allChanges
 }
 
+// PreviewEvalResults returns the state transitions that ProcessEvalResults would apply for the evaluation results.
+// The states of the manager are not changed, nothing is saved or recorded in the state history and no images are taken.
+func (st *Manager) PreviewEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels) []StateTransition {
+	preview := NewManager(ManagerCfg{
+		ExternalURL:                    st.externalURL,
+		Images:                         &NoopImageService{},
+		Clock:                          st.clock,
+		DoNotSaveNormalState:           st.doNotSaveNormalState,
+		MaxStateSaveConcurrency:        1,
+		ApplyNoDataAndErrorToAllStates: st.applyNoDataAndErrorToAllStates,
+		Tracer:                         st.tracer,
+		Log:                            st.log,
+	})
+	preview.ResendDelay = st.ResendDelay
+	// States of other rules are copied as well because they are needed to evaluate the dependencies of the rule.
+	for _, s := range st.cache.getAll(alertRule.OrgID, false) {
+		cp := *s
+		preview.cache.set(&cp)
+	}
+	return preview.ProcessEvalResults(ctx, evaluatedAt, alertRule, results, extraLabels)
+}
+
 func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
 	if st.applyNoDataAndErrorToAllStates && results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK) { // If it is no data, check the mapping and switch all results to the new state
 		// TODO aggregate UID of datasources that returned NoData into one and provide as auxiliary info, probably annotation
@@ -387,6 +461,11 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = result.State.String()
 	}
 
+	// The state keeps firing only because of keep_firing_for
+	if currentState.State == eval.Alerting && !currentState.KeepFiringSince.IsZero() {
+		currentState.StateReason = ngModels.StateReasonKeepFiring
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
@@ -418,6 +497,34 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	return nextState
 }
 
+// applyDependencies marks the Alerting states of the rule as suppressed while any of the rules it depends on is firing.
+// Suppressed states are not sent to the Alertmanager. Only the states in the cache of this manager are checked, which is why
+// the scheduler evaluates rules with dependencies on every replica when evaluation is sharded.
+func (st *Manager) applyDependencies(alertRule *ngModels.AlertRule, transitions []StateTransition, logger log.Logger) {
+	if len(alertRule.DependsOn) == 0 {
+		return
+	}
+	var firing *ngModels.RuleDependency
+	for i := range alertRule.DependsOn {
+		if st.cache.isFiring(alertRule.OrgID, alertRule.DependsOn[i]) {
+			firing = &alertRule.DependsOn[i]
+			break
+		}
+	}
+	if firing != nil {
+		logger.Debug("Suppressing alerts because a rule this rule depends on is firing", "dependency", firing.String())
+	}
+	for _, t := range transitions {
+		if firing != nil && t.State.State == eval.Alerting {
+			t.State.StateReason = ngModels.StateReasonSuppressed
+		}
+		// The alert was not sent while it was suppressed, so there is nothing to resolve.
+		if t.State.Resolved && t.PreviousStateReason == ngModels.StateReasonSuppressed {
+			t.State.Resolved = false
+		}
+	}
+}
+
 func (st *Manager) GetAll(orgID int64) []*State {
 	allStates := st.cache.getAll(orgID, st.doNotSaveNormalState)
 	return allStates
@@ -859,6 +859,95 @@ func TestProcessEvalResults_StateTransitions(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:      "t1[{}:alerting] t2[{}:normal] t3[{}:normal] and 'keep_firing_for'>0 at t2,t3",
+			alertRule: baseRuleWith(ngmodels.WithKeepFiringFor(evaluationInterval)),
+			results: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal)),
+				},
+			},
+			expectedTransitions: map[time.Time][]StateTransition{
+				t2: {
+					{
+						PreviousState: eval.Alerting,
+						State: &State{
+							Labels:      labels["system + rule"],
+							State:       eval.Alerting,
+							StateReason: ngmodels.StateReasonKeepFiring,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+							},
+							StartsAt:           t1,
+							EndsAt:             t2.Add(ResendDelay * 4),
+							LastEvaluationTime: t2,
+							KeepFiringSince:    t2,
+						},
+					},
+				},
+				t3: {
+					{
+						PreviousState:       eval.Alerting,
+						PreviousStateReason: ngmodels.StateReasonKeepFiring,
+						State: &State{
+							Labels: labels["system + rule"],
+							State:  eval.Normal,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+								newEvaluation(t3, eval.Normal),
+							},
+							StartsAt:           t3,
+							EndsAt:             t3,
+							LastEvaluationTime: t3,
+							Resolved:           true,
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:      "t1[{}:alerting] t2[{}:normal] t3[{}:alerting] and 'keep_firing_for'>0 at t3",
+			alertRule: baseRuleWith(ngmodels.WithKeepFiringFor(2 * evaluationInterval)),
+			results: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Alerting)),
+				},
+			},
+			expectedTransitions: map[time.Time][]StateTransition{
+				t3: {
+					{
+						PreviousState:       eval.Alerting,
+						PreviousStateReason: ngmodels.StateReasonKeepFiring,
+						State: &State{
+							Labels: labels["system + rule"],
+							State:  eval.Alerting,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+								newEvaluation(t3, eval.Alerting),
+							},
+							StartsAt:           t1,
+							EndsAt:             t3.Add(ResendDelay * 4),
+							LastEvaluationTime: t3,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3325,6 +3414,101 @@ func TestProcessEvalResults_StateTransitions(t *testing.T) {
 	})
 }
 
+func TestProcessEvalResults_Dependencies(t *testing.T) {
+	evaluationInterval := 10 * time.Second
+	t1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(evaluationInterval)
+	t3 := t2.Add(evaluationInterval)
+
+	newRule := func(uid, group string, dependencies ...ngmodels.RuleDependency) *ngmodels.AlertRule {
+		return &ngmodels.AlertRule{
+			OrgID:           1,
+			UID:             uid,
+			Title:           uid,
+			NamespaceUID:    "test_namespace_uid",
+			RuleGroup:       group,
+			IntervalSeconds: int64(evaluationInterval.Seconds()),
+			NoDataState:     ngmodels.NoData,
+			ExecErrState:    ngmodels.ErrorErrState,
+			DependsOn:       dependencies,
+		}
+	}
+	results := func(ts time.Time, state eval.State) eval.Results {
+		return eval.Results{{State: state, EvaluatedAt: ts}}
+	}
+	newManager := func() *Manager {
+		return NewManager(ManagerCfg{
+			Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			Tracer:                  tracing.InitializeTracerForTest(),
+			Log:                     log.New("ngalert.state.manager"),
+			InstanceStore:           &FakeInstanceStore{},
+			Images:                  &NotAvailableImageService{},
+			Clock:                   clock.NewMock(),
+			Historian:               &FakeHistorian{},
+			MaxStateSaveConcurrency: 1,
+		})
+	}
+
+	upstream := newRule("upstream", "upstream-group")
+	testCases := []struct {
+		desc       string
+		dependency ngmodels.RuleDependency
+	}{
+		{desc: "rule dependency", dependency: ngmodels.RuleDependency{RuleUID: upstream.UID}},
+		{desc: "rule group dependency", dependency: ngmodels.RuleDependency{NamespaceUID: upstream.NamespaceUID, RuleGroup: upstream.RuleGroup}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Run("suppresses alerts while the upstream rule is firing", func(t *testing.T) {
+				st := newManager()
+				dependent := newRule("dependent", "dependent-group", tc.dependency)
+
+				st.ProcessEvalResults(context.Background(), t1, upstream, results(t1, eval.Alerting), nil)
+				transitions := st.ProcessEvalResults(context.Background(), t1, dependent, results(t1, eval.Alerting), nil)
+				require.Len(t, transitions, 1)
+				assert.Equal(t, eval.Alerting, transitions[0].State.State)
+				assert.Equal(t, ngmodels.StateReasonSuppressed, transitions[0].State.StateReason)
+				assert.False(t, transitions[0].State.NeedsSending(ResendDelay))
+
+				st.ProcessEvalResults(context.Background(), t2, upstream, results(t2, eval.Normal), nil)
+				transitions = st.ProcessEvalResults(context.Background(), t2, dependent, results(t2, eval.Alerting), nil)
+				require.Len(t, transitions, 1)
+				assert.Equal(t, eval.Alerting, transitions[0].State.State)
+				assert.Empty(t, transitions[0].State.StateReason)
+				assert.Equal(t, ngmodels.StateReasonSuppressed, transitions[0].PreviousStateReason)
+				assert.True(t, transitions[0].State.NeedsSending(ResendDelay))
+			})
+
+			t.Run("does not resolve alerts that were suppressed", func(t *testing.T) {
+				st := newManager()
+				dependent := newRule("dependent", "dependent-group", tc.dependency)
+
+				st.ProcessEvalResults(context.Background(), t1, upstream, results(t1, eval.Alerting), nil)
+				st.ProcessEvalResults(context.Background(), t1, dependent, results(t1, eval.Alerting), nil)
+				transitions := st.ProcessEvalResults(context.Background(), t2, dependent, results(t2, eval.Normal), nil)
+				require.Len(t, transitions, 1)
+				assert.Equal(t, eval.Normal, transitions[0].State.State)
+				assert.False(t, transitions[0].State.Resolved)
+				assert.False(t, transitions[0].State.NeedsSending(ResendDelay))
+			})
+
+			t.Run("does not suppress alerts when the upstream rule is not firing", func(t *testing.T) {
+				st := newManager()
+				dependent := newRule("dependent", "dependent-group", tc.dependency)
+
+				st.ProcessEvalResults(context.Background(), t1, upstream, results(t1, eval.Normal), nil)
+				transitions := st.ProcessEvalResults(context.Background(), t1, dependent, results(t1, eval.Alerting), nil)
+				require.Len(t, transitions, 1)
+				assert.Empty(t, transitions[0].State.StateReason)
+
+				transitions = st.ProcessEvalResults(context.Background(), t3, dependent, results(t3, eval.Normal), nil)
+				require.Len(t, transitions, 1)
+				assert.True(t, transitions[0].State.Resolved)
+			})
+		})
+	}
+}
+
 func setCacheID(s *State) *State {
 	if s.CacheID != "" {
 		return s
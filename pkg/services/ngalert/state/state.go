@@ -61,6 +61,10 @@ type State struct {
 	// conditions.
 	Values map[string]float64
 
+	// KeepFiringSince is set to the time of the first evaluation where the condition of an alerting
+	// state stopped being met while the rule has a keep_firing_for duration. It is zero otherwise.
+	KeepFiringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -92,6 +96,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetPending the state to Pending. It changes both the start and end time.
@@ -101,6 +106,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNoData sets the state to NoData. It changes both the start and end time.
@@ -110,6 +116,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -119,6 +126,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -128,6 +136,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // Resolve sets the State to Normal. It updates the StateReason, the end time, and sets Resolved to true.
@@ -136,6 +145,7 @@ func (a *State) Resolve(reason string, endsAt time.Time) {
 	a.StateReason = reason
 	a.Resolved = true
 	a.EndsAt = endsAt
+	a.KeepFiringSince = time.Time{}
 }
 
 // Maintain updates the end time using the most recent evaluation.
@@ -187,9 +197,21 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
+	} else if state.State == eval.Alerting && keepFiring(state, rule, result.EvaluatedAt) {
+		prevEndsAt := state.EndsAt
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Keeping state because the keep firing for duration has not been observed",
+			"state",
+			state.State,
+			"keep_firing_since",
+			state.KeepFiringSince,
+			"previous_ends_at",
+			prevEndsAt,
+			"next_ends_at",
+			state.EndsAt)
 	} else {
 		nextEndsAt := result.EvaluatedAt
 		logger.Debug("Changing state",
@@ -206,11 +228,25 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 	}
 }
 
+// keepFiring returns true if the alerting state should keep firing even though its condition is no longer met
+// because the rule has a keep_firing_for duration that has not been observed yet.
+func keepFiring(state *State, rule *models.AlertRule, evaluatedAt time.Time) bool {
+	if rule.KeepFiringFor <= 0 {
+		return false
+	}
+	if state.KeepFiringSince.IsZero() {
+		state.KeepFiringSince = evaluatedAt
+	}
+	return evaluatedAt.Sub(state.KeepFiringSince) < rule.KeepFiringFor
+}
+
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	switch state.State {
 	case eval.Alerting:
 		prevEndsAt := state.EndsAt
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		// The condition is met again, so the state no longer fires only because of keep_firing_for.
+		state.KeepFiringSince = time.Time{}
 		logger.Debug("Keeping state",
 			"state",
 			state.State,
@@ -364,6 +400,10 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logg
 }
 
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
+	if a.StateReason == models.StateReasonSuppressed {
+		// We do not send notifications while a rule the alert rule depends on is firing
+		return false
+	}
 	switch a.State {
 	case eval.Pending:
 		// We do not send notifications for pending states
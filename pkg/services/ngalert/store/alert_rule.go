@@ -140,22 +140,27 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 			}
 			newRules = append(newRules, r)
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleUID:          r.UID,
-				RuleOrgID:        r.OrgID,
-				RuleNamespaceUID: r.NamespaceUID,
-				RuleGroup:        r.RuleGroup,
-				ParentVersion:    0,
-				Version:          r.Version,
-				Created:          r.Updated,
-				Condition:        r.Condition,
-				Title:            r.Title,
-				Data:             r.Data,
-				IntervalSeconds:  r.IntervalSeconds,
-				NoDataState:      r.NoDataState,
-				ExecErrState:     r.ExecErrState,
-				For:              r.For,
-				Annotations:      r.Annotations,
-				Labels:           r.Labels,
+				RuleUID:                  r.UID,
+				RuleOrgID:                r.OrgID,
+				RuleNamespaceUID:         r.NamespaceUID,
+				RuleGroup:                r.RuleGroup,
+				ParentVersion:            0,
+				Version:                  r.Version,
+				Created:                  r.Updated,
+				Condition:                r.Condition,
+				Title:                    r.Title,
+				Data:                     r.Data,
+				IntervalSeconds:          r.IntervalSeconds,
+				NoDataState:              r.NoDataState,
+				ExecErrState:             r.ExecErrState,
+				For:                      r.For,
+				KeepFiringFor:            r.KeepFiringFor,
+				Annotations:              r.Annotations,
+				Labels:                   r.Labels,
+				Record:                   r.Record,
+				DependsOn:                r.DependsOn,
+				ActiveTimeIntervals:      r.ActiveTimeIntervals,
+				GroupActiveTimeIntervals: r.GroupActiveTimeIntervals,
 			})
 		}
 		if len(newRules) > 0 {
@@ -215,23 +220,28 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				RuleGroupIndex:   r.New.RuleGroupIndex,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version + 1,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
+				RuleOrgID:                r.New.OrgID,
+				RuleUID:                  r.New.UID,
+				RuleNamespaceUID:         r.New.NamespaceUID,
+				RuleGroup:                r.New.RuleGroup,
+				RuleGroupIndex:           r.New.RuleGroupIndex,
+				ParentVersion:            parentVersion,
+				Version:                  r.New.Version + 1,
+				Created:                  r.New.Updated,
+				Condition:                r.New.Condition,
+				Title:                    r.New.Title,
+				Data:                     r.New.Data,
+				IntervalSeconds:          r.New.IntervalSeconds,
+				NoDataState:              r.New.NoDataState,
+				ExecErrState:             r.New.ExecErrState,
+				For:                      r.New.For,
+				KeepFiringFor:            r.New.KeepFiringFor,
+				Annotations:              r.New.Annotations,
+				Labels:                   r.New.Labels,
+				Record:                   r.New.Record,
+				DependsOn:                r.New.DependsOn,
+				ActiveTimeIntervals:      r.New.ActiveTimeIntervals,
+				GroupActiveTimeIntervals: r.New.GroupActiveTimeIntervals,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -634,7 +644,7 @@ var GenerateNewAlertRuleUID = func(sess *db.Session, orgID int64, ruleTitle stri
 	return "", ngmodels.ErrAlertRuleFailedGenerateUniqueUID
 }
 
-// validateAlertRule validates the alert rule interval and organisation.
+// validateAlertRule validates the alert rule interval and organisation, and the settings of recording rules.
 func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if len(alertRule.Data) == 0 {
 		return fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
@@ -677,5 +687,22 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if err := alertRule.ValidateDependencies(); err != nil {
+		return err
+	}
+
+	if alertRule.Record != nil {
+		if !st.Cfg.RecordingRules.Enabled {
+			return fmt.Errorf("%w: recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if err := alertRule.Record.Validate(alertRule.Data); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -558,6 +558,47 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 	}
 }
 
+func TestIntegrationInsertRecordingRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+	gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithRecord("some_metric"))
+
+	t.Run("should fail if recording rules are not enabled", func(t *testing.T) {
+		store.Cfg.RecordingRules.Enabled = false
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*gen()})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "recording rules are not enabled")
+	})
+
+	t.Run("should fail if recorded query does not exist", func(t *testing.T) {
+		store.Cfg.RecordingRules.Enabled = true
+		rule := gen()
+		rule.Record.From = "not-a-query"
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should insert valid recording rules", func(t *testing.T) {
+		store.Cfg.RecordingRules.Enabled = true
+		rule := gen()
+		rule.Record.From = rule.Data[0].RefID
+		ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.NoError(t, err)
+		require.Len(t, ids, 1)
+	})
+}
+
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
 	t.Helper()
 	if generate == nil {
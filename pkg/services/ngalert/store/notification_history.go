@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// defaultNotificationHistoryLimit is the maximum number of entries returned by a query without a limit.
+const defaultNotificationHistoryLimit = 1000
+
+// NotificationHistoryStore is the database interface for the history of the notifications sent by the Alertmanager.
+type NotificationHistoryStore interface {
+	// SaveNotificationHistory saves the given attempts to send notifications.
+	SaveNotificationHistory(ctx context.Context, entries ...models.NotificationHistoryEntry) error
+
+	// GetNotificationHistory returns the attempts to send notifications that match the query,
+	// the most recent attempts first.
+	GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+}
+
+func (st DBstore) SaveNotificationHistory(ctx context.Context, entries ...models.NotificationHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for i := range entries {
+			if _, err := sess.Insert(&entries[i]); err != nil {
+				return fmt.Errorf("failed to insert notification history entry: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	var entries []models.NotificationHistoryEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			q = q.And("integration = ?", query.Integration)
+		}
+		if query.GroupKey != "" {
+			q = q.And("group_key = ?", query.GroupKey)
+		}
+		if query.AlertFingerprint != "" {
+			// Fingerprints are stored as a JSON array of strings.
+			q = q.And("alert_fingerprints LIKE ?", fmt.Sprintf("%%%q%%", query.AlertFingerprint))
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if !query.From.IsZero() {
+			q = q.And("sent_at >= ?", query.From.UTC())
+		}
+		if !query.To.IsZero() {
+			q = q.And("sent_at <= ?", query.To.UTC())
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultNotificationHistoryLimit
+		}
+		return q.Desc("sent_at", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification history: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteExpiredNotificationHistory deletes the notification history entries of all organizations that were sent before the given time.
+// It returns the number of deleted entries.
+func DeleteExpiredNotificationHistory(ctx context.Context, store db.DB, before time.Time) (int64, error) {
+	var n int64
+	if err := store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("sent_at < ?", before.UTC()).Delete(&models.NotificationHistoryEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete expired notification history: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationNotificationHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	// Our database schema uses second precision for timestamps.
+	now := time.Now().UTC().Truncate(time.Second)
+	entry := func(orgID int64, receiver string, status models.NotificationStatus, sentAt time.Time, fingerprints ...string) models.NotificationHistoryEntry {
+		return models.NotificationHistoryEntry{
+			OrgID:             orgID,
+			Receiver:          receiver,
+			Integration:       "slack",
+			GroupKey:          "{}:{alertname=\"test\"}",
+			AlertFingerprints: fingerprints,
+			Status:            status,
+			Duration:          time.Second,
+			SentAt:            sentAt,
+		}
+	}
+	require.NoError(t, store.SaveNotificationHistory(ctx,
+		entry(1, "team-a", models.NotificationStatusFailure, now.Add(-3*time.Hour), "aaa"),
+		entry(1, "team-a", models.NotificationStatusSuccess, now.Add(-2*time.Hour), "aaa", "bbb"),
+		entry(1, "team-b", models.NotificationStatusSuccess, now.Add(-1*time.Hour), "ccc"),
+		entry(2, "team-a", models.NotificationStatusSuccess, now, "aaa"),
+	))
+
+	t.Run("returns the most recent entries of the organization first", func(t *testing.T) {
+		res, err := store.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		require.Equal(t, "team-b", res[0].Receiver)
+		require.Equal(t, []string{"aaa", "bbb"}, res[1].AlertFingerprints)
+		require.Equal(t, time.Second, res[1].Duration)
+		require.Equal(t, now.Add(-3*time.Hour), res[2].SentAt.UTC())
+	})
+
+	t.Run("filters entries", func(t *testing.T) {
+		res, err := store.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, Receiver: "team-a", Status: models.NotificationStatusSuccess})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		res, err = store.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, AlertFingerprint: "bbb"})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		res, err = store.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, From: now.Add(-150 * time.Minute), Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, "team-b", res[0].Receiver)
+	})
+
+	t.Run("deletes expired entries of all organizations", func(t *testing.T) {
+		deleted, err := DeleteExpiredNotificationHistory(ctx, sqlStore, now.Add(-90*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		res, err := store.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+	})
+}
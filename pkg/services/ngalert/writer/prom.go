@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	remoteWriteVersionHeader = "X-Prometheus-Remote-Write-Version"
+	remoteWriteVersion       = "0.1.0"
+)
+
+// PrometheusWriter writes the results of recording rules to a Prometheus
+// remote write endpoint.
+type PrometheusWriter struct {
+	url      string
+	username string
+	password string
+	headers  map[string]string
+	client   *http.Client
+	logger   log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.RecordingRuleSettings, l log.Logger) (*PrometheusWriter, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("remote write URL is required")
+	}
+
+	return &PrometheusWriter{
+		url:      cfg.URL,
+		username: cfg.BasicAuthUsername,
+		password: cfg.BasicAuthPassword,
+		headers:  cfg.CustomHeaders,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		logger:   l,
+	}, nil
+}
+
+// Write converts the frames to Prometheus series named after the recorded metric and
+// sends them to the remote write endpoint as a single request.
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	series, err := remotewrite.InstantTimeSeriesFromFrames(name, t, extraLabels, frames...)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		w.logger.Debug("No series to write", "metric", name)
+		return nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set(remoteWriteVersionHeader, remoteWriteVersion)
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("remote write request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	w.logger.Debug("Wrote recording rule results", "metric", name, "series", len(series))
+	return nil
+}
@@ -0,0 +1,85 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	now := time.Now()
+	frames := data.Frames{
+		data.NewFrame("", data.NewField("", map[string]string{"instance": "a"}, []float64{42})),
+	}
+
+	t.Run("sends series to remote write endpoint", func(t *testing.T) {
+		var received prompb.WriteRequest
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			decoded, err := snappy.Decode(nil, body)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(decoded, &received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		w, err := NewPrometheusWriter(setting.RecordingRuleSettings{
+			URL:               srv.URL,
+			BasicAuthUsername: "user",
+			BasicAuthPassword: "pass",
+			Timeout:           time.Second,
+			CustomHeaders:     map[string]string{"X-Scope-OrgID": "1"},
+		}, log.NewNopLogger())
+		require.NoError(t, err)
+
+		err = w.Write(context.Background(), "test_metric", now, frames, map[string]string{"rule": "test"})
+		require.NoError(t, err)
+
+		require.Equal(t, "snappy", header.Get("Content-Encoding"))
+		require.Equal(t, "application/x-protobuf", header.Get("Content-Type"))
+		require.Equal(t, remoteWriteVersion, header.Get(remoteWriteVersionHeader))
+		require.Equal(t, "1", header.Get("X-Scope-OrgID"))
+		require.Equal(t, "Basic dXNlcjpwYXNz", header.Get("Authorization"))
+
+		require.Len(t, received.Timeseries, 1)
+		require.Equal(t, []prompb.Label{
+			{Name: "instance", Value: "a"},
+			{Name: "rule", Value: "test"},
+			{Name: "__name__", Value: "test_metric"},
+		}, received.Timeseries[0].Labels)
+		require.Equal(t, []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 42}}, received.Timeseries[0].Samples)
+	})
+
+	t.Run("returns error on non-2xx response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "out of order sample", http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+
+		w, err := NewPrometheusWriter(setting.RecordingRuleSettings{URL: srv.URL, Timeout: time.Second}, log.NewNopLogger())
+		require.NoError(t, err)
+
+		err = w.Write(context.Background(), "test_metric", now, frames, nil)
+		require.ErrorContains(t, err, "out of order sample")
+	})
+
+	t.Run("fails without URL", func(t *testing.T) {
+		_, err := NewPrometheusWriter(setting.RecordingRuleSettings{}, log.NewNopLogger())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,20 @@
+package writer
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Writer persists the results of recording rules.
+type Writer interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+}
+
+// NoopWriter discards everything it is asked to write. It is used when recording rules are disabled.
+type NoopWriter struct{}
+
+func (w NoopWriter) Write(_ context.Context, _ string, _ time.Time, _ data.Frames, _ map[string]string) error {
+	return nil
+}
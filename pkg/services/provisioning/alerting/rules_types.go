@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -29,6 +30,8 @@ type AlertRuleGroupV1 struct {
 	Folder   values.StringValue `json:"folder" yaml:"folder"`
 	Interval values.StringValue `json:"interval" yaml:"interval"`
 	Rules    []AlertRuleV1      `json:"rules" yaml:"rules"`
+	// ActiveTimeIntervals apply to the rules of the group that do not define their own.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals" yaml:"activeTimeIntervals"`
 }
 
 func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFolderTitle, error) {
@@ -46,6 +49,7 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 		return models.AlertRuleGroupWithFolderTitle{}, err
 	}
 	ruleGroup.Interval = int64(time.Duration(interval).Seconds())
+	ruleGroup.ActiveTimeIntervals = ruleGroupV1.ActiveTimeIntervals
 	ruleGroup.FolderTitle = ruleGroupV1.Folder.Value()
 	if strings.TrimSpace(ruleGroup.FolderTitle) == "" {
 		return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group has no folder set")
@@ -61,18 +65,49 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	Record        *RecordV1             `json:"record" yaml:"record"`
+	DependsOn     []RuleDependencyV1    `json:"dependsOn" yaml:"dependsOn"`
+	// ActiveTimeIntervals are the time intervals in which the rule is evaluated.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals" yaml:"activeTimeIntervals"`
+}
+
+type RuleDependencyV1 struct {
+	RuleUID      values.StringValue `json:"ruleUid" yaml:"ruleUid"`
+	NamespaceUID values.StringValue `json:"namespaceUid" yaml:"namespaceUid"`
+	RuleGroup    values.StringValue `json:"ruleGroup" yaml:"ruleGroup"`
+}
+
+func (dependency *RuleDependencyV1) mapToModel() models.RuleDependency {
+	return models.RuleDependency{
+		RuleUID:      dependency.RuleUID.Value(),
+		NamespaceUID: dependency.NamespaceUID.Value(),
+		RuleGroup:    dependency.RuleGroup.Value(),
+	}
+}
+
+type RecordV1 struct {
+	Metric values.StringValue `json:"metric" yaml:"metric"`
+	From   values.StringValue `json:"from" yaml:"from"`
+}
+
+func (record *RecordV1) mapToModel() models.Record {
+	return models.Record{
+		Metric: record.Metric.Value(),
+		From:   record.From.Value(),
+	}
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -91,6 +126,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
 	alertRule.For = time.Duration(duration)
+	if keepFiringFor := rule.KeepFiringFor.Value(); keepFiringFor != "" {
+		duration, err := model.ParseDuration(keepFiringFor)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
+	}
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -114,7 +156,11 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.NoDataState = noDataState
 	alertRule.Condition = rule.Condition.Value()
-	if alertRule.Condition == "" {
+	if rule.Record != nil {
+		record := rule.Record.mapToModel()
+		alertRule.Record = &record
+	}
+	if alertRule.Condition == "" && alertRule.Record == nil {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no condition set", alertRule.Title)
 	}
 	alertRule.Annotations = rule.Annotations.Raw
@@ -129,6 +175,18 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	if len(alertRule.Data) == 0 {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(alertRule.Data); err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+	}
+	for _, dependency := range rule.DependsOn {
+		alertRule.DependsOn = append(alertRule.DependsOn, dependency.mapToModel())
+	}
+	if alertRule.Record != nil && len(alertRule.DependsOn) > 0 {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: recording rules cannot depend on other rules", alertRule.Title)
+	}
+	alertRule.ActiveTimeIntervals = rule.ActiveTimeIntervals
 	alertRule.IsPaused = rule.IsPaused.Value()
 	return alertRule, nil
 }
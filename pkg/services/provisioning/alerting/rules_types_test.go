@@ -80,6 +80,21 @@ func TestRuleGroup(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(1), rgMapped.OrgID)
 	})
+	t.Run("a rule group with active time intervals should keep them apart from the ones of its rules", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		err := yaml.Unmarshal([]byte("- weekdays: ['monday:friday']\n"), &rg.ActiveTimeIntervals)
+		require.NoError(t, err)
+		rule := validRuleV1(t)
+		err = yaml.Unmarshal([]byte("- times:\n  - start_time: '08:00'\n    end_time: '18:00'\n"), &rule.ActiveTimeIntervals)
+		require.NoError(t, err)
+		rg.Rules = append(rg.Rules, validRuleV1(t), rule)
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Len(t, rgMapped.Rules, 2)
+		require.Equal(t, rg.ActiveTimeIntervals, rgMapped.ActiveTimeIntervals)
+		require.Empty(t, rgMapped.Rules[0].ActiveTimeIntervals)
+		require.Equal(t, rule.ActiveTimeIntervals, rgMapped.Rules[1].ActiveTimeIntervals)
+	})
 }
 
 func TestRules(t *testing.T) {
@@ -88,6 +103,14 @@ func TestRules(t *testing.T) {
 		_, err := rule.mapToModel(1)
 		require.NoError(t, err)
 	})
+	t.Run("a rule with active time intervals should map them", func(t *testing.T) {
+		rule := validRuleV1(t)
+		err := yaml.Unmarshal([]byte("- weekdays: ['saturday', 'sunday']\n  location: Europe/Berlin\n"), &rule.ActiveTimeIntervals)
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, rule.ActiveTimeIntervals, ruleMapped.ActiveTimeIntervals)
+	})
 	t.Run("a rule with out a uid should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.UID = values.StringValue{}
@@ -125,6 +148,38 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with a keepFiringFor duration should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keepFiringFor duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("abc"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with dependencies should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var dependencies []RuleDependencyV1
+		err := yaml.Unmarshal([]byte("- ruleUid: upstream\n- namespaceUid: folder\n  ruleGroup: group\n"), &dependencies)
+		require.NoError(t, err)
+		rule.DependsOn = dependencies
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleDependency{
+			{RuleUID: "upstream"},
+			{NamespaceUID: "folder", RuleGroup: "group"},
+		}, ruleMapped.DependsOn)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -187,6 +242,27 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.NoDataState, models.NoData)
 	})
+	t.Run("a recording rule without a condition should map the record", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		var refID, metric, from values.StringValue
+		require.NoError(t, yaml.Unmarshal([]byte("A"), &refID))
+		require.NoError(t, yaml.Unmarshal([]byte("test_metric"), &metric))
+		require.NoError(t, yaml.Unmarshal([]byte("A"), &from))
+		rule.Data = []QueryV1{{RefID: refID}}
+		rule.Record = &RecordV1{Metric: metric, From: from}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, ruleMapped.Record)
+	})
+	t.Run("a recording rule with an invalid record should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var metric values.StringValue
+		require.NoError(t, yaml.Unmarshal([]byte("test_metric"), &metric))
+		rule.Record = &RecordV1{Metric: metric}
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
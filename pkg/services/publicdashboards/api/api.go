@@ -55,10 +55,13 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetPublicAnnotations))
+	requiresPasscode := RequiresPasscode(api.PublicDashboardService)
 
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", requiresPasscode, routing.Wrap(api.ViewPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", requiresPasscode, routing.Wrap(api.GetPublicAnnotations))
+
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", requiresPasscode, routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/passcode", routing.Wrap(api.AuthenticatePublicDashboard))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -86,6 +89,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Get Public dashboard access log
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/access-log",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardAccessLog))
 }
 
 // swagger:route GET /dashboards/public-dashboards dashboard_public listPublicDashboards
@@ -283,6 +291,36 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, nil)
 }
 
+// swagger:route GET /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/access-log dashboard_public getPublicDashboardAccessLog
+//
+//	Get the most recent views of a public dashboard
+//
+// Responses:
+// 200: getPublicDashboardAccessLogResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) GetPublicDashboardAccessLog(c *contextmodel.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid Uid %s", uid))
+	}
+
+	entries, err := api.PublicDashboardService.FindAccessLog(c.Req.Context(), c.SignedInUser.GetOrgID(), uid, dashboardUid, c.QueryInt("limit"))
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, entries)
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
@@ -353,6 +391,26 @@ type UpdatePublicDashboardResponse struct {
 	Body PublicDashboard `json:"body"`
 }
 
+// swagger:parameters getPublicDashboardAccessLog
+type GetPublicDashboardAccessLogParams struct {
+	// in:path
+	// required:true
+	DashboardUid string `json:"dashboardUid"`
+	// in:path
+	// required:true
+	Uid string `json:"uid"`
+	// The maximum number of views to return, at most 1000
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:response getPublicDashboardAccessLogResponse
+type GetPublicDashboardAccessLogResponse struct {
+	// in: body
+	Body []AccessLogEntry `json:"body"`
+}
+
 // swagger:parameters deletePublicDashboard
 type DeletePublicDashboardParams struct {
 	// in:path
@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -56,6 +57,12 @@ func setupTestServer(
 	// set initial context
 	m.Use(contextProvider(&testContext{user}))
 
+	// public dashboards are not protected by a passcode and views are recorded unless a test expects otherwise
+	if fakeService, ok := service.(*publicdashboards.FakePublicDashboardService); ok {
+		fakeService.On("CheckPasscodeGrant", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+		fakeService.On("LogAccess", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	}
+
 	// build api, this will mount the routes at the same time if
 	// featuremgmt.FlagPublicDashboard is enabled
 	ProvideApi(service, rr, ac, features)
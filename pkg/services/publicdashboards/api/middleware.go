@@ -2,10 +2,14 @@ package api
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -62,6 +66,41 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 	}
 }
 
+// passcodeGrantCookieName is the cookie that holds the grant of a viewer who entered the passcode of a public dashboard.
+// Its path is the API path of the public dashboard, so viewers hold a grant per public dashboard.
+const passcodeGrantCookieName = "grafana_public_dashboard_grant"
+
+// RequiresPasscode Middleware to enforce that viewers of a passcode protected public dashboard entered its passcode
+// before continuing to handler. Requests with a missing or invalid access token are left to the handler.
+func RequiresPasscode(publicDashboardService publicdashboards.Service) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !validation.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		var grant string
+		if cookie, err := c.Req.Cookie(passcodeGrantCookieName); err == nil {
+			grant = cookie.Value
+		}
+
+		if err := publicDashboardService.CheckPasscodeGrant(c.Req.Context(), accessToken, grant); err != nil {
+			c.WriteErr(err)
+			return
+		}
+	}
+}
+
+// writePasscodeGrantCookie sets the grant of a viewer who entered the passcode of a public dashboard until it expires
+func writePasscodeGrantCookie(c *contextmodel.ReqContext, accessToken string, grant *PasscodeGrant) {
+	maxAge := int(time.Until(grant.ExpiresAt).Seconds())
+	cookies.WriteCookie(c.Resp, passcodeGrantCookieName, grant.Value, maxAge, func() cookies.CookieOptions {
+		options := cookies.NewCookieOptions()
+		options.Path = strings.TrimSuffix(options.Path, "/") + "/api/public/dashboards/" + accessToken
+		return options
+	})
+}
+
 func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
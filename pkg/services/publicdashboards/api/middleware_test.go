@@ -8,9 +8,12 @@ import (
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
@@ -83,6 +86,61 @@ func TestRequiresExistingAccessToken(t *testing.T) {
 	}
 }
 
+func TestRequiresPasscode(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		AccessToken          string
+		Grant                string
+		GrantErr             error
+		ExpectedResponseCode int
+	}{
+		{
+			Name:                 "Continues when the grant gives access to the public dashboard",
+			AccessToken:          validAccessToken,
+			Grant:                "grant",
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 401 when the grant does not give access to the public dashboard",
+			AccessToken:          validAccessToken,
+			GrantErr:             ErrPublicDashboardPasscodeRequired.Errorf(""),
+			ExpectedResponseCode: http.StatusUnauthorized,
+		},
+		{
+			Name:                 "Continues when access token is invalid",
+			AccessToken:          "invalidAccessToken",
+			ExpectedResponseCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := publicdashboards.NewFakePublicDashboardService(t)
+			if validation.IsValidAccessToken(tt.AccessToken) {
+				publicdashboardService.On("CheckPasscodeGrant", mock.Anything, tt.AccessToken, tt.Grant).Return(tt.GrantErr)
+			}
+
+			request, err := http.NewRequest(http.MethodGet, "/api/public/dashboards/"+tt.AccessToken, nil)
+			require.NoError(t, err)
+			if tt.Grant != "" {
+				request.AddCookie(&http.Cookie{Name: passcodeGrantCookieName, Value: tt.Grant})
+			}
+			response := httptest.NewRecorder()
+			ctx := &contextmodel.ReqContext{
+				Context: &web.Context{
+					Req:  web.SetURLParams(request, map[string]string{":accessToken": tt.AccessToken}),
+					Resp: web.NewResponseWriter(http.MethodGet, response),
+				},
+				SignedInUser: &user.SignedInUser{},
+				Logger:       log.New("publicdashboards-test"),
+			}
+
+			RequiresPasscode(publicdashboardService)(ctx)
+			require.Equal(t, tt.ExpectedResponseCode, response.Code)
+		})
+	}
+}
+
 func TestSetPublicDashboardOrgIdOnContext(t *testing.T) {
 	tests := []struct {
 		Name          string
@@ -35,9 +35,46 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 		return response.Err(err)
 	}
 
+	api.PublicDashboardService.LogAccess(c.Req.Context(), accessToken, c.RemoteAddr(), c.Req.UserAgent())
+
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route POST /public/dashboards/{accessToken}/passcode dashboard_public authenticatePublicDashboard
+//
+//	Enter the passcode of a public dashboard to get access to it
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 429: tooManyRequestsPublicError
+// 500: internalServerPublicError
+func (api *Api) AuthenticatePublicDashboard(c *contextmodel.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("AuthenticatePublicDashboard: invalid access token"))
+	}
+
+	passcodeDTO := PasscodeDTO{}
+	if err := web.Bind(c.Req, &passcodeDTO); err != nil {
+		return response.Err(ErrBadRequest.Errorf("AuthenticatePublicDashboard: error parsing request: %v", err))
+	}
+
+	grant, err := api.PublicDashboardService.Authenticate(c.Req.Context(), accessToken, passcodeDTO.Passcode, c.RemoteAddr())
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if grant != nil {
+		writePasscodeGrantCookie(c, accessToken, grant)
+	}
+
+	return response.JSON(http.StatusOK, nil)
+}
+
 // swagger:route POST /public/dashboards/{accessToken}/panels/{panelId}/query dashboard_public queryPublicDashboard
 //
 //	Get results for a given panel on a public dashboard
@@ -116,6 +153,15 @@ type ViewPublicDashboardParams struct {
 	AccessToken string `json:"accessToken"`
 }
 
+// swagger:parameters authenticatePublicDashboard
+type AuthenticatePublicDashboardParams struct {
+	// in: path
+	AccessToken string `json:"accessToken"`
+	// in: body
+	// required:true
+	Body PasscodeDTO
+}
+
 // swagger:response queryPublicDashboardResponse
 type QueryPublicDashboardResponse struct {
 	// in: body
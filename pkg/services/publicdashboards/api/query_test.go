@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -25,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources/guardian"
 	datasourcesService "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	publicdashboardsStore "github.com/grafana/grafana/pkg/services/publicdashboards/database"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -142,6 +144,37 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 	}
 }
 
+func TestAPIAuthenticatePublicDashboard(t *testing.T) {
+	path := fmt.Sprintf("/api/public/dashboards/%s/passcode", validAccessToken)
+
+	t.Run("sets a grant cookie that expires with the grant", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("Authenticate", mock.Anything, validAccessToken, "correct horse", mock.Anything).
+			Return(&PasscodeGrant{Value: "grant", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+		testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+		response := callAPI(testServer, http.MethodPost, path, strings.NewReader(`{"passcode":"correct horse"}`), t)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		cookies := response.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, passcodeGrantCookieName, cookies[0].Name)
+		assert.Equal(t, "grant", cookies[0].Value)
+		assert.InDelta(t, time.Hour.Seconds(), cookies[0].MaxAge, 5)
+	})
+
+	t.Run("returns 429 when the viewer is locked out", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("Authenticate", mock.Anything, validAccessToken, "correct horse", mock.Anything).
+			Return(nil, ErrPublicDashboardTooManyPasscodeAttempts.Errorf(""))
+
+		testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+		response := callAPI(testServer, http.MethodPost, path, strings.NewReader(`{"passcode":"correct horse"}`), t)
+		require.Equal(t, http.StatusTooManyRequests, response.Code)
+		assert.Empty(t, response.Result().Cookies())
+	})
+}
+
 // `/public/dashboards/:uid/query“ endpoint test
 func TestAPIQueryPublicDashboard(t *testing.T) {
 	mockedResponse := &backend.QueryDataResponse{
@@ -336,7 +369,7 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 	cfg := setting.NewCfg()
 	ac := acmock.New()
 	ws := publicdashboardsService.ProvideServiceWrapper(store)
-	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac, ws)
+	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac, ws, loginattempttest.FakeLoginAttemptService{ExpectedValid: true})
 	pubdash, err := service.Create(context.Background(), &user.SignedInUser{}, savePubDashboardCmd)
 	require.NoError(t, err)
 
@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -54,7 +56,7 @@ func (d *PublicDashboardStoreImpl) FindAllWithPagination(ctx context.Context, qu
 	}
 
 	pubdashBuilder := db.NewSqlBuilder(d.cfg, d.features, d.sqlStore.GetDialect(), recursiveQueriesAreSupported)
-	pubdashBuilder.Write("SELECT dashboard_public.uid, dashboard_public.access_token, dashboard.uid as dashboard_uid, dashboard_public.is_enabled, dashboard.title")
+	pubdashBuilder.Write("SELECT dashboard_public.uid, dashboard_public.access_token, dashboard.uid as dashboard_uid, dashboard_public.is_enabled, dashboard_public.expires_at, dashboard.title")
 	pubdashBuilder.Write(" FROM dashboard_public")
 	pubdashBuilder.Write(" JOIN dashboard ON dashboard.uid = dashboard_public.dashboard_uid AND dashboard.org_id = dashboard_public.org_id")
 	pubdashBuilder.Write(` WHERE dashboard_public.org_id = ?`, query.OrgID)
@@ -194,9 +196,9 @@ func (d *PublicDashboardStoreImpl) FindByDashboardUid(ctx context.Context, orgId
 func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE dashboard_uid=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE dashboard_uid=? AND is_enabled=true AND (expires_at IS NULL OR expires_at > ?)"
 
-		result, err := dbSession.SQL(sql, dashboardUid).Count()
+		result, err := dbSession.SQL(sql, dashboardUid, time.Now().UTC().Format("2006-01-02 15:04:05")).Count()
 		if err != nil {
 			return err
 		}
@@ -212,9 +214,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true AND (expires_at IS NULL OR expires_at > ?)"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, time.Now().UTC().Format("2006-01-02 15:04:05")).Count()
 		if err != nil {
 			return err
 		}
@@ -268,12 +270,26 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		templateVariablesJSON, err := json.Marshal(cmd.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return err
+		}
+
+		var expiresAt any
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, template_variables = ?, expires_at = ?, passcode = ?, passcode_salt = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			string(templateVariablesJSON),
+			expiresAt,
+			cmd.PublicDashboard.Passcode,
+			cmd.PublicDashboard.PasscodeSalt,
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -334,3 +350,60 @@ func (d *PublicDashboardStoreImpl) GetMetrics(ctx context.Context) (*Metrics, er
 
 	return metrics, nil
 }
+
+// CreateAccessLogEntry records a view of a public dashboard
+func (d *PublicDashboardStoreImpl) CreateAccessLogEntry(ctx context.Context, entry *AccessLogEntry) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+// FindAccessLog Returns the most recent views of a public dashboard first
+func (d *PublicDashboardStoreImpl) FindAccessLog(ctx context.Context, query *AccessLogQuery) ([]*AccessLogEntry, error) {
+	entries := make([]*AccessLogEntry, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND public_dashboard_uid = ?", query.OrgId, query.PublicDashboardUid).
+			Desc("accessed_at", "id").
+			Limit(query.Limit).
+			Find(&entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DisableExpired disables the enabled public dashboards of all organizations that expired before the given time.
+// It returns the number of disabled public dashboards.
+func DisableExpired(ctx context.Context, store db.DB, now time.Time) (int64, error) {
+	var affectedRows int64
+	err := store.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ? WHERE is_enabled = ? AND expires_at <= ?",
+			false, true, now.UTC().Format("2006-01-02 15:04:05"))
+		if err != nil {
+			return fmt.Errorf("failed to disable expired public dashboards: %w", err)
+		}
+		affectedRows, err = sqlResult.RowsAffected()
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// DeleteAccessLogBefore deletes the views of public dashboards of all organizations that happened before the given time.
+// It returns the number of deleted entries.
+func DeleteAccessLogBefore(ctx context.Context, store db.DB, before time.Time) (int64, error) {
+	var affectedRows int64
+	err := store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("accessed_at < ?", before.UTC().Format("2006-01-02 15:04:05")).Delete(&AccessLogEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete public dashboard access log: %w", err)
+		}
+		return nil
+	})
+
+	return affectedRows, err
+}
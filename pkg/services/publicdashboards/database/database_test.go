@@ -254,6 +254,30 @@ func TestIntegrationExistsEnabledByAccessToken(t *testing.T) {
 		require.False(t, res)
 	})
 
+	t.Run("ExistsEnabledByAccessToken will return false when the public dashboard expired", func(t *testing.T) {
+		setup()
+
+		expiresAt := time.Now().Add(-time.Minute)
+		_, err := publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{
+			PublicDashboard: PublicDashboard{
+				IsEnabled:    true,
+				Uid:          "abc123",
+				DashboardUid: savedDashboard.UID,
+				OrgId:        savedDashboard.OrgID,
+				CreatedAt:    time.Now(),
+				CreatedBy:    7,
+				AccessToken:  "accessToken",
+				ExpiresAt:    &expiresAt,
+			},
+		})
+		require.NoError(t, err)
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "accessToken")
+		require.NoError(t, err)
+
+		require.False(t, res)
+	})
+
 	t.Run("ExistsEnabledByAccessToken will return false when no public dashboard has matching access token", func(t *testing.T) {
 		setup()
 
@@ -617,6 +641,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			TemplateVariables:    TemplateVariables{{Name: "env", AllowedValues: []string{"dev", "prod"}}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -638,6 +663,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.TemplateVariables, pdRetrieved.TemplateVariables)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -866,6 +892,83 @@ func TestGetMetrics(t *testing.T) {
 	})
 }
 
+func TestIntegrationDisableExpired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	quotaService := quotatest.New(false, nil)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotaService)
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	ctx := context.Background()
+
+	expired := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "expired", 1, 0, "", false).UID, 1, true, PublicShareType)
+	notExpired := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "not expired", 1, 0, "", false).UID, 1, true, PublicShareType)
+	withoutExpiry := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "without expiry", 1, 0, "", false).UID, 1, true, PublicShareType)
+
+	past := DefaultTime.Add(-time.Hour)
+	future := DefaultTime.Add(time.Hour)
+	expired.ExpiresAt = &past
+	notExpired.ExpiresAt = &future
+	for _, pd := range []*PublicDashboard{expired, notExpired} {
+		_, err := publicdashboardStore.Update(ctx, SavePublicDashboardCommand{PublicDashboard: *pd})
+		require.NoError(t, err)
+	}
+
+	disabled, err := DisableExpired(ctx, sqlStore, DefaultTime)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, disabled)
+
+	for _, tc := range []struct {
+		pd        *PublicDashboard
+		isEnabled bool
+	}{{expired, false}, {notExpired, true}, {withoutExpiry, true}} {
+		pd, err := publicdashboardStore.Find(ctx, tc.pd.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, tc.isEnabled, pd.IsEnabled)
+	}
+}
+
+func TestIntegrationAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	ctx := context.Background()
+
+	for i, uid := range []string{"abc123", "abc123", "abc123", "def456"} {
+		err := publicdashboardStore.CreateAccessLogEntry(ctx, &AccessLogEntry{
+			OrgId:              1,
+			PublicDashboardUid: uid,
+			RemoteAddr:         "127.0.0.1",
+			UserAgent:          "test",
+			AccessedAt:         DefaultTime.Add(time.Duration(i-3) * time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("FindAccessLog returns the most recent views of a public dashboard first", func(t *testing.T) {
+		entries, err := publicdashboardStore.FindAccessLog(ctx, &AccessLogQuery{OrgId: 1, PublicDashboardUid: "abc123", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, DefaultTime.Add(-time.Hour), entries[0].AccessedAt.UTC())
+		assert.Equal(t, "127.0.0.1", entries[0].RemoteAddr)
+		assert.Equal(t, DefaultTime.Add(-2*time.Hour), entries[1].AccessedAt.UTC())
+	})
+
+	t.Run("DeleteAccessLogBefore deletes old views of all public dashboards", func(t *testing.T) {
+		deleted, err := DeleteAccessLogBefore(ctx, sqlStore, DefaultTime.Add(-90*time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, deleted)
+
+		entries, err := publicdashboardStore.FindAccessLog(ctx, &AccessLogQuery{OrgId: 1, PublicDashboardUid: "abc123", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+}
+
 // helper function to insert a dashboard
 func insertTestDashboard(t *testing.T, dashboardStore dashboards.Store, title string, orgId int64,
 	folderId int64, folderUID string, isFolder bool, tags ...any) *dashboards.Dashboard {
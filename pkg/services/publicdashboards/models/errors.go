@@ -23,6 +23,16 @@ var (
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
+	ErrInvalidTemplateVariable             = errutil.BadRequest("publicdashboards.invalidTemplateVariable", errutil.WithPublicMessage("Invalid template variable"))
+	ErrInvalidTemplateVariableValue        = errutil.BadRequest("publicdashboards.invalidTemplateVariableValue", errutil.WithPublicMessage("Invalid template variable value"))
+	ErrInvalidExpiry                       = errutil.BadRequest("publicdashboards.invalidExpiry", errutil.WithPublicMessage("Expiry must be in the future"))
+	ErrInvalidPasscode                     = errutil.BadRequest("publicdashboards.invalidPasscode", errutil.WithPublicMessage("Passcode must have at least 8 characters"))
+
+	ErrPublicDashboardPasscodeRequired = errutil.Unauthorized("publicdashboards.passcodeRequired", errutil.WithPublicMessage("Public dashboard requires a passcode"))
+	ErrPublicDashboardWrongPasscode    = errutil.Unauthorized("publicdashboards.wrongPasscode", errutil.WithPublicMessage("Invalid passcode"))
+
+	ErrPublicDashboardTooManyPasscodeAttempts = errutil.TooManyRequests("publicdashboards.tooManyPasscodeAttempts", errutil.WithPublicMessage("Too many invalid passcodes, try again later"))
 
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired    = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
 )
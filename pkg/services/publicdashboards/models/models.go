@@ -30,6 +30,9 @@ const (
 	PublicShareType ShareType = "public"
 )
 
+// MinPasscodeLength is the minimum number of characters of the passcode of a public dashboard
+const MinPasscodeLength = 8
+
 var (
 	QueryResultStatuses = []string{QuerySuccess, QueryFailure}
 	ValidShareTypes     = []ShareType{EmailShareType, PublicShareType}
@@ -53,6 +56,41 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// TemplateVariables are the template variables of the dashboard that viewers can change
+	TemplateVariables TemplateVariables `json:"templateVariables,omitempty" xorm:"template_variables"`
+	// ExpiresAt is the time after which the public dashboard is disabled
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+	// Passcode is the hash of the passcode viewers have to enter, empty if the public dashboard is not protected
+	Passcode     string `json:"-" xorm:"passcode"`
+	PasscodeSalt string `json:"-" xorm:"passcode_salt"`
+}
+
+// IsExpired returns true if the public dashboard has an expiry that is not after the given time
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.After(now)
+}
+
+// IsPasscodeProtected returns true if viewers have to enter a passcode to access the public dashboard
+func (pd PublicDashboard) IsPasscodeProtected() bool {
+	return pd.Passcode != ""
+}
+
+// MarshalJSON adds whether the public dashboard is protected by a passcode, without exposing the passcode hash
+func (pd PublicDashboard) MarshalJSON() ([]byte, error) {
+	type publicDashboard PublicDashboard
+	return json.Marshal(struct {
+		publicDashboard
+		PasscodeProtected bool `json:"passcodeProtected"`
+	}{
+		publicDashboard:   publicDashboard(pd),
+		PasscodeProtected: pd.IsPasscodeProtected(),
+	})
+}
+
+// PasscodeGrant gives a viewer who entered the passcode of a public dashboard access to it until it expires
+type PasscodeGrant struct {
+	Value     string
+	ExpiresAt time.Time
 }
 
 type PublicDashboardDTO struct {
@@ -62,6 +100,12 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// TemplateVariables sets the template variables viewers can change, an empty list removes them
+	TemplateVariables *TemplateVariables `json:"templateVariables"`
+	// ExpiresAt sets the expiry of the public dashboard, the zero time removes it
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Passcode sets the passcode of the public dashboard, an empty passcode removes the protection
+	Passcode *string `json:"passcode"`
 }
 
 type EmailDTO struct {
@@ -112,11 +156,32 @@ type PublicDashboardListResponseWithPagination struct {
 }
 
 type PublicDashboardListResponse struct {
-	Uid          string `json:"uid" xorm:"uid"`
-	AccessToken  string `json:"accessToken" xorm:"access_token"`
-	Title        string `json:"title" xorm:"title"`
-	DashboardUid string `json:"dashboardUid" xorm:"dashboard_uid"`
-	IsEnabled    bool   `json:"isEnabled" xorm:"is_enabled"`
+	Uid          string     `json:"uid" xorm:"uid"`
+	AccessToken  string     `json:"accessToken" xorm:"access_token"`
+	Title        string     `json:"title" xorm:"title"`
+	DashboardUid string     `json:"dashboardUid" xorm:"dashboard_uid"`
+	IsEnabled    bool       `json:"isEnabled" xorm:"is_enabled"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+}
+
+// AccessLogEntry is a view of a public dashboard
+type AccessLogEntry struct {
+	Id                 int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgId              int64     `json:"-" xorm:"org_id"`
+	PublicDashboardUid string    `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	RemoteAddr         string    `json:"remoteAddr" xorm:"remote_addr"`
+	UserAgent          string    `json:"userAgent" xorm:"user_agent"`
+	AccessedAt         time.Time `json:"accessedAt" xorm:"accessed_at"`
+}
+
+func (e AccessLogEntry) TableName() string {
+	return "dashboard_public_access_log"
+}
+
+type AccessLogQuery struct {
+	OrgId              int64
+	PublicDashboardUid string
+	Limit              int
 }
 
 type TimeSettings struct {
@@ -132,6 +197,38 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// TemplateVariables are the template variables of a dashboard that viewers of its public dashboard can change
+type TemplateVariables []TemplateVariable
+
+// TemplateVariable allows viewers to pick the values of a template variable of the dashboard
+type TemplateVariable struct {
+	// Name is the name of the template variable in the dashboard
+	Name string `json:"name"`
+	// AllowedValues are the values viewers can pick, in addition to the default value of the template variable
+	AllowedValues []string `json:"allowedValues"`
+}
+
+// Find returns the template variable with the given name or nil if viewers cannot change it
+func (tv TemplateVariables) Find(name string) *TemplateVariable {
+	for i := range tv {
+		if tv[i].Name == name {
+			return &tv[i]
+		}
+	}
+	return nil
+}
+
+func (tv *TemplateVariables) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, tv)
+}
+
+func (tv *TemplateVariables) ToDB() ([]byte, error) {
+	return json.Marshal(tv)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -152,6 +249,12 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the values picked by the viewer for the template variables of the public dashboard
+	Variables map[string][]string
+}
+
+type PasscodeDTO struct {
+	Passcode string `json:"passcode"`
 }
 
 type AnnotationsQueryDTO struct {
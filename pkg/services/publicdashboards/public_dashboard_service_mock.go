@@ -23,6 +23,46 @@ type FakePublicDashboardService struct {
 	mock.Mock
 }
 
+// Authenticate provides a mock function with given fields: ctx, accessToken, passcode, remoteAddr
+func (_m *FakePublicDashboardService) Authenticate(ctx context.Context, accessToken string, passcode string, remoteAddr string) (*models.PasscodeGrant, error) {
+	ret := _m.Called(ctx, accessToken, passcode, remoteAddr)
+
+	var r0 *models.PasscodeGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.PasscodeGrant, error)); ok {
+		return rf(ctx, accessToken, passcode, remoteAddr)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.PasscodeGrant); ok {
+		r0 = rf(ctx, accessToken, passcode, remoteAddr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PasscodeGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, accessToken, passcode, remoteAddr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckPasscodeGrant provides a mock function with given fields: ctx, accessToken, grant
+func (_m *FakePublicDashboardService) CheckPasscodeGrant(ctx context.Context, accessToken string, grant string) error {
+	ret := _m.Called(ctx, accessToken, grant)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, accessToken, grant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -151,6 +191,32 @@ func (_m *FakePublicDashboardService) Find(ctx context.Context, uid string) (*mo
 	return r0, r1
 }
 
+// FindAccessLog provides a mock function with given fields: ctx, orgId, uid, dashboardUid, limit
+func (_m *FakePublicDashboardService) FindAccessLog(ctx context.Context, orgId int64, uid string, dashboardUid string, limit int) ([]*models.AccessLogEntry, error) {
+	ret := _m.Called(ctx, orgId, uid, dashboardUid, limit)
+
+	var r0 []*models.AccessLogEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, int) ([]*models.AccessLogEntry, error)); ok {
+		return rf(ctx, orgId, uid, dashboardUid, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, int) []*models.AccessLogEntry); ok {
+		r0 = rf(ctx, orgId, uid, dashboardUid, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AccessLogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, int) error); ok {
+		r1 = rf(ctx, orgId, uid, dashboardUid, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllWithPagination provides a mock function with given fields: ctx, query
 func (_m *FakePublicDashboardService) FindAllWithPagination(ctx context.Context, query *models.PublicDashboardListQuery) (*models.PublicDashboardListResponseWithPagination, error) {
 	ret := _m.Called(ctx, query)
@@ -451,6 +517,11 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 	return r0, r1
 }
 
+// LogAccess provides a mock function with given fields: ctx, accessToken, remoteAddr, userAgent
+func (_m *FakePublicDashboardService) LogAccess(ctx context.Context, accessToken string, remoteAddr string, userAgent string) {
+	_m.Called(ctx, accessToken, remoteAddr, userAgent)
+}
+
 // NewPublicDashboardAccessToken provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.Context) (string, error) {
 	ret := _m.Called(ctx)
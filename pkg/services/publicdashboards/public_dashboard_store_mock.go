@@ -40,6 +40,20 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateAccessLogEntry provides a mock function with given fields: ctx, entry
+func (_m *FakePublicDashboardStore) CreateAccessLogEntry(ctx context.Context, entry *models.AccessLogEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AccessLogEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int64, error) {
 	ret := _m.Called(ctx, uid)
@@ -138,6 +152,32 @@ func (_m *FakePublicDashboardStore) Find(ctx context.Context, uid string) (*mode
 	return r0, r1
 }
 
+// FindAccessLog provides a mock function with given fields: ctx, query
+func (_m *FakePublicDashboardStore) FindAccessLog(ctx context.Context, query *models.AccessLogQuery) ([]*models.AccessLogEntry, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*models.AccessLogEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AccessLogQuery) ([]*models.AccessLogEntry, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AccessLogQuery) []*models.AccessLogEntry); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AccessLogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.AccessLogQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllWithPagination provides a mock function with given fields: ctx, query
 func (_m *FakePublicDashboardStore) FindAllWithPagination(ctx context.Context, query *models.PublicDashboardListQuery) (*models.PublicDashboardListResponseWithPagination, error) {
 	ret := _m.Called(ctx, query)
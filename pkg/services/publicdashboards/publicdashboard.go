@@ -37,6 +37,11 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	Authenticate(ctx context.Context, accessToken string, passcode string, remoteAddr string) (*PasscodeGrant, error)
+	CheckPasscodeGrant(ctx context.Context, accessToken string, grant string) error
+	LogAccess(ctx context.Context, accessToken string, remoteAddr string, userAgent string)
+	FindAccessLog(ctx context.Context, orgId int64, uid string, dashboardUid string, limit int) ([]*AccessLogEntry, error)
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -63,4 +68,6 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+	CreateAccessLogEntry(ctx context.Context, entry *AccessLogEntry) error
+	FindAccessLog(ctx context.Context, query *AccessLogQuery) ([]*AccessLogEntry, error)
 }
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	// maxAccessLogLimit is the maximum number of views returned for a public dashboard
+	maxAccessLogLimit = 1000
+	// accessLogInterval is how often the views of a viewer of a public dashboard are recorded
+	accessLogInterval = 10 * time.Minute
+	// maxAccessLogViewers is the number of viewers whose last recorded view is remembered, views of further
+	// viewers are not recorded until the views of others are forgotten
+	maxAccessLogViewers = 10000
+	// maxPendingAccessLogEntries is the number of views recorded at once, further views are not recorded
+	maxPendingAccessLogEntries = 100
+	// accessLogTimeout is how long recording a view can take
+	accessLogTimeout = 5 * time.Second
+)
+
+// accessLogKey identifies a viewer of a public dashboard
+type accessLogKey struct {
+	accessToken string
+	remoteAddr  string
+	userAgent   string
+}
+
+// accessLogger samples the views of public dashboards and records them in the background
+type accessLogger struct {
+	mtx          sync.Mutex
+	lastRecorded map[accessLogKey]time.Time
+	lastEviction time.Time
+
+	pending chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newAccessLogger() *accessLogger {
+	return &accessLogger{
+		lastRecorded: make(map[accessLogKey]time.Time),
+		pending:      make(chan struct{}, maxPendingAccessLogEntries),
+	}
+}
+
+// sample returns true if the view should be recorded, that is if the viewer has not been recorded in the last
+// accessLogInterval
+func (l *accessLogger) sample(key accessLogKey, now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.Sub(l.lastEviction) >= accessLogInterval {
+		for k, t := range l.lastRecorded {
+			if now.Sub(t) >= accessLogInterval {
+				delete(l.lastRecorded, k)
+			}
+		}
+		l.lastEviction = now
+	}
+
+	if t, ok := l.lastRecorded[key]; ok && now.Sub(t) < accessLogInterval {
+		return false
+	}
+	if len(l.lastRecorded) >= maxAccessLogViewers {
+		return false
+	}
+
+	l.lastRecorded[key] = now
+	return true
+}
+
+// LogAccess records a view of a public dashboard in the background. A viewer is recorded at most once every
+// accessLogInterval, and views are dropped rather than queued when too many are being recorded, so that recording
+// views never delays or fails them.
+func (pd *PublicDashboardServiceImpl) LogAccess(ctx context.Context, accessToken string, remoteAddr string, userAgent string) {
+	logger := pd.log.FromContext(ctx)
+
+	now := time.Now()
+	if !pd.accessLog.sample(accessLogKey{accessToken: accessToken, remoteAddr: remoteAddr, userAgent: userAgent}, now) {
+		return
+	}
+
+	select {
+	case pd.accessLog.pending <- struct{}{}:
+	default:
+		logger.Warn("Dropped view of public dashboard, too many views are being recorded")
+		return
+	}
+
+	pd.accessLog.wg.Add(1)
+	go func() {
+		defer func() {
+			<-pd.accessLog.pending
+			pd.accessLog.wg.Done()
+		}()
+
+		// the view is recorded after the request is done
+		ctx, cancel := context.WithTimeout(context.Background(), accessLogTimeout)
+		defer cancel()
+
+		pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
+		if err != nil {
+			logger.Error("Failed to find public dashboard to record view", "error", err)
+			return
+		}
+		if pubdash == nil {
+			return
+		}
+
+		err = pd.store.CreateAccessLogEntry(ctx, &AccessLogEntry{
+			OrgId:              pubdash.OrgId,
+			PublicDashboardUid: pubdash.Uid,
+			RemoteAddr:         remoteAddr,
+			UserAgent:          userAgent,
+			AccessedAt:         now,
+		})
+		if err != nil {
+			logger.Error("Failed to record view of public dashboard", "publicDashboardUid", pubdash.Uid, "error", err)
+		}
+	}()
+}
+
+// FindAccessLog Returns the most recent views of a public dashboard first
+func (pd *PublicDashboardServiceImpl) FindAccessLog(ctx context.Context, orgId int64, uid string, dashboardUid string, limit int) ([]*AccessLogEntry, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLog: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+
+	// validate the public dashboard belongs to the dashboard of the org
+	if pubdash == nil || pubdash.OrgId != orgId {
+		return nil, ErrPublicDashboardNotFound.Errorf("FindAccessLog: public dashboard not found by uid: %s", uid)
+	}
+	if pubdash.DashboardUid != dashboardUid {
+		return nil, ErrInvalidUid.Errorf("FindAccessLog: the public dashboard does not belong to the dashboard")
+	}
+
+	if limit <= 0 || limit > maxAccessLogLimit {
+		limit = maxAccessLogLimit
+	}
+
+	entries, err := pd.store.FindAccessLog(ctx, &AccessLogQuery{OrgId: orgId, PublicDashboardUid: uid, Limit: limit})
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLog: failed to find the views of public dashboard %s: %w", uid, err)
+	}
+
+	return entries, nil
+}
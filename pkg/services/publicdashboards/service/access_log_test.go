@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func TestLogAccess(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "abc", AccessToken: "token", OrgId: 1, IsEnabled: true}
+
+	t.Run("records a viewer at most once per interval", func(t *testing.T) {
+		store := &FakePublicDashboardStore{}
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("CreateAccessLogEntry", mock.Anything, mock.Anything).Return(nil)
+
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store, accessLog: newAccessLogger()}
+		service.LogAccess(context.Background(), "token", "10.0.0.1", "firefox")
+		service.LogAccess(context.Background(), "token", "10.0.0.1", "firefox")
+		service.LogAccess(context.Background(), "token", "10.0.0.2", "firefox")
+		service.accessLog.wg.Wait()
+
+		store.AssertNumberOfCalls(t, "CreateAccessLogEntry", 2)
+		store.AssertCalled(t, "CreateAccessLogEntry", mock.Anything, mock.MatchedBy(func(e *AccessLogEntry) bool {
+			return e.OrgId == 1 && e.PublicDashboardUid == "abc" && e.RemoteAddr == "10.0.0.2" && e.UserAgent == "firefox"
+		}))
+	})
+
+	t.Run("records a viewer again after the interval", func(t *testing.T) {
+		l := newAccessLogger()
+		key := accessLogKey{accessToken: "token", remoteAddr: "10.0.0.1", userAgent: "firefox"}
+		now := time.Now()
+
+		require.True(t, l.sample(key, now))
+		require.False(t, l.sample(key, now.Add(accessLogInterval/2)))
+		require.True(t, l.sample(key, now.Add(accessLogInterval)))
+	})
+
+	t.Run("does not fail when the view cannot be recorded", func(t *testing.T) {
+		store := &FakePublicDashboardStore{}
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("CreateAccessLogEntry", mock.Anything, mock.Anything).Return(errors.New("database is locked"))
+
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store, accessLog: newAccessLogger()}
+		service.LogAccess(context.Background(), "token", "10.0.0.1", "firefox")
+		service.accessLog.wg.Wait()
+
+		store.AssertNumberOfCalls(t, "CreateAccessLogEntry", 1)
+	})
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// passcodeGrantTTL is how long viewers keep access to a public dashboard after entering its passcode
+const passcodeGrantTTL = 24 * time.Hour
+
+// Authenticate checks the passcode of a public dashboard and returns the grant that gives access to it.
+// The grant is nil if the public dashboard is not protected by a passcode. Viewers who entered too many invalid
+// passcodes for a public dashboard are locked out of it for a while.
+func (pd *PublicDashboardServiceImpl) Authenticate(ctx context.Context, accessToken string, passcode string, remoteAddr string) (*PasscodeGrant, error) {
+	pubdash, err := pd.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !pubdash.IsEnabled {
+		return nil, ErrPublicDashboardNotEnabled.Errorf("Authenticate: Public dashboard is not enabled accessToken: %s", accessToken)
+	}
+
+	if pubdash.IsExpired(now) {
+		return nil, ErrPublicDashboardExpired.Errorf("Authenticate: Public dashboard expired accessToken: %s", accessToken)
+	}
+
+	if !pubdash.IsPasscodeProtected() {
+		return nil, nil
+	}
+
+	attemptsKey := passcodeAttemptsKey(accessToken, remoteAddr)
+	ok, err := pd.loginAttempts.Validate(ctx, attemptsKey)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("Authenticate: failed to check passcode attempts: %w", err)
+	}
+	if !ok {
+		return nil, ErrPublicDashboardTooManyPasscodeAttempts.Errorf("Authenticate: too many invalid passcodes for public dashboard %s", pubdash.Uid)
+	}
+
+	hash, err := util.EncodePassword(passcode, pubdash.PasscodeSalt)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("Authenticate: failed to hash passcode: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(pubdash.Passcode)) != 1 {
+		pd.log.Warn("Invalid passcode for public dashboard", "publicDashboardUid", pubdash.Uid)
+		if err := pd.loginAttempts.Add(ctx, attemptsKey, remoteAddr); err != nil {
+			pd.log.Error("Failed to record invalid passcode for public dashboard", "publicDashboardUid", pubdash.Uid, "error", err)
+		}
+		return nil, ErrPublicDashboardWrongPasscode.Errorf("Authenticate: invalid passcode for public dashboard %s", pubdash.Uid)
+	}
+
+	if err := pd.loginAttempts.Reset(ctx, attemptsKey); err != nil {
+		pd.log.Error("Failed to reset invalid passcodes for public dashboard", "publicDashboardUid", pubdash.Uid, "error", err)
+	}
+
+	// the grant does not outlive the public dashboard
+	expiresAt := now.Add(passcodeGrantTTL)
+	if pubdash.ExpiresAt != nil && pubdash.ExpiresAt.Before(expiresAt) {
+		expiresAt = *pubdash.ExpiresAt
+	}
+
+	return &PasscodeGrant{
+		Value:     pd.passcodeGrant(pubdash, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CheckPasscodeGrant returns an error if the public dashboard is protected by a passcode and the grant does not
+// give access to it. Public dashboards that do not exist are left to the handlers.
+func (pd *PublicDashboardServiceImpl) CheckPasscodeGrant(ctx context.Context, accessToken string, grant string) error {
+	pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return ErrInternalServerError.Errorf("CheckPasscodeGrant: failed to find a public dashboard: %w", err)
+	}
+
+	if pubdash == nil || !pubdash.IsPasscodeProtected() {
+		return nil
+	}
+
+	expiry, _, _ := strings.Cut(grant, ".")
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !time.Unix(seconds, 0).After(time.Now()) {
+		return ErrPublicDashboardPasscodeRequired.Errorf("CheckPasscodeGrant: missing or expired grant for public dashboard %s", pubdash.Uid)
+	}
+
+	if !hmac.Equal([]byte(grant), []byte(pd.passcodeGrant(pubdash, time.Unix(seconds, 0)))) {
+		return ErrPublicDashboardPasscodeRequired.Errorf("CheckPasscodeGrant: invalid grant for public dashboard %s", pubdash.Uid)
+	}
+
+	return nil
+}
+
+// passcodeGrant signs the access token and the expiry of the grant with the passcode hash, so that grants expire
+// and are revoked when the passcode changes
+func (pd *PublicDashboardServiceImpl) passcodeGrant(pubdash *PublicDashboard, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(pd.cfg.SecretKey))
+	mac.Write([]byte(pubdash.AccessToken + ":" + pubdash.Passcode + ":" + expiry))
+	return expiry + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// passcodeAttemptsKey identifies the passcode attempts of a viewer of a public dashboard
+func passcodeAttemptsKey(accessToken string, remoteAddr string) string {
+	return "public-dashboard:" + accessToken + ":" + remoteAddr
+}
+
+// hashPasscode returns the hash of the passcode and the salt used to compute it
+func hashPasscode(passcode string) (string, string, error) {
+	salt, err := util.GetRandomString(10)
+	if err != nil {
+		return "", "", ErrInternalServerError.Errorf("failed to generate passcode salt: %w", err)
+	}
+
+	hash, err := util.EncodePassword(passcode, salt)
+	if err != nil {
+		return "", "", ErrInternalServerError.Errorf("failed to hash passcode: %w", err)
+	}
+
+	return hash, salt, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/database"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestPasscode(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	quotaService := quotatest.New(false, nil)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotaService)
+	require.NoError(t, err)
+	publicdashboardStore := database.ProvideStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures())
+	dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true, []map[string]any{}, nil)
+
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+	loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+	service := &PublicDashboardServiceImpl{
+		log:            log.New("test.logger"),
+		cfg:            cfg,
+		store:          publicdashboardStore,
+		serviceWrapper: ProvideServiceWrapper(publicdashboardStore),
+		loginAttempts:  loginAttempts,
+	}
+	ctx := context.Background()
+
+	pubdash, err := service.Create(ctx, SignedInUser, &SavePublicDashboardDTO{
+		DashboardUid: dashboard.UID,
+		UserId:       7,
+		OrgID:        dashboard.OrgID,
+		PublicDashboard: &PublicDashboardDTO{
+			IsEnabled: util.Pointer(true),
+			Passcode:  util.Pointer("correct horse"),
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, pubdash.IsPasscodeProtected())
+	require.NotEqual(t, "correct horse", pubdash.Passcode)
+
+	t.Run("passcode hash is not exposed", func(t *testing.T) {
+		b, err := pubdash.MarshalJSON()
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), pubdash.Passcode)
+		assert.Contains(t, string(b), `"passcodeProtected":true`)
+	})
+
+	t.Run("requires a grant", func(t *testing.T) {
+		err := service.CheckPasscodeGrant(ctx, pubdash.AccessToken, "")
+		require.ErrorIs(t, err, ErrPublicDashboardPasscodeRequired)
+	})
+
+	t.Run("rejects a wrong passcode", func(t *testing.T) {
+		_, err := service.Authenticate(ctx, pubdash.AccessToken, "wrong horse", "10.0.0.1")
+		require.ErrorIs(t, err, ErrPublicDashboardWrongPasscode)
+		require.True(t, loginAttempts.AddCalled)
+	})
+
+	t.Run("locks out viewers who entered too many wrong passcodes", func(t *testing.T) {
+		loginAttempts.ExpectedValid = false
+		t.Cleanup(func() { loginAttempts.ExpectedValid = true })
+
+		_, err := service.Authenticate(ctx, pubdash.AccessToken, "correct horse", "10.0.0.1")
+		require.ErrorIs(t, err, ErrPublicDashboardTooManyPasscodeAttempts)
+	})
+
+	var grant string
+	t.Run("grants access with the passcode", func(t *testing.T) {
+		g, err := service.Authenticate(ctx, pubdash.AccessToken, "correct horse", "10.0.0.1")
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.True(t, loginAttempts.ResetCalled)
+		assert.WithinDuration(t, time.Now().Add(passcodeGrantTTL), g.ExpiresAt, time.Minute)
+
+		grant = g.Value
+		require.NoError(t, service.CheckPasscodeGrant(ctx, pubdash.AccessToken, grant))
+	})
+
+	t.Run("rejects expired or tampered grants", func(t *testing.T) {
+		expired := service.passcodeGrant(pubdash, time.Now().Add(-time.Minute))
+		require.ErrorIs(t, service.CheckPasscodeGrant(ctx, pubdash.AccessToken, expired), ErrPublicDashboardPasscodeRequired)
+
+		_, mac, _ := strings.Cut(grant, ".")
+		extended := strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10) + "." + mac
+		require.ErrorIs(t, service.CheckPasscodeGrant(ctx, pubdash.AccessToken, extended), ErrPublicDashboardPasscodeRequired)
+	})
+
+	t.Run("changing the passcode revokes grants", func(t *testing.T) {
+		_, err := service.Update(ctx, SignedInUser, &SavePublicDashboardDTO{
+			Uid:             pubdash.Uid,
+			DashboardUid:    dashboard.UID,
+			PublicDashboard: &PublicDashboardDTO{Passcode: util.Pointer("battery staple")},
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, service.CheckPasscodeGrant(ctx, pubdash.AccessToken, grant), ErrPublicDashboardPasscodeRequired)
+	})
+
+	t.Run("removing the passcode gives access to everyone", func(t *testing.T) {
+		updated, err := service.Update(ctx, SignedInUser, &SavePublicDashboardDTO{
+			Uid:             pubdash.Uid,
+			DashboardUid:    dashboard.UID,
+			PublicDashboard: &PublicDashboardDTO{Passcode: util.Pointer("")},
+		})
+		require.NoError(t, err)
+		require.False(t, updated.IsPasscodeProtected())
+		require.NoError(t, service.CheckPasscodeGrant(ctx, pubdash.AccessToken, ""))
+	})
+
+	t.Run("grants do not outlive the public dashboard", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		_, err := service.Update(ctx, SignedInUser, &SavePublicDashboardDTO{
+			Uid:             pubdash.Uid,
+			DashboardUid:    dashboard.UID,
+			PublicDashboard: &PublicDashboardDTO{Passcode: util.Pointer("battery staple"), ExpiresAt: &expiresAt},
+		})
+		require.NoError(t, err)
+
+		g, err := service.Authenticate(ctx, pubdash.AccessToken, "battery staple", "10.0.0.1")
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		assert.WithinDuration(t, expiresAt, g.ExpiresAt, time.Second)
+	})
+
+	t.Run("does not authenticate expired public dashboards", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		updated, err := service.Update(ctx, SignedInUser, &SavePublicDashboardDTO{
+			Uid:             pubdash.Uid,
+			DashboardUid:    dashboard.UID,
+			PublicDashboard: &PublicDashboardDTO{ExpiresAt: &expiresAt},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, updated.ExpiresAt)
+
+		_, err = publicdashboardStore.Update(ctx, SavePublicDashboardCommand{PublicDashboard: PublicDashboard{
+			Uid:          updated.Uid,
+			IsEnabled:    true,
+			TimeSettings: updated.TimeSettings,
+			Share:        updated.Share,
+			ExpiresAt:    util.Pointer(time.Now().Add(-time.Hour)),
+		}})
+		require.NoError(t, err)
+
+		_, err = service.Authenticate(ctx, pubdash.AccessToken, "", "10.0.0.1")
+		require.ErrorIs(t, err, ErrPublicDashboardExpired)
+	})
+}
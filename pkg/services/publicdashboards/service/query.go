@@ -162,6 +162,18 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
 	}
 
+	// resolve template variables on the server so viewers cannot change the queries
+	variables, err := resolveTemplateVariables(dashboard.Data, publicDashboard.TemplateVariables, reqDTO.Variables)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+	for i := range queries {
+		err = interpolateQuery(queries[i], variables)
+		if err != nil {
+			return dtos.MetricRequest{}, err
+		}
+	}
+
 	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard)
 
 	// determine safe resolution to query data at
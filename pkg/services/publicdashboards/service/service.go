@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -35,6 +36,8 @@ type PublicDashboardServiceImpl struct {
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
 	serviceWrapper     publicdashboards.ServiceWrapper
+	loginAttempts      loginattempt.Service
+	accessLog          *accessLogger
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -52,6 +55,7 @@ func ProvideService(
 	anno annotations.Repository,
 	ac accesscontrol.AccessControl,
 	serviceWrapper publicdashboards.ServiceWrapper,
+	loginAttempts loginattempt.Service,
 ) *PublicDashboardServiceImpl {
 	return &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
@@ -62,6 +66,8 @@ func ProvideService(
 		AnnotationsRepo:    anno,
 		ac:                 ac,
 		serviceWrapper:     serviceWrapper,
+		loginAttempts:      loginAttempts,
+		accessLog:          newAccessLogger(),
 	}
 }
 
@@ -89,6 +95,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	sanitizeTemplateVariables(dash.Data, pubdash.TemplateVariables)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -145,6 +152,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired accessToken: %s", accessToken)
+	}
+
 	return pubdash, dash, err
 }
 
@@ -176,11 +187,19 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	// validate the template variables viewers can change exist in the dashboard
+	if dto.PublicDashboard.TemplateVariables != nil {
+		err = validateTemplateVariables(dashboard.Data, *dto.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// validate the dashboard does not already have a public dashboard
 	existingPubdash, err := pd.FindByDashboardUid(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil && !errors.Is(err, ErrPublicDashboardNotFound) {
@@ -227,11 +246,19 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	// validate dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	// validate the template variables viewers can change exist in the dashboard
+	if dto.PublicDashboard.TemplateVariables != nil {
+		err = validateTemplateVariables(dashboard.Data, *dto.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.Uid)
 	if err != nil {
@@ -245,7 +272,15 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrInvalidUid.Errorf("Update: the public dashboard does not belong to the dashboard")
 	}
 
-	publicDashboard := newUpdatePublicDashboard(dto, existingPubdash)
+	publicDashboard, err := newUpdatePublicDashboard(dto, existingPubdash)
+	if err != nil {
+		return nil, err
+	}
+
+	// an expired public dashboard can only be enabled again with a new expiry
+	if publicDashboard.IsEnabled && publicDashboard.IsExpired(time.Now()) {
+		return nil, ErrInvalidExpiry.Errorf("Update: public dashboard %s expired", dto.Uid)
+	}
 
 	// set values to update
 	cmd := SavePublicDashboardCommand{
@@ -476,6 +511,24 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	var templateVariables TemplateVariables
+	if dto.PublicDashboard.TemplateVariables != nil {
+		templateVariables = *dto.PublicDashboard.TemplateVariables
+	}
+
+	var expiresAt *time.Time
+	if dto.PublicDashboard.ExpiresAt != nil && !dto.PublicDashboard.ExpiresAt.IsZero() {
+		expiresAt = dto.PublicDashboard.ExpiresAt
+	}
+
+	var passcode, passcodeSalt string
+	if dto.PublicDashboard.Passcode != nil && *dto.PublicDashboard.Passcode != "" {
+		passcode, passcodeSalt, err = hashPasscode(*dto.PublicDashboard.Passcode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -487,15 +540,19 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
 		Share:                share,
+		TemplateVariables:    templateVariables,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            now,
 		AccessToken:          accessToken,
+		ExpiresAt:            expiresAt,
+		Passcode:             passcode,
+		PasscodeSalt:         passcodeSalt,
 	}, nil
 }
 
-func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) *PublicDashboard {
+func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) (*PublicDashboard, error) {
 	pubdashDTO := dto.PublicDashboard
 	timeSelectionEnabled := returnValueOrDefault(pubdashDTO.TimeSelectionEnabled, pd.TimeSelectionEnabled)
 	isEnabled := returnValueOrDefault(pubdashDTO.IsEnabled, pd.IsEnabled)
@@ -506,6 +563,31 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	templateVariables := pd.TemplateVariables
+	if pubdashDTO.TemplateVariables != nil {
+		templateVariables = *pubdashDTO.TemplateVariables
+	}
+
+	expiresAt := pd.ExpiresAt
+	if pubdashDTO.ExpiresAt != nil {
+		expiresAt = pubdashDTO.ExpiresAt
+		if pubdashDTO.ExpiresAt.IsZero() {
+			expiresAt = nil
+		}
+	}
+
+	passcode, passcodeSalt := pd.Passcode, pd.PasscodeSalt
+	if pubdashDTO.Passcode != nil {
+		passcode, passcodeSalt = "", ""
+		if *pubdashDTO.Passcode != "" {
+			var err error
+			passcode, passcodeSalt, err = hashPasscode(*pubdashDTO.Passcode)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -513,9 +595,13 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		TemplateVariables:    templateVariables,
+		ExpiresAt:            expiresAt,
+		Passcode:             passcode,
+		PasscodeSalt:         passcodeSalt,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
-	}
+	}, nil
 }
 
 func returnValueOrDefault(value *bool, defaultValue bool) bool {
@@ -555,6 +555,17 @@ func TestGetEnabledPublicDashboard(t *testing.T) {
 			ErrResp:  ErrPublicDashboardNotFound,
 			DashResp: nil,
 		},
+		{
+			Name:        "returns ErrPublicDashboardExpired when the public dashboard expired",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: util.Pointer(time.Now().Add(-time.Minute))},
+				d:   &dashboards.Dashboard{UID: "mydashboard"},
+				err: nil,
+			},
+			ErrResp:  ErrPublicDashboardExpired,
+			DashResp: nil,
+		},
 	}
 
 	for _, test := range testCases {
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"golang.org/x/exp/slices"
+)
+
+// allVariableValue is the value of a template variable when "All" is selected
+const allVariableValue = "$__all"
+
+// variableRegex matches the $var, [[var]], [[var:format]], ${var} and ${var:format} syntaxes of template variables
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::([^\}]+))?\}`)
+
+// templateVariable is a template variable of a dashboard with the values used to interpolate the queries
+type templateVariable struct {
+	name  string
+	multi bool
+	// all is true if "All" is selected, in which case the values are the options of the template variable
+	all bool
+	// allValue is the custom value of "All", it is used as is
+	allValue string
+	values   []string
+	// defaults are the values of the template variable stored in the dashboard
+	defaults []string
+}
+
+// getDashboardTemplateVariables returns the template variables of the dashboard that can be interpolated in queries.
+// Ad hoc filters and datasource variables are not supported.
+func getDashboardTemplateVariables(dashboard *simplejson.Json) []*simplejson.Json {
+	var variables []*simplejson.Json
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if variable.Get("name").MustString() == "" {
+			continue
+		}
+
+		switch variable.Get("type").MustString() {
+		case "adhoc", "datasource":
+			continue
+		}
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+// getTemplateVariables returns the template variables of the dashboard by name with their default values
+func getTemplateVariables(dashboard *simplejson.Json) map[string]*templateVariable {
+	variables := make(map[string]*templateVariable)
+	for _, variable := range getDashboardTemplateVariables(dashboard) {
+		defaults := getVariableValues(variable.Get("current").Get("value"))
+		tv := &templateVariable{
+			name:     variable.Get("name").MustString(),
+			multi:    variable.Get("multi").MustBool(),
+			values:   defaults,
+			defaults: defaults,
+		}
+
+		if slices.Contains(tv.values, allVariableValue) {
+			tv.all = true
+			tv.allValue = variable.Get("allValue").MustString()
+			tv.values = getVariableOptions(variable)
+		}
+
+		// constants and text boxes keep their value in the query
+		if len(tv.values) == 0 {
+			if query := variable.Get("query").MustString(); query != "" {
+				tv.values = []string{query}
+			}
+		}
+
+		variables[tv.name] = tv
+	}
+	return variables
+}
+
+// getVariableValues returns the value of a template variable, a string or an array of strings, as an array
+func getVariableValues(value *simplejson.Json) []string {
+	switch v := value.Interface().(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// getVariableOptions returns the values of the options of a template variable except "All"
+func getVariableOptions(variable *simplejson.Json) []string {
+	var options []string
+	for _, optionObj := range variable.Get("options").MustArray() {
+		option := simplejson.NewFromAny(optionObj)
+		if value := option.Get("value").MustString(); value != "" && value != allVariableValue {
+			options = append(options, value)
+		}
+	}
+
+	// the options of custom variables are not always stored in the dashboard
+	if len(options) == 0 && variable.Get("type").MustString() == "custom" {
+		for _, option := range strings.Split(variable.Get("query").MustString(), ",") {
+			// options can be written as "text : value"
+			if _, value, ok := strings.Cut(option, " : "); ok {
+				option = value
+			}
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+	}
+	return options
+}
+
+// resolveTemplateVariables returns the template variables of the dashboard with the values picked by the viewer. Viewers
+// can only change the template variables allowed by the public dashboard, to their allowed values or their default value.
+func resolveTemplateVariables(dashboard *simplejson.Json, allowed models.TemplateVariables, picked map[string][]string) (map[string]*templateVariable, error) {
+	variables := getTemplateVariables(dashboard)
+
+	for name, values := range picked {
+		tv, ok := variables[name]
+		allowedVariable := allowed.Find(name)
+		if !ok || allowedVariable == nil {
+			return nil, models.ErrInvalidTemplateVariable.Errorf("resolveTemplateVariables: template variable %s cannot be changed", name)
+		}
+
+		// the default values, including "All", are resolved already
+		if slices.Equal(values, tv.defaults) {
+			continue
+		}
+
+		if len(values) == 0 || (!tv.multi && len(values) > 1) {
+			return nil, models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: invalid number of values for template variable %s", name)
+		}
+
+		for _, value := range values {
+			isDefault := value != allVariableValue && slices.Contains(tv.defaults, value)
+			if !isDefault && !slices.Contains(allowedVariable.AllowedValues, value) {
+				return nil, models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: value %q is not allowed for template variable %s", value, name)
+			}
+		}
+
+		tv.values = values
+		tv.all = false
+		tv.allValue = ""
+	}
+
+	return variables, nil
+}
+
+// validateTemplateVariables validates that the template variables viewers can change exist in the dashboard
+func validateTemplateVariables(dashboard *simplejson.Json, templateVariables models.TemplateVariables) error {
+	names := make(map[string]bool)
+	for _, variable := range getDashboardTemplateVariables(dashboard) {
+		names[variable.Get("name").MustString()] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, tv := range templateVariables {
+		if !names[tv.Name] {
+			return models.ErrInvalidTemplateVariable.Errorf("validateTemplateVariables: template variable %s not found in dashboard", tv.Name)
+		}
+		if seen[tv.Name] {
+			return models.ErrInvalidTemplateVariable.Errorf("validateTemplateVariables: template variable %s is duplicated", tv.Name)
+		}
+		seen[tv.Name] = true
+
+		if len(tv.AllowedValues) == 0 {
+			return models.ErrInvalidTemplateVariable.Errorf("validateTemplateVariables: template variable %s has no allowed values", tv.Name)
+		}
+		for _, value := range tv.AllowedValues {
+			if value == "" || value == allVariableValue {
+				return models.ErrInvalidTemplateVariable.Errorf("validateTemplateVariables: invalid allowed value %q for template variable %s", value, tv.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// interpolateQuery replaces the template variables in all the fields of the query except its datasource and refId
+func interpolateQuery(query *simplejson.Json, variables map[string]*templateVariable) error {
+	datasourceType := query.Get("datasource").Get("type").MustString()
+	for key, value := range query.MustMap() {
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+
+		interpolated, err := interpolateValue(value, variables, datasourceType)
+		if err != nil {
+			return err
+		}
+		query.Set(key, interpolated)
+	}
+	return nil
+}
+
+func interpolateValue(value any, variables map[string]*templateVariable, datasourceType string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, variables, datasourceType)
+	case map[string]any:
+		for key, item := range v {
+			interpolated, err := interpolateValue(item, variables, datasourceType)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = interpolated
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			interpolated, err := interpolateValue(item, variables, datasourceType)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = interpolated
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// interpolateString replaces the template variables in the string, unknown variables such as the global ones are kept
+func interpolateString(s string, variables map[string]*templateVariable, datasourceType string) (string, error) {
+	var err error
+	interpolated := variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[5]
+
+		tv, ok := variables[name]
+		if !ok || err != nil {
+			return match
+		}
+
+		formatted, formatErr := tv.format(format, datasourceType)
+		if formatErr != nil {
+			err = formatErr
+			return match
+		}
+		return formatted
+	})
+	if err != nil {
+		return "", err
+	}
+	return interpolated, nil
+}
+
+// format returns the values of the template variable in the given format. Without a format, the values are formatted
+// for the datasource of the query like in the frontend: quoted for SQL datasources, as a regex for Prometheus and Loki,
+// and as a glob otherwise.
+func (tv *templateVariable) format(format string, datasourceType string) (string, error) {
+	if tv.all && tv.allValue != "" {
+		return tv.allValue, nil
+	}
+
+	if len(tv.values) == 0 {
+		return "", models.ErrPublicDashboardHasTemplateVariables.Errorf("format: template variable %s has no value", tv.name)
+	}
+
+	if format == "" {
+		switch datasourceType {
+		case datasources.DS_MYSQL, datasources.DS_POSTGRES, datasources.DS_MSSQL:
+			if !tv.multi && !tv.all {
+				return strings.ReplaceAll(strings.Join(tv.values, ","), "'", "''"), nil
+			}
+			return quoteValues(tv.values, "'", "''"), nil
+		case datasources.DS_PROMETHEUS, datasources.DS_LOKI:
+			if !tv.multi && !tv.all {
+				return promRegularEscaper.Replace(strings.Join(tv.values, ",")), nil
+			}
+			escaped := make([]string, 0, len(tv.values))
+			for _, value := range tv.values {
+				escaped = append(escaped, promRegexEscape(value))
+			}
+			if len(escaped) == 1 {
+				return escaped[0], nil
+			}
+			return "(" + strings.Join(escaped, "|") + ")", nil
+		}
+	}
+
+	switch format {
+	case "", "glob":
+		if len(tv.values) == 1 {
+			return tv.values[0], nil
+		}
+		return "{" + strings.Join(tv.values, ",") + "}", nil
+	case "raw", "text", "csv":
+		return strings.Join(tv.values, ","), nil
+	case "pipe":
+		return strings.Join(tv.values, "|"), nil
+	case "regex":
+		escaped := make([]string, 0, len(tv.values))
+		for _, value := range tv.values {
+			escaped = append(escaped, regexp.QuoteMeta(value))
+		}
+		if len(escaped) == 1 {
+			return escaped[0], nil
+		}
+		return "(" + strings.Join(escaped, "|") + ")", nil
+	case "json":
+		var value any = tv.values
+		if !tv.multi && len(tv.values) == 1 {
+			value = tv.values[0]
+		}
+		formatted, err := json.Marshal(value)
+		if err != nil {
+			return "", models.ErrInternalServerError.Errorf("format: failed to format template variable %s: %w", tv.name, err)
+		}
+		return string(formatted), nil
+	case "singlequote":
+		return quoteValues(tv.values, "'", `\'`), nil
+	case "doublequote":
+		return quoteValues(tv.values, `"`, `\"`), nil
+	case "sqlstring":
+		return quoteValues(tv.values, "'", "''"), nil
+	default:
+		return "", models.ErrPublicDashboardHasTemplateVariables.Errorf("format: unsupported format %s for template variable %s", format, tv.name)
+	}
+}
+
+// promRegularEscaper escapes single values in PromQL and LogQL string literals
+var promRegularEscaper = strings.NewReplacer(`\`, `\\`, "'", `\\'`)
+
+// promRegexSpecialChars are the characters escaped in regex matchers of PromQL and LogQL
+var promRegexSpecialChars = regexp.MustCompile(`[$^*{}\[\]'+?.()|]`)
+
+// promRegexEscape escapes a value for a regex matcher in PromQL and LogQL string literals
+func promRegexEscape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\\\`)
+	return promRegexSpecialChars.ReplaceAllString(value, `\\${0}`)
+}
+
+// quoteValues quotes each value, escaping the quote inside of it, and joins them with commas
+func quoteValues(values []string, quote string, escapedQuote string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, quote+strings.ReplaceAll(value, quote, escapedQuote)+quote)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// sanitizeTemplateVariables turns the template variables of the dashboard into custom variables holding the values viewers
+// can pick, so that their queries are not exposed. Template variables viewers cannot change are hidden.
+func sanitizeTemplateVariables(data *simplejson.Json, allowed models.TemplateVariables) {
+	for _, variable := range getDashboardTemplateVariables(data) {
+		current := getVariableValues(variable.Get("current").Get("value"))
+		values := make([]string, 0, len(current))
+		for _, value := range current {
+			if value != allVariableValue {
+				values = append(values, value)
+			}
+		}
+
+		if allowedVariable := allowed.Find(variable.Get("name").MustString()); allowedVariable != nil {
+			for _, value := range allowedVariable.AllowedValues {
+				if !slices.Contains(values, value) {
+					values = append(values, value)
+				}
+			}
+		} else {
+			// hide the template variable entirely
+			variable.Set("hide", 2)
+		}
+
+		options := make([]any, 0, len(values))
+		escaped := make([]string, 0, len(values))
+		for _, value := range values {
+			options = append(options, map[string]any{"text": value, "value": value, "selected": slices.Contains(current, value)})
+			escaped = append(escaped, strings.ReplaceAll(value, ",", `\,`))
+		}
+
+		variable.Set("type", "custom")
+		variable.Set("query", strings.Join(escaped, ","))
+		variable.Set("options", options)
+		variable.Del("definition")
+		variable.Del("datasource")
+		variable.Del("regex")
+	}
+}
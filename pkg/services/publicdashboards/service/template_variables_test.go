@@ -0,0 +1,239 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const dashboardWithTemplateVariables = `{
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "dev,staging,prod",
+        "current": { "text": "dev", "value": "dev" }
+      },
+      {
+        "name": "job",
+        "type": "query",
+        "multi": true,
+        "definition": "label_values(up, job)",
+        "query": "label_values(up, job)",
+        "current": { "text": ["api", "db"], "value": ["api", "db"] },
+        "options": [
+          { "text": "api", "value": "api" },
+          { "text": "db", "value": "db" },
+          { "text": "web.1", "value": "web.1" }
+        ]
+      },
+      {
+        "name": "instance",
+        "type": "custom",
+        "includeAll": true,
+        "query": "a,b",
+        "current": { "text": "All", "value": "$__all" }
+      },
+      {
+        "name": "region",
+        "type": "custom",
+        "includeAll": true,
+        "allValue": ".*",
+        "query": "eu,us",
+        "current": { "text": "All", "value": ["$__all"] }
+      },
+      {
+        "name": "threshold",
+        "type": "constant",
+        "query": "10"
+      },
+      {
+        "name": "filters",
+        "type": "adhoc"
+      }
+    ]
+  }
+}`
+
+func TestInterpolateQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    map[string]any
+		picked   map[string][]string
+		expected map[string]any
+	}{
+		{
+			name:     "replaces the syntaxes of template variables with their default values",
+			query:    map[string]any{"expr": `up{env="$env", env2="${env}", env3="[[env]]"} > $threshold`},
+			expected: map[string]any{"expr": `up{env="dev", env2="dev", env3="dev"} > 10`},
+		},
+		{
+			name:     "formats multiple values",
+			query:    map[string]any{"expr": `up{job=~"${job:regex}"}`, "target": "$job", "csv": "${job:csv}", "pipe": "${job:pipe}", "sql": "IN (${job:sqlstring})", "json": "${job:json}"},
+			expected: map[string]any{"expr": `up{job=~"(api|db)"}`, "target": "{api,db}", "csv": "api,db", "pipe": "api|db", "sql": "IN ('api','db')", "json": `["api","db"]`},
+		},
+		{
+			name:     "formats multiple values as quoted strings for SQL datasources by default",
+			query:    map[string]any{"datasource": map[string]any{"type": "mysql"}, "rawSql": "WHERE job IN ($job) AND env = '$env'"},
+			expected: map[string]any{"datasource": map[string]any{"type": "mysql"}, "rawSql": "WHERE job IN ('api','db') AND env = 'dev'"},
+		},
+		{
+			name:     "formats multiple values as a regex for Prometheus and Loki by default",
+			query:    map[string]any{"datasource": map[string]any{"type": "loki"}, "expr": `{job=~"$job", env="$env"}`},
+			expected: map[string]any{"datasource": map[string]any{"type": "loki"}, "expr": `{job=~"(api|db)", env="dev"}`},
+		},
+		{
+			name:     "escapes picked values for Prometheus by default",
+			query:    map[string]any{"datasource": map[string]any{"type": "prometheus"}, "expr": `up{job=~"$job"}`},
+			picked:   map[string][]string{"job": {"web.1"}},
+			expected: map[string]any{"datasource": map[string]any{"type": "prometheus"}, "expr": `up{job=~"web\\.1"}`},
+		},
+		{
+			name:     "resolves all to the options of the template variable or its custom all value",
+			query:    map[string]any{"expr": `up{instance=~"${instance:regex}", region=~"$region"}`},
+			expected: map[string]any{"expr": `up{instance=~"(a|b)", region=~".*"}`},
+		},
+		{
+			name:     "replaces template variables in nested fields and keeps unknown variables",
+			query:    map[string]any{"refId": "$env", "datasource": map[string]any{"uid": "$env"}, "nested": map[string]any{"list": []any{"$env", "$__interval", "$filters", 1.0}}},
+			expected: map[string]any{"refId": "$env", "datasource": map[string]any{"uid": "$env"}, "nested": map[string]any{"list": []any{"dev", "$__interval", "$filters", 1.0}}},
+		},
+		{
+			name:     "uses the values picked by the viewer",
+			query:    map[string]any{"expr": `up{env="$env", job=~"${job:regex}"}`},
+			picked:   map[string][]string{"env": {"prod"}, "job": {"web.1"}},
+			expected: map[string]any{"expr": `up{env="prod", job=~"web\.1"}`},
+		},
+		{
+			name:     "escapes quotes of picked values",
+			query:    map[string]any{"rawSql": "WHERE job IN (${job:sqlstring})"},
+			picked:   map[string][]string{"job": {"o'brien"}},
+			expected: map[string]any{"rawSql": "WHERE job IN ('o''brien')"},
+		},
+	}
+
+	allowed := TemplateVariables{
+		{Name: "env", AllowedValues: []string{"staging", "prod"}},
+		{Name: "job", AllowedValues: []string{"web.1", "o'brien"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+			require.NoError(t, err)
+
+			variables, err := resolveTemplateVariables(dashboard, allowed, tc.picked)
+			require.NoError(t, err)
+
+			query := simplejson.NewFromAny(tc.query)
+			require.NoError(t, interpolateQuery(query, variables))
+			assert.Equal(t, tc.expected, query.MustMap())
+		})
+	}
+
+	t.Run("fails on an unsupported format", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+		require.NoError(t, err)
+
+		variables, err := resolveTemplateVariables(dashboard, nil, nil)
+		require.NoError(t, err)
+
+		err = interpolateQuery(simplejson.NewFromAny(map[string]any{"expr": "${env:unknown}"}), variables)
+		require.ErrorIs(t, err, ErrPublicDashboardHasTemplateVariables)
+	})
+}
+
+func TestResolveTemplateVariables(t *testing.T) {
+	allowed := TemplateVariables{
+		{Name: "env", AllowedValues: []string{"staging"}},
+		{Name: "instance", AllowedValues: []string{"a"}},
+	}
+
+	testCases := []struct {
+		name        string
+		picked      map[string][]string
+		expectedErr error
+	}{
+		{name: "accepts allowed values", picked: map[string][]string{"env": {"staging"}}},
+		{name: "accepts default values", picked: map[string][]string{"env": {"dev"}, "instance": {"$__all"}}},
+		{name: "rejects values that are not allowed", picked: map[string][]string{"env": {"prod"}}, expectedErr: ErrInvalidTemplateVariableValue},
+		{name: "rejects all if it is not the default value", picked: map[string][]string{"instance": {"a", "$__all"}}, expectedErr: ErrInvalidTemplateVariableValue},
+		{name: "rejects multiple values of single value variables", picked: map[string][]string{"env": {"dev", "staging"}}, expectedErr: ErrInvalidTemplateVariableValue},
+		{name: "rejects no value", picked: map[string][]string{"env": {}}, expectedErr: ErrInvalidTemplateVariableValue},
+		{name: "rejects variables viewers cannot change", picked: map[string][]string{"job": {"api"}}, expectedErr: ErrInvalidTemplateVariable},
+		{name: "rejects unknown variables", picked: map[string][]string{"unknown": {"value"}}, expectedErr: ErrInvalidTemplateVariable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+			require.NoError(t, err)
+
+			_, err = resolveTemplateVariables(dashboard, allowed, tc.picked)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name              string
+		templateVariables TemplateVariables
+		expectedErr       error
+	}{
+		{name: "no template variables", templateVariables: TemplateVariables{}},
+		{name: "valid template variables", templateVariables: TemplateVariables{{Name: "env", AllowedValues: []string{"prod"}}, {Name: "job", AllowedValues: []string{"web"}}}},
+		{name: "unknown template variable", templateVariables: TemplateVariables{{Name: "unknown", AllowedValues: []string{"prod"}}}, expectedErr: ErrInvalidTemplateVariable},
+		{name: "unsupported template variable", templateVariables: TemplateVariables{{Name: "filters", AllowedValues: []string{"prod"}}}, expectedErr: ErrInvalidTemplateVariable},
+		{name: "duplicated template variable", templateVariables: TemplateVariables{{Name: "env", AllowedValues: []string{"prod"}}, {Name: "env", AllowedValues: []string{"dev"}}}, expectedErr: ErrInvalidTemplateVariable},
+		{name: "no allowed values", templateVariables: TemplateVariables{{Name: "env"}}, expectedErr: ErrInvalidTemplateVariable},
+		{name: "all as allowed value", templateVariables: TemplateVariables{{Name: "env", AllowedValues: []string{"$__all"}}}, expectedErr: ErrInvalidTemplateVariable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTemplateVariables(dashboard, tc.templateVariables)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSanitizeTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	sanitizeTemplateVariables(dashboard, TemplateVariables{{Name: "job", AllowedValues: []string{"web", "db"}}})
+
+	list := dashboard.Get("templating").Get("list")
+
+	job := list.GetIndex(1)
+	assert.Equal(t, "custom", job.Get("type").MustString())
+	assert.Equal(t, "api,db,web", job.Get("query").MustString())
+	assert.Len(t, job.Get("options").MustArray(), 3)
+	assert.Equal(t, 0, job.Get("hide").MustInt())
+	_, hasDefinition := job.CheckGet("definition")
+	assert.False(t, hasDefinition)
+
+	env := list.GetIndex(0)
+	assert.Equal(t, 2, env.Get("hide").MustInt())
+	assert.Equal(t, "dev", env.Get("query").MustString())
+
+	filters := list.GetIndex(5)
+	assert.Equal(t, "adhoc", filters.Get("type").MustString())
+}
@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
@@ -13,6 +15,16 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	// the zero time removes the expiry
+	if expiresAt := dto.PublicDashboard.ExpiresAt; expiresAt != nil && !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return ErrInvalidExpiry.Errorf("ValidateSavePublicDashboard: expiry must be in the future")
+	}
+
+	// an empty passcode removes the protection
+	if passcode := dto.PublicDashboard.Passcode; passcode != nil && *passcode != "" && len(*passcode) < MinPasscodeLength {
+		return ErrInvalidPasscode.Errorf("ValidateSavePublicDashboard: passcode is too short")
+	}
+
 	return nil
 }
 
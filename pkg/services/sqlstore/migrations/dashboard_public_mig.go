@@ -96,4 +96,43 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add passcode column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "passcode",
+		Type:     DB_NVarchar,
+		Length:   255,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add passcode_salt column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "passcode_salt",
+		Type:     DB_NVarchar,
+		Length:   50,
+		Nullable: true,
+	}))
+
+	var dashboardPublicAccessLogV1 = Table{
+		Name: "dashboard_public_access_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "remote_addr", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "user_agent", Type: DB_Text, Nullable: true},
+			{Name: "accessed_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "public_dashboard_uid", "accessed_at"}},
+			{Cols: []string{"accessed_at"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public access log table v1", NewAddTableMigration(dashboardPublicAccessLogV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicAccessLogV1)
 }
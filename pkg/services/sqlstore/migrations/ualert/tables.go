@@ -24,9 +24,137 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("add record column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "record", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add record column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "record", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add keep_firing_for column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add keep_firing_for column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "depends_on", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "depends_on", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	addAlertStateHistoryMigrations(mg)
+
+	addNotificationHistoryMigrations(mg)
+
+	mg.AddMigration("add active_time_intervals column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "active_time_intervals", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add active_time_intervals column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "active_time_intervals", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add group_active_time_intervals column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "group_active_time_intervals", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add group_active_time_intervals column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "group_active_time_intervals", Type: migrator.DB_Text, Nullable: true,
+	}))
 	// End of migration log, add new migrations above this line.
 }
 
+func addAlertStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "rule_condition", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_fingerprint", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "reason", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true},
+			{Name: "state_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "eval_time", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "eval_time"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "labels_fingerprint", "eval_time"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "eval_time"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history on org_id, rule_uid and eval_time columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history on org_id, labels_fingerprint and eval_time columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+	mg.AddMigration("add index in alert_state_history on org_id and eval_time columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[2]))
+
+	// Labels of state history entries, to filter entries by labels without parsing the labels of every entry.
+	stateHistoryLabel := migrator.Table{
+		Name: "alert_state_history_label",
+		Columns: []*migrator.Column{
+			{Name: "history_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "value", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "eval_time", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		PrimaryKeys: []string{"history_id", "name"},
+		Indices: []*migrator.Index{
+			{Cols: []string{"name", "value", "history_id"}, Type: migrator.IndexType},
+			{Cols: []string{"eval_time"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history_label table", migrator.NewAddTableMigration(stateHistoryLabel))
+	mg.AddMigration("add index in alert_state_history_label on name, value and history_id columns", migrator.NewAddIndexMigration(stateHistoryLabel, stateHistoryLabel.Indices[0]))
+	mg.AddMigration("add index in alert_state_history_label on eval_time column", migrator.NewAddIndexMigration(stateHistoryLabel, stateHistoryLabel.Indices[1]))
+}
+
+func addNotificationHistoryMigrations(mg *migrator.Migrator) {
+	notificationHistory := migrator.Table{
+		Name: "alert_notification_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alert_fingerprints", Type: migrator.DB_Text, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "retry", Type: migrator.DB_Int, Nullable: false},
+			{Name: "sent_at", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "sent_at"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "receiver", "sent_at"}, Type: migrator.IndexType},
+			{Cols: []string{"sent_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_history table", migrator.NewAddTableMigration(notificationHistory))
+	mg.AddMigration("add index in alert_notification_history on org_id and sent_at columns", migrator.NewAddIndexMigration(notificationHistory, notificationHistory.Indices[0]))
+	mg.AddMigration("add index in alert_notification_history on org_id, receiver and sent_at columns", migrator.NewAddIndexMigration(notificationHistory, notificationHistory.Indices[1]))
+	mg.AddMigration("add index in alert_notification_history on sent_at column", migrator.NewAddIndexMigration(notificationHistory, notificationHistory.Indices[2]))
+}
+
 // historicalTableMigrations contains those migrations that existed prior to creating the improved messaging around migration immutability.
 func historicalTableMigrations(mg *migrator.Migrator) {
 	// DO NOT EDIT
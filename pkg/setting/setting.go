@@ -396,6 +396,7 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	SqlDatasourceQueryTimeoutDefault    int
 
 	// Snapshots
 	SnapshotEnabled       bool
@@ -524,6 +525,9 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	// Query and resource caching
+	Caching CachingSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -1190,6 +1194,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.Caching = readCachingSettings(iniFile)
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1965,6 +1970,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqlDatasourceQueryTimeoutDefault = sqlDatasources.Key("query_timeout_default").MustInt(0)
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
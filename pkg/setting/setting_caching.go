@@ -0,0 +1,32 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type CachingSettings struct {
+	// Enabled turns on query and resource caching in the OSS caching service.
+	Enabled bool
+	// TTL is the default time-to-live for cached query responses.
+	TTL time.Duration
+	// ResourcesTTL is the default time-to-live for cached resource responses.
+	ResourcesTTL time.Duration
+	// TimeRangeStep is the step to which query time ranges are rounded down when building cache keys.
+	TimeRangeStep time.Duration
+	// MaxValueSize is the maximum size in bytes of a single cached value. Larger responses are not cached.
+	MaxValueSize int
+}
+
+func readCachingSettings(iniFile *ini.File) CachingSettings {
+	s := CachingSettings{}
+
+	cachingSection := iniFile.Section("caching")
+	s.Enabled = cachingSection.Key("enabled").MustBool(false)
+	s.TTL = cachingSection.Key("ttl").MustDuration(time.Minute)
+	s.ResourcesTTL = cachingSection.Key("resources_ttl").MustDuration(5 * time.Minute)
+	s.TimeRangeStep = cachingSection.Key("time_range_step").MustDuration(time.Minute)
+	s.MaxValueSize = cachingSection.Key("max_value_mb").MustInt(1) * 1024 * 1024
+	return s
+}
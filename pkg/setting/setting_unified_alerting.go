@@ -15,12 +15,13 @@ import (
 )
 
 const (
-	alertmanagerDefaultClusterAddr        = "0.0.0.0:9094"
-	alertmanagerDefaultPeerTimeout        = 15 * time.Second
-	alertmanagerDefaultGossipInterval     = cluster.DefaultGossipInterval
-	alertmanagerDefaultPushPullInterval   = cluster.DefaultPushPullInterval
-	alertmanagerDefaultConfigPollInterval = time.Minute
-	alertmanagerRedisDefaultMaxConns      = 5
+	alertmanagerDefaultClusterAddr           = "0.0.0.0:9094"
+	alertmanagerDefaultPeerTimeout           = 15 * time.Second
+	alertmanagerDefaultGossipInterval        = cluster.DefaultGossipInterval
+	alertmanagerDefaultPushPullInterval      = cluster.DefaultPushPullInterval
+	alertmanagerDefaultConfigPollInterval    = time.Minute
+	remoteAlertmanagerDefaultCompareInterval = time.Minute
+	alertmanagerRedisDefaultMaxConns         = 5
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -59,8 +60,11 @@ const (
 	// with intervals that are not exactly divided by this number not to be evaluated
 	SchedulerBaseInterval = 10 * time.Second
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
-	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
-	stateHistoryDefaultEnabled    = true
+	DefaultRuleEvaluationInterval       = SchedulerBaseInterval * 6 // == 60 seconds
+	stateHistoryDefaultEnabled          = true
+	stateHistoryDefaultSQLRetention     = 30 * 24 * time.Hour
+	notificationHistoryDefaultEnabled   = false
+	notificationHistoryDefaultRetention = 30 * 24 * time.Hour
 )
 
 type UnifiedAlertingSettings struct {
@@ -80,6 +84,7 @@ type UnifiedAlertingSettings struct {
 	HARedisPassword                string
 	HARedisDB                      int
 	HARedisMaxConns                int
+	HAEvaluationSharding           bool
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -95,6 +100,8 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	NotificationHistory           NotificationHistorySettings
+	RecordingRules                RecordingRuleSettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
@@ -107,6 +114,11 @@ type RemoteAlertmanagerSettings struct {
 	URL      string
 	TenantID string
 	Password string
+	// ShadowMode keeps the internal Alertmanager authoritative and mirrors configuration, silences and alerts
+	// to the remote Alertmanager, so that both can be compared before switching over.
+	ShadowMode bool
+	// ShadowCompareInterval is how often the internal and the remote Alertmanager are compared in shadow mode.
+	ShadowCompareInterval time.Duration
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -134,6 +146,29 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLRetention is how long the "sql" backend keeps state history entries.
+	// Entries are kept forever if it is 0.
+	SQLRetention time.Duration
+}
+
+// NotificationHistorySettings contains the configuration of the history of the notifications
+// sent by the Grafana Alertmanager.
+type NotificationHistorySettings struct {
+	Enabled bool
+	// Retention is how long notification history entries are kept.
+	// Entries are kept forever if it is 0.
+	Retention time.Duration
+}
+
+// RecordingRuleSettings contains the configuration of Grafana-managed recording rules
+// and of the Prometheus remote write endpoint their results are written to.
+type RecordingRuleSettings struct {
+	Enabled           bool
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+	CustomHeaders     map[string]string
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -253,6 +288,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisPassword = ua.Key("ha_redis_password").MustString("")
 	uaCfg.HARedisDB = ua.Key("ha_redis_db").MustInt(0)
 	uaCfg.HARedisMaxConns = ua.Key("ha_redis_max_conns").MustInt(alertmanagerRedisDefaultMaxConns)
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
 	peers := ua.Key("ha_peers").MustString("")
 	uaCfg.HAPeers = make([]string, 0)
 	if peers != "" {
@@ -353,6 +389,15 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		URL:      remoteAlertmanager.Key("url").MustString(""),
 		TenantID: remoteAlertmanager.Key("tenant").MustString(""),
 		Password: remoteAlertmanager.Key("password").MustString(""),
+
+		ShadowMode: remoteAlertmanager.Key("shadow_mode").MustBool(false),
+	}
+	uaCfgRemoteAM.ShadowCompareInterval, err = gtime.ParseDuration(valueAsString(remoteAlertmanager, "shadow_compare_interval", remoteAlertmanagerDefaultCompareInterval.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgRemoteAM.ShadowCompareInterval <= 0 {
+		return fmt.Errorf("value of setting 'shadow_compare_interval' should be greater than 0")
 	}
 	uaCfg.RemoteAlertmanager = uaCfgRemoteAM
 
@@ -395,8 +440,56 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
 	}
+	uaCfgStateHistory.SQLRetention, err = gtime.ParseDuration(valueAsString(stateHistory, "sql_retention", stateHistoryDefaultSQLRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgStateHistory.SQLRetention < 0 {
+		return fmt.Errorf("setting 'sql_retention' is invalid, only non-negative durations are allowed")
+	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	notificationHistory := iniFile.Section("unified_alerting.notification_history")
+	uaCfg.NotificationHistory = NotificationHistorySettings{
+		Enabled: notificationHistoryDefaultEnabled,
+	}
+	// Keys that are missing in a child section are looked up in the parent section,
+	// so 'enabled' is read from the keys of the section only.
+	if enabled := notificationHistory.KeysHash()["enabled"]; enabled != "" {
+		uaCfg.NotificationHistory.Enabled, err = strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("failed to parse setting 'enabled' in section 'unified_alerting.notification_history': %w", err)
+		}
+	}
+	uaCfg.NotificationHistory.Retention, err = gtime.ParseDuration(valueAsString(notificationHistory, "retention", notificationHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.NotificationHistory.Retention < 0 {
+		return fmt.Errorf("setting 'retention' in section 'unified_alerting.notification_history' is invalid, only non-negative durations are allowed")
+	}
+
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	recordingRulesHeaders := iniFile.Section("unified_alerting.recording_rules.custom_headers")
+	uaCfg.RecordingRules = RecordingRuleSettings{
+		URL:               recordingRules.Key("url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+		Timeout:           recordingRules.Key("timeout").MustDuration(10 * time.Second),
+		CustomHeaders:     recordingRulesHeaders.KeysHash(),
+	}
+	// Keys that are missing in a child section are looked up in the parent section,
+	// which would enable recording rules whenever unified alerting is enabled.
+	if enabled := recordingRules.KeysHash()["enabled"]; enabled != "" {
+		uaCfg.RecordingRules.Enabled, err = strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("failed to parse setting 'enabled' in section 'unified_alerting.recording_rules': %w", err)
+		}
+	}
+	if uaCfg.RecordingRules.Enabled && uaCfg.RecordingRules.URL == "" {
+		return errors.New("setting 'url' in section 'unified_alerting.recording_rules' is required when recording rules are enabled")
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	cfg.UnifiedAlerting = uaCfg
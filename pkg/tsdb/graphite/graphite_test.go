@@ -289,10 +289,12 @@ func TestConvertResponses(t *testing.T) {
 	})
 }
 
-type fakeInstanceManager struct{}
+type fakeInstanceManager struct {
+	dsInfo datasourceInfo
+}
 
 func (f fakeInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
-	return datasourceInfo{}, nil
+	return f.dsInfo, nil
 }
 
 func (f fakeInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
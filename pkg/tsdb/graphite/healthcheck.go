@@ -0,0 +1,69 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	healthCheckRefID = "__healthcheck__"
+)
+
+// CheckHealth renders a constant line over the last hour, which succeeds as soon as Graphite answers render requests
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	if _, err := s.getDSInfo(ctx, req.PluginContext); err != nil {
+		return getHealthCheckMessage(fmt.Errorf("failed to get data source information: %w", err)), nil
+	}
+
+	model, _ := json.Marshal(map[string]string{TargetModelField: "constantLine(100)"})
+	now := time.Now()
+	query := backend.DataQuery{
+		RefID: healthCheckRefID,
+		TimeRange: backend.TimeRange{
+			From: now.Add(-time.Hour),
+			To:   now,
+		},
+		JSON: model,
+	}
+
+	resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: req.PluginContext,
+		Queries:       []backend.DataQuery{query},
+	})
+	if err != nil {
+		logger.Warn("Graphite health check failed", "error", err)
+		return getHealthCheckMessage(fmt.Errorf("error received while querying Graphite: %w", err)), nil
+	}
+
+	if resp.Responses[healthCheckRefID].Error != nil {
+		logger.Warn("Graphite health check failed", "error", resp.Responses[healthCheckRefID].Error)
+		return getHealthCheckMessage(fmt.Errorf("error from Graphite: %w", resp.Responses[healthCheckRefID].Error)), nil
+	}
+
+	if len(resp.Responses[healthCheckRefID].Frames) == 0 {
+		return getHealthCheckMessage(errors.New("no series returned by Graphite for the probe query constantLine(100)")), nil
+	}
+
+	return getHealthCheckMessage(nil), nil
+}
+
+func getHealthCheckMessage(err error) *backend.CheckHealthResult {
+	if err == nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusOk,
+			Message: "Data source successfully connected.",
+		}
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,71 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	testCases := []struct {
+		name            string
+		status          int
+		body            string
+		expectedStatus  backend.HealthStatus
+		expectedMessage string
+	}{
+		{
+			name:            "Graphite renders the probe query",
+			status:          http.StatusOK,
+			body:            `[{"target": "constantLine(100) __healthcheck__", "datapoints": [[100, 1], [100, 2]]}]`,
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Data source successfully connected.",
+		},
+		{
+			name:            "Graphite rejects the request",
+			status:          http.StatusUnauthorized,
+			body:            `unauthorized`,
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "error received while querying Graphite: request failed, status: 401 Unauthorized",
+		},
+		{
+			name:            "Graphite renders no series",
+			status:          http.StatusOK,
+			body:            `[]`,
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "no series returned by Graphite",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/render", r.URL.Path)
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, `aliasSub(constantLine(100),"(^.*$)","\1 __healthcheck__")`, r.PostForm.Get("target"))
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			t.Cleanup(server.Close)
+
+			res, err := newTestService(server).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+			assert.Contains(t, res.Message, tc.expectedMessage)
+		})
+	}
+}
+
+func newTestService(server *httptest.Server) *Service {
+	return &Service{
+		im:     fakeInstanceManager{dsInfo: datasourceInfo{HTTPClient: server.Client(), URL: server.URL}},
+		tracer: tracing.InitializeTracerForTest(),
+	}
+}
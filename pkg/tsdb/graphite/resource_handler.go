@@ -0,0 +1,117 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// resourcePaths are the Graphite API endpoints served as resources: metric find and expand, tag keys and values, and
+// the tag suggestions of the query editor
+var resourcePaths = regexp.MustCompile(`^(metrics/find|metrics/expand|tags|tags/autoComplete/tags|tags/autoComplete/values|tags/[^/]+)$`)
+
+// CallResource proxies the metric and tag lookups of the query editor to Graphite
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath, err := url.Parse(req.URL)
+	if err != nil {
+		logger.Error("Failed to parse resource URL", "error", err, "url", req.URL)
+		return err
+	}
+
+	if !resourcePaths.MatchString(req.Path) {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusNotFound})
+	}
+
+	// metric find queries are posted as forms, see https://graphite.readthedocs.io/en/latest/metrics_api.html
+	if req.Method != http.MethodGet && !(req.Method == http.MethodPost && req.Path == "metrics/find") {
+		logger.Error("Invalid HTTP method", "method", req.Method, "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusMethodNotAllowed})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	graphiteURL, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		logger.Error("Failed to parse data source URL", "error", err, "url", dsInfo.URL)
+		return err
+	}
+	graphiteURL.Path = path.Join(graphiteURL.Path, req.Path)
+	graphiteURL.RawQuery = resourcePath.RawQuery
+
+	ctx, span := s.tracer.Start(ctx, "datasource.graphite.CallResource", trace.WithAttributes(
+		attribute.String("path", req.Path),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", req.PluginContext.OrgID),
+	))
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, req.Method, graphiteURL.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		logger.Error("Failed to create request", "error", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType := req.GetHTTPHeaders().Get("Content-Type"); contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	s.tracer.Inject(ctx, request.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Failed resource call from graphite", "error", err, "path", req.Path)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+
+	return sendResourceResponse(logger, req, res, sender)
+}
+
+// sendResourceResponse sends the response of Graphite, cached by the browser when the frontend asks for it
+func sendResourceResponse(logger log.Logger, req *backend.CallResourceRequest, res *http.Response, sender backend.CallResourceResponseSender) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read response body", "error", err)
+		return err
+	}
+
+	headers := map[string][]string{
+		"content-type": {res.Header.Get("Content-Type")},
+	}
+
+	// frontend sets the X-Grafana-Cache with the desired response cache control value
+	if cacheControl := req.GetHTTPHeaders().Get("X-Grafana-Cache"); cacheControl != "" && res.StatusCode/100 == 2 {
+		headers["X-Grafana-Cache"] = []string{"y"}
+		headers["Cache-Control"] = []string{cacheControl}
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
@@ -0,0 +1,101 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var requested *http.Request
+	var requestedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requested, requestedBody = r, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["host"]`))
+	}))
+	t.Cleanup(server.Close)
+	service := newTestService(server)
+
+	callResource := func(t *testing.T, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+
+		requested = nil
+		req.Path, _, _ = strings.Cut(req.URL, "?")
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), req, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("proxies tag lookups to the Graphite API", func(t *testing.T) {
+		res := callResource(t, &backend.CallResourceRequest{Method: http.MethodGet, URL: "tags/autoComplete/values?tag=host&expr=app%3Dweb"})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.JSONEq(t, `["host"]`, string(res.Body))
+		require.NotNil(t, requested)
+		assert.Equal(t, "/tags/autoComplete/values", requested.URL.Path)
+		assert.Equal(t, "app=web", requested.URL.Query().Get("expr"))
+	})
+
+	t.Run("posts metric find queries", func(t *testing.T) {
+		res := callResource(t, &backend.CallResourceRequest{
+			Method:  http.MethodPost,
+			URL:     "metrics/find?from=-1h&until=now",
+			Headers: map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+			Body:    []byte("query=app.*"),
+		})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		require.NotNil(t, requested)
+		assert.Equal(t, http.MethodPost, requested.Method)
+		assert.Equal(t, "/metrics/find", requested.URL.Path)
+		assert.Equal(t, "-1h", requested.URL.Query().Get("from"))
+		assert.Equal(t, "application/x-www-form-urlencoded", requested.Header.Get("Content-Type"))
+		assert.Equal(t, "query=app.*", requestedBody)
+	})
+
+	t.Run("sets the cache control asked by the frontend", func(t *testing.T) {
+		res := callResource(t, &backend.CallResourceRequest{
+			Method:  http.MethodGet,
+			URL:     "tags",
+			Headers: map[string][]string{"X-Grafana-Cache": {"private, max-age=60"}},
+		})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, []string{"private, max-age=60"}, res.Headers["Cache-Control"])
+	})
+
+	t.Run("rejects other paths", func(t *testing.T) {
+		res := callResource(t, &backend.CallResourceRequest{Method: http.MethodGet, URL: "render?target=app.*"})
+
+		assert.Equal(t, http.StatusNotFound, res.Status)
+		assert.Nil(t, requested)
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		res := callResource(t, &backend.CallResourceRequest{Method: http.MethodPost, URL: "tags/autoComplete/tags"})
+
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Status)
+		assert.Nil(t, requested)
+	})
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
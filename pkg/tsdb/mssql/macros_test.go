@@ -10,6 +10,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
 )
 
 func TestMacroEngine(t *testing.T) {
@@ -256,3 +258,18 @@ func TestMacroEngineConcurrency(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestMacroEngineParameters(t *testing.T) {
+	engine := &msSQLMacroEngine{}
+	query := &backend.DataQuery{JSON: []byte("{}")}
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	params := sqleng.NewQueryParameters(engine.Placeholder)
+	sql, err := engine.InterpolateParameters(query, timeRange, "SELECT $__timeFrom() WHERE $__timeFilter(time_column) AND epoch <= $__unixEpochNanoTo()", params)
+	require.NoError(t, err)
+
+	require.Equal(t, "SELECT @p1 WHERE time_column BETWEEN @p2 AND @p3 AND epoch <= @p4", params.Placeholders(sql))
+	require.Equal(t, []any{from, from, to, to.UnixNano()}, params.Args())
+}
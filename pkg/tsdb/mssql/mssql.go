@@ -69,6 +69,7 @@ func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
 			MaxOpenConns:      cfg.SqlDatasourceMaxOpenConnsDefault,
 			MaxIdleConns:      cfg.SqlDatasourceMaxIdleConnsDefault,
 			ConnMaxLifetime:   cfg.SqlDatasourceMaxConnLifetimeDefault,
+			QueryTimeout:      cfg.SqlDatasourceQueryTimeoutDefault,
 			Encrypt:           "false",
 			ConnectionTimeout: 0,
 			SecureDSProxy:     false,
@@ -129,6 +130,9 @@ func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
 			DSInfo:            dsInfo,
 			MetricColumnTypes: []string{"VARCHAR", "CHAR", "NVARCHAR", "NCHAR"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			SchemaDialect:     mssqlSchemaDialect{},
+			// SQL Server has no setting limiting the execution time of statements, queries are cancelled by the client
+			// once the timeout is reached and the driver stops them on the server
 		}
 
 		queryResultTransformer := mssqlQueryResultTransformer{
@@ -270,6 +274,15 @@ func (t *mssqlQueryResultTransformer) TransformQueryError(logger log.Logger, err
 	return err
 }
 
+// CallResource serves the schema of the connected SQL database
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return dsHandler.CallResource(ctx, req, sender)
+}
+
 // CheckHealth pings the connected SQL database
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
@@ -0,0 +1,37 @@
+package mssql
+
+// mssqlSchemaDialect discovers the schema of the SQL Server database the data source connects to. Tables are
+// discovered in the requested schema or the default schema of the user.
+type mssqlSchemaDialect struct{}
+
+func (mssqlSchemaDialect) DatabasesQuery() (string, []any) {
+	return "SELECT DB_NAME()", nil
+}
+
+func (mssqlSchemaDialect) SchemasQuery(string) (string, []any) {
+	return `SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA
+		WHERE SCHEMA_NAME NOT IN ('sys', 'INFORMATION_SCHEMA', 'guest') AND SCHEMA_NAME NOT LIKE 'db[_]%'
+		ORDER BY SCHEMA_NAME`, nil
+}
+
+func (mssqlSchemaDialect) TablesQuery(_ string, schema string) (string, []any) {
+	return `SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME())
+		ORDER BY TABLE_NAME`, []any{schema}
+}
+
+func (mssqlSchemaDialect) ColumnsQuery(_ string, schema string, table string) (string, []any) {
+	return `SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND TABLE_NAME = @p2
+		ORDER BY ORDINAL_POSITION`, []any{schema, table}
+}
+
+func (mssqlSchemaDialect) IndexesQuery(_ string, schema string, table string) (string, []any) {
+	return `SELECT i.name, i.is_unique, c.name
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(QUOTENAME(COALESCE(NULLIF(@p1, ''), SCHEMA_NAME())) + '.' + QUOTENAME(@p2))
+			AND i.name IS NOT NULL AND ic.key_ordinal > 0
+		ORDER BY i.name, ic.key_ordinal`, []any{schema, table}
+}
@@ -32,6 +32,22 @@ func newMysqlMacroEngine(logger log.Logger, cfg *setting.Cfg) sqleng.SQLMacroEng
 }
 
 func (m *mySQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	return m.interpolate(query, timeRange, sql, nil)
+}
+
+// InterpolateParameters interpolates the macros of the query binding their values as parameters
+func (m *mySQLMacroEngine) InterpolateParameters(query *backend.DataQuery, timeRange backend.TimeRange, sql string,
+	params *sqleng.QueryParameters) (string, error) {
+	return m.interpolate(query, timeRange, sql, params)
+}
+
+// Placeholder returns the placeholder of parameters, MySQL binds them by position
+func (m *mySQLMacroEngine) Placeholder(int) string {
+	return "?"
+}
+
+func (m *mySQLMacroEngine) interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string,
+	params *sqleng.QueryParameters) (string, error) {
 	matches := restrictedRegExp.FindAllStringSubmatch(sql, 1)
 	if len(matches) > 0 {
 		m.logger.Error("Show grants, session_user(), current_user(), system_user() or user() not allowed in query")
@@ -47,7 +63,7 @@ func (m *mySQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backe
 		for i, arg := range args {
 			args[i] = strings.Trim(arg, " ")
 		}
-		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args, params)
 		if err != nil && macroError == nil {
 			macroError = err
 			return "macro_error()"
@@ -62,7 +78,7 @@ func (m *mySQLMacroEngine) Interpolate(query *backend.DataQuery, timeRange backe
 	return sql, nil
 }
 
-func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string, params *sqleng.QueryParameters) (string, error) {
 	switch name {
 	case "__timeEpoch", "__time":
 		if len(args) == 0 {
@@ -73,14 +89,17 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
-		if timeRange.From.UTC().Unix() < 0 {
-			return fmt.Sprintf("%s BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL %d SECOND) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+		from, to := timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()
+		if from < 0 {
+			return fmt.Sprintf("%s BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL %s SECOND) AND FROM_UNIXTIME(%s)", args[0], params.Value(from, fmt.Sprint(from)), params.Value(to, fmt.Sprint(to))), nil
 		}
-		return fmt.Sprintf("%s BETWEEN FROM_UNIXTIME(%d) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+		return fmt.Sprintf("%s BETWEEN FROM_UNIXTIME(%s) AND FROM_UNIXTIME(%s)", args[0], params.Value(from, fmt.Sprint(from)), params.Value(to, fmt.Sprint(to))), nil
 	case "__timeFrom":
-		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.From.UTC().Unix()), nil
+		from := timeRange.From.UTC().Unix()
+		return fmt.Sprintf("FROM_UNIXTIME(%s)", params.Value(from, fmt.Sprint(from))), nil
 	case "__timeTo":
-		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.To.UTC().Unix()), nil
+		to := timeRange.To.UTC().Unix()
+		return fmt.Sprintf("FROM_UNIXTIME(%s)", params.Value(to, fmt.Sprint(to))), nil
 	case "__timeGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
@@ -97,7 +116,7 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 		return fmt.Sprintf("UNIX_TIMESTAMP(%s) DIV %.0f * %.0f", args[0], interval.Seconds(), interval.Seconds()), nil
 	case "__timeGroupAlias":
-		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args, params)
 		if err == nil {
 			return tg + " AS \"time\"", nil
 		}
@@ -106,16 +125,20 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
-		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+		from, to := timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()
+		return fmt.Sprintf("%s >= %s AND %s <= %s", args[0], params.Value(from, fmt.Sprint(from)), args[0], params.Value(to, fmt.Sprint(to))), nil
 	case "__unixEpochNanoFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
-		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+		from, to := timeRange.From.UTC().UnixNano(), timeRange.To.UTC().UnixNano()
+		return fmt.Sprintf("%s >= %s AND %s <= %s", args[0], params.Value(from, fmt.Sprint(from)), args[0], params.Value(to, fmt.Sprint(to))), nil
 	case "__unixEpochNanoFrom":
-		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+		from := timeRange.From.UTC().UnixNano()
+		return params.Value(from, fmt.Sprint(from)), nil
 	case "__unixEpochNanoTo":
-		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+		to := timeRange.To.UTC().UnixNano()
+		return params.Value(to, fmt.Sprint(to)), nil
 	case "__unixEpochGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
@@ -132,7 +155,7 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 		return fmt.Sprintf("%s DIV %v * %v", args[0], interval.Seconds(), interval.Seconds()), nil
 	case "__unixEpochGroupAlias":
-		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
+		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args, params)
 		if err == nil {
 			return tg + " AS \"time\"", nil
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
 
 	"github.com/stretchr/testify/require"
 )
@@ -223,3 +224,38 @@ func TestMacroEngineConcurrency(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestMacroEngineParameters(t *testing.T) {
+	engine := &mySQLMacroEngine{
+		logger:    log.New("test"),
+		userError: "inspect Grafana server log for details",
+	}
+	query := &backend.DataQuery{}
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("binds the time range as parameters", func(t *testing.T) {
+		params := sqleng.NewQueryParameters(engine.Placeholder)
+		sql, err := engine.InterpolateParameters(query, timeRange, "SELECT $__timeFrom() WHERE $__timeFilter(time_column) AND $__unixEpochFilter(epoch)", params)
+		require.NoError(t, err)
+
+		require.Equal(t, "SELECT FROM_UNIXTIME(?) WHERE time_column BETWEEN FROM_UNIXTIME(?) AND FROM_UNIXTIME(?) AND epoch >= ? AND epoch <= ?", params.Placeholders(sql))
+		require.Equal(t, []any{from.Unix(), from.Unix(), to.Unix(), from.Unix(), to.Unix()}, params.Args())
+	})
+
+	t.Run("binds negative time ranges as parameters", func(t *testing.T) {
+		from := time.Date(1960, 2, 1, 7, 0, 0, 0, time.UTC)
+		params := sqleng.NewQueryParameters(engine.Placeholder)
+		sql, err := engine.InterpolateParameters(query, backend.TimeRange{From: from, To: to}, "WHERE $__timeFilter(time_column)", params)
+		require.NoError(t, err)
+
+		require.Equal(t, "WHERE time_column BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL ? SECOND) AND FROM_UNIXTIME(?)", params.Placeholders(sql))
+		require.Equal(t, []any{from.Unix(), to.Unix()}, params.Args())
+	})
+
+	t.Run("rejects restricted functions", func(t *testing.T) {
+		_, err := engine.InterpolateParameters(query, timeRange, "select user()", sqleng.NewQueryParameters(engine.Placeholder))
+		require.Error(t, err)
+	})
+}
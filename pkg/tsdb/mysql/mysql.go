@@ -56,6 +56,7 @@ func newInstanceSettings(cfg *setting.Cfg, httpClientProvider httpclient.Provide
 			MaxOpenConns:            cfg.SqlDatasourceMaxOpenConnsDefault,
 			MaxIdleConns:            cfg.SqlDatasourceMaxIdleConnsDefault,
 			ConnMaxLifetime:         cfg.SqlDatasourceMaxConnLifetimeDefault,
+			QueryTimeout:            cfg.SqlDatasourceQueryTimeoutDefault,
 			SecureDSProxy:           false,
 			AllowCleartextPasswords: false,
 		}
@@ -142,6 +143,8 @@ func newInstanceSettings(cfg *setting.Cfg, httpClientProvider httpclient.Provide
 			TimeColumnNames:   []string{"time", "time_sec"},
 			MetricColumnTypes: []string{"CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			SchemaDialect:     mysqlSchemaDialect{},
+			StatementTimeout:  mysqlStatementTimeout{},
 		}
 
 		rowTransformer := mysqlQueryResultTransformer{
@@ -161,6 +164,15 @@ func (s *Service) getDataSourceHandler(ctx context.Context, pluginCtx backend.Pl
 	return instance, nil
 }
 
+// CallResource serves the schema of the connected SQL database
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return dsHandler.CallResource(ctx, req, sender)
+}
+
 // CheckHealth pings the connected SQL database
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
@@ -0,0 +1,32 @@
+package mysql
+
+// mysqlSchemaDialect discovers the schema of MySQL databases. MySQL has no schemas inside of databases, tables are
+// discovered in the requested database or the current one. Only the current database, the one the data source
+// connects to, is listed, as the data source user may be able to read other databases.
+type mysqlSchemaDialect struct{}
+
+func (mysqlSchemaDialect) DatabasesQuery() (string, []any) {
+	return "SELECT DATABASE() FROM DUAL WHERE DATABASE() IS NOT NULL", nil
+}
+
+func (mysqlSchemaDialect) SchemasQuery(string) (string, []any) {
+	return "", nil
+}
+
+func (mysqlSchemaDialect) TablesQuery(database string, _ string) (string, []any) {
+	return `SELECT TABLE_NAME FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+		ORDER BY TABLE_NAME`, []any{database}
+}
+
+func (mysqlSchemaDialect) ColumnsQuery(database string, _ string, table string) (string, []any) {
+	return `SELECT COLUMN_NAME, COLUMN_TYPE FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`, []any{database, table}
+}
+
+func (mysqlSchemaDialect) IndexesQuery(database string, _ string, table string) (string, []any) {
+	return `SELECT INDEX_NAME, NON_UNIQUE = 0, COLUMN_NAME FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, []any{database, table}
+}
@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+)
+
+var selectRegex = regexp.MustCompile(`(?i)^\s*select\b`)
+
+// mysqlStatementTimeout applies the query timeout with the MAX_EXECUTION_TIME optimizer hint. MySQL only supports the
+// hint in SELECT statements, other statements are only cancelled by the client.
+type mysqlStatementTimeout struct{}
+
+func (mysqlStatementTimeout) StatementTimeout(query string, timeout time.Duration) (string, string, string) {
+	loc := selectRegex.FindStringIndex(query)
+	if loc == nil {
+		return query, "", ""
+	}
+	return fmt.Sprintf("%s /*+ MAX_EXECUTION_TIME(%d) */%s", query[:loc[1]], timeout.Milliseconds(), query[loc[1]:]), "", ""
+}
+
+func (mysqlStatementTimeout) IsStatementTimeout(err error) bool {
+	var driverErr *mysql.MySQLError
+	return errors.As(err, &driverErr) && driverErr.Number == mysqlerr.ER_QUERY_TIMEOUT
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{query: "SELECT * FROM metrics", expected: "SELECT /*+ MAX_EXECUTION_TIME(5000) */ * FROM metrics"},
+		{query: "  select\n1", expected: "  select /*+ MAX_EXECUTION_TIME(5000) */\n1"},
+		{query: "SHOW TABLES", expected: "SHOW TABLES"},
+		{query: "SELECTED", expected: "SELECTED"},
+	}
+
+	for _, tc := range testCases {
+		query, set, reset := mysqlStatementTimeout{}.StatementTimeout(tc.query, 5*time.Second)
+		assert.Equal(t, tc.expected, query)
+		assert.Empty(t, set)
+		assert.Empty(t, reset)
+	}
+}
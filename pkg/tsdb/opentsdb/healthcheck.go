@@ -0,0 +1,85 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth asks OpenTSDB for a metric suggestion, which succeeds as soon as its HTTP API answers
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return getHealthCheckMessage(fmt.Errorf("failed to get data source information: %w", err)), nil
+	}
+
+	if err := probe(ctx, dsInfo); err != nil {
+		logger.Warn("OpenTSDB health check failed", "error", err)
+		return getHealthCheckMessage(err), nil
+	}
+
+	return getHealthCheckMessage(nil), nil
+}
+
+// probe runs a metric suggestion query, which is also what the query editor runs first
+func probe(ctx context.Context, dsInfo *datasourceInfo) error {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return fmt.Errorf("invalid OpenTSDB URL: %w", err)
+	}
+	u.Path = path.Join(u.Path, "api/suggest")
+	u.RawQuery = url.Values{"type": []string{"metrics"}, "max": []string{"1"}}.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error received while querying OpenTSDB: %w", err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the response of OpenTSDB: %w", err)
+	}
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("OpenTSDB request failed, status: %s", res.Status)
+	}
+
+	var suggestions []string
+	if err := json.Unmarshal(body, &suggestions); err != nil {
+		return fmt.Errorf("invalid response received from OpenTSDB, check the URL of the data source: %w", err)
+	}
+
+	return nil
+}
+
+func getHealthCheckMessage(err error) *backend.CheckHealthResult {
+	if err == nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusOk,
+			Message: "Data source successfully connected.",
+		}
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,72 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth(t *testing.T) {
+	testCases := []struct {
+		name            string
+		status          int
+		body            string
+		expectedStatus  backend.HealthStatus
+		expectedMessage string
+	}{
+		{
+			name:            "OpenTSDB answers suggestions",
+			status:          http.StatusOK,
+			body:            `["cpu.user"]`,
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Data source successfully connected.",
+		},
+		{
+			name:            "OpenTSDB rejects the request",
+			status:          http.StatusUnauthorized,
+			body:            `{"error": {"message": "unauthorized"}}`,
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "OpenTSDB request failed, status: 401 Unauthorized",
+		},
+		{
+			name:            "the URL does not point to OpenTSDB",
+			status:          http.StatusOK,
+			body:            `<html></html>`,
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "invalid response received from OpenTSDB",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/suggest", r.URL.Path)
+				assert.Equal(t, "metrics", r.URL.Query().Get("type"))
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			t.Cleanup(server.Close)
+
+			res, err := newTestService(server).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+			assert.Contains(t, res.Message, tc.expectedMessage)
+		})
+	}
+
+	t.Run("OpenTSDB is unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		service := newTestService(server)
+		server.Close()
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Contains(t, res.Message, "error received while querying OpenTSDB")
+	})
+}
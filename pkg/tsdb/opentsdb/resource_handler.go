@@ -0,0 +1,96 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourcePaths maps the resources to the OpenTSDB API endpoints: suggestions of metrics, tag keys and tag values,
+// lookup of the tags of metrics, and the aggregators and filters of the query editor
+var resourcePaths = map[string]string{
+	"suggest":        "api/suggest",
+	"search/lookup":  "api/search/lookup",
+	"aggregators":    "api/aggregators",
+	"config/filters": "api/config/filters",
+}
+
+// CallResource proxies the lookups of the query editor to OpenTSDB
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath, err := url.Parse(req.URL)
+	if err != nil {
+		logger.Error("Failed to parse resource URL", "error", err, "url", req.URL)
+		return err
+	}
+
+	apiPath, ok := resourcePaths[req.Path]
+	if !ok {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusNotFound})
+	}
+
+	if req.Method != http.MethodGet {
+		logger.Error("Invalid HTTP method", "method", req.Method, "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusMethodNotAllowed})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		logger.Error("Failed to parse data source URL", "error", err, "url", dsInfo.URL)
+		return err
+	}
+	u.Path = path.Join(u.Path, apiPath)
+	u.RawQuery = resourcePath.RawQuery
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Error("Failed to create request", "error", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		logger.Error("Failed resource call from opentsdb", "error", err, "path", req.Path)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read response body", "error", err)
+		return err
+	}
+
+	headers := map[string][]string{
+		"content-type": {res.Header.Get("Content-Type")},
+	}
+
+	// frontend sets the X-Grafana-Cache with the desired response cache control value
+	if cacheControl := req.GetHTTPHeaders().Get("X-Grafana-Cache"); cacheControl != "" && res.StatusCode/100 == 2 {
+		headers["X-Grafana-Cache"] = []string{"y"}
+		headers["Cache-Control"] = []string{cacheControl}
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
@@ -0,0 +1,104 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var requested *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.user"]`))
+	}))
+	t.Cleanup(server.Close)
+	service := newTestService(server)
+
+	callResource := func(t *testing.T, method string, url string, headers map[string][]string) *backend.CallResourceResponse {
+		t.Helper()
+
+		requested = nil
+		path, _, _ := strings.Cut(url, "?")
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{Method: method, Path: path, URL: url, Headers: headers}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("proxies suggestions to the OpenTSDB API", func(t *testing.T) {
+		res := callResource(t, http.MethodGet, "suggest?type=tagk&q=ho&max=10", nil)
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.JSONEq(t, `["cpu.user"]`, string(res.Body))
+		require.NotNil(t, requested)
+		assert.Equal(t, "/api/suggest", requested.URL.Path)
+		assert.Equal(t, "type=tagk&q=ho&max=10", requested.URL.RawQuery)
+	})
+
+	t.Run("proxies tag lookups to the OpenTSDB API", func(t *testing.T) {
+		res := callResource(t, http.MethodGet, "search/lookup?m=cpu.user%7Bhost%3D%2A%7D", nil)
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		require.NotNil(t, requested)
+		assert.Equal(t, "/api/search/lookup", requested.URL.Path)
+		assert.Equal(t, "cpu.user{host=*}", requested.URL.Query().Get("m"))
+	})
+
+	t.Run("sets the cache control asked by the frontend", func(t *testing.T) {
+		res := callResource(t, http.MethodGet, "aggregators", map[string][]string{"X-Grafana-Cache": {"private, max-age=60"}})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, []string{"private, max-age=60"}, res.Headers["Cache-Control"])
+	})
+
+	t.Run("rejects other paths", func(t *testing.T) {
+		res := callResource(t, http.MethodGet, "query", nil)
+
+		assert.Equal(t, http.StatusNotFound, res.Status)
+		assert.Nil(t, requested)
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		res := callResource(t, http.MethodPost, "suggest", nil)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Status)
+		assert.Nil(t, requested)
+	})
+}
+
+func newTestService(server *httptest.Server) *Service {
+	return &Service{
+		im: fakeInstanceManager{dsInfo: &datasourceInfo{HTTPClient: server.Client(), URL: server.URL}},
+	}
+}
+
+type fakeInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (f fakeInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f fakeInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
@@ -28,6 +28,22 @@ func newPostgresMacroEngine(timescaledb bool) sqleng.SQLMacroEngine {
 }
 
 func (m *postgresMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	return m.interpolate(query, timeRange, sql, nil)
+}
+
+// InterpolateParameters interpolates the macros of the query binding their values as parameters
+func (m *postgresMacroEngine) InterpolateParameters(query *backend.DataQuery, timeRange backend.TimeRange, sql string,
+	params *sqleng.QueryParameters) (string, error) {
+	return m.interpolate(query, timeRange, sql, params)
+}
+
+// Placeholder returns the placeholder of the n-th parameter
+func (m *postgresMacroEngine) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (m *postgresMacroEngine) interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string,
+	params *sqleng.QueryParameters) (string, error) {
 	// TODO: Handle error
 	rExp, _ := regexp.Compile(sExpr)
 	var macroError error
@@ -51,7 +67,7 @@ func (m *postgresMacroEngine) Interpolate(query *backend.DataQuery, timeRange ba
 		for i, arg := range args {
 			args[i] = strings.Trim(arg, " ")
 		}
-		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args, params)
 		if err != nil && macroError == nil {
 			macroError = err
 			return "macro_error()"
@@ -67,7 +83,7 @@ func (m *postgresMacroEngine) Interpolate(query *backend.DataQuery, timeRange ba
 }
 
 //nolint:gocyclo
-func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string, params *sqleng.QueryParameters) (string, error) {
 	switch name {
 	case "__time":
 		if len(args) == 0 {
@@ -84,11 +100,14 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
 
-		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+		from, to := timeRange.From.UTC(), timeRange.To.UTC()
+		return fmt.Sprintf("%s BETWEEN %s AND %s", args[0], params.Value(from, fmt.Sprintf("'%s'", from.Format(time.RFC3339Nano))), params.Value(to, fmt.Sprintf("'%s'", to.Format(time.RFC3339Nano)))), nil
 	case "__timeFrom":
-		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
+		from := timeRange.From.UTC()
+		return params.Value(from, fmt.Sprintf("'%s'", from.Format(time.RFC3339Nano))), nil
 	case "__timeTo":
-		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+		to := timeRange.To.UTC()
+		return params.Value(to, fmt.Sprintf("'%s'", to.Format(time.RFC3339Nano))), nil
 	case "__timeGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
@@ -114,7 +133,7 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 			interval.Seconds(),
 		), nil
 	case "__timeGroupAlias":
-		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args, params)
 		if err == nil {
 			return tg + " AS \"time\"", nil
 		}
@@ -123,16 +142,20 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
-		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+		from, to := timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()
+		return fmt.Sprintf("%s >= %s AND %s <= %s", args[0], params.Value(from, fmt.Sprint(from)), args[0], params.Value(to, fmt.Sprint(to))), nil
 	case "__unixEpochNanoFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
-		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+		from, to := timeRange.From.UTC().UnixNano(), timeRange.To.UTC().UnixNano()
+		return fmt.Sprintf("%s >= %s AND %s <= %s", args[0], params.Value(from, fmt.Sprint(from)), args[0], params.Value(to, fmt.Sprint(to))), nil
 	case "__unixEpochNanoFrom":
-		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+		from := timeRange.From.UTC().UnixNano()
+		return params.Value(from, fmt.Sprint(from)), nil
 	case "__unixEpochNanoTo":
-		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+		to := timeRange.To.UTC().UnixNano()
+		return params.Value(to, fmt.Sprint(to)), nil
 	case "__unixEpochGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
@@ -149,7 +172,7 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		}
 		return fmt.Sprintf("floor((%s)/%v)*%v", args[0], interval.Seconds(), interval.Seconds()), nil
 	case "__unixEpochGroupAlias":
-		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
+		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args, params)
 		if err == nil {
 			return tg + " AS \"time\"", nil
 		}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
 )
 
 func TestMacroEngine(t *testing.T) {
@@ -247,3 +249,18 @@ func TestMacroEngineConcurrency(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestMacroEngineParameters(t *testing.T) {
+	engine := &postgresMacroEngine{}
+	query := &backend.DataQuery{}
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	params := sqleng.NewQueryParameters(engine.Placeholder)
+	sql, err := engine.InterpolateParameters(query, timeRange, "SELECT $__timeGroup(time_column,'5m'), $__timeTo() WHERE $__timeFilter(time_column) AND $__unixEpochNanoFilter(epoch)", params)
+	require.NoError(t, err)
+
+	require.Equal(t, "SELECT floor(extract(epoch from time_column)/300)*300 AS \"time\", $1 WHERE time_column BETWEEN $2 AND $3 AND epoch >= $4 AND epoch <= $5", params.Placeholders(sql))
+	require.Equal(t, []any{to, from, to, from.UnixNano(), to.UnixNano()}, params.Args())
+}
@@ -60,6 +60,7 @@ func (s *Service) newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFacto
 			MaxOpenConns:        cfg.SqlDatasourceMaxOpenConnsDefault,
 			MaxIdleConns:        cfg.SqlDatasourceMaxIdleConnsDefault,
 			ConnMaxLifetime:     cfg.SqlDatasourceMaxConnLifetimeDefault,
+			QueryTimeout:        cfg.SqlDatasourceQueryTimeoutDefault,
 			Timescaledb:         false,
 			ConfigurationMethod: "file-path",
 			SecureDSProxy:       false,
@@ -111,6 +112,8 @@ func (s *Service) newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFacto
 			DSInfo:            dsInfo,
 			MetricColumnTypes: []string{"UNKNOWN", "TEXT", "VARCHAR", "CHAR"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			SchemaDialect:     postgresSchemaDialect{},
+			StatementTimeout:  postgresStatementTimeout{},
 		}
 
 		queryResultTransformer := postgresQueryResultTransformer{}
@@ -209,6 +212,15 @@ func (t *postgresQueryResultTransformer) TransformQueryError(_ log.Logger, err e
 	return err
 }
 
+// CallResource serves the schema of the connected SQL database
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	dsHandler, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return dsHandler.CallResource(ctx, req, sender)
+}
+
 // CheckHealth pings the connected SQL database
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	dsHandler, err := s.getDSInfo(ctx, req.PluginContext)
@@ -0,0 +1,40 @@
+package postgres
+
+// postgresSchemaDialect discovers the schema of the Postgres database the data source connects to. Tables are
+// discovered in the requested schema or the current one.
+type postgresSchemaDialect struct{}
+
+func (postgresSchemaDialect) DatabasesQuery() (string, []any) {
+	return "SELECT current_database()", nil
+}
+
+func (postgresSchemaDialect) SchemasQuery(string) (string, []any) {
+	return `SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+			AND schema_name NOT LIKE 'pg_temp_%' AND schema_name NOT LIKE 'pg_toast_temp_%'
+		ORDER BY schema_name`, nil
+}
+
+func (postgresSchemaDialect) TablesQuery(_ string, schema string) (string, []any) {
+	return `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema())
+		ORDER BY table_name`, []any{schema}
+}
+
+func (postgresSchemaDialect) ColumnsQuery(_ string, schema string, table string) (string, []any) {
+	return `SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2
+		ORDER BY ordinal_position`, []any{schema, table}
+}
+
+func (postgresSchemaDialect) IndexesQuery(_ string, schema string, table string) (string, []any) {
+	return `SELECT i.relname, ix.indisunique, a.attname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND t.relname = $2
+		ORDER BY i.relname, k.ord`, []any{schema, table}
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// queryCanceled is the code of the error of statements stopped by the statement timeout
+const queryCanceled = "57014"
+
+// postgresStatementTimeout applies the query timeout with the statement_timeout setting of the connection
+type postgresStatementTimeout struct{}
+
+func (postgresStatementTimeout) StatementTimeout(query string, timeout time.Duration) (string, string, string) {
+	return query, fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds()), "RESET statement_timeout"
+}
+
+func (postgresStatementTimeout) IsStatementTimeout(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == queryCanceled
+}
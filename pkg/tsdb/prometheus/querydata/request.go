@@ -45,6 +45,7 @@ type QueryData struct {
 	TimeInterval       string
 	enableDataplane    bool
 	exemplarSampler    func() exemplar.Sampler
+	rangeSplit         rangeSplit
 }
 
 func New(
@@ -64,6 +65,11 @@ func New(
 		return nil, err
 	}
 
+	rangeSplit, err := parseRangeSplit(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -83,6 +89,7 @@ func New(
 		URL:                settings.URL,
 		enableDataplane:    features.IsEnabled(featuremgmt.FlagPrometheusDataplane),
 		exemplarSampler:    exemplarSampler,
+		rangeSplit:         rangeSplit,
 	}, nil
 }
 
@@ -152,6 +159,13 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 }
 
 func (s *QueryData) rangeQuery(ctx context.Context, c *client.Client, q *models.Query, headers map[string]string) backend.DataResponse {
+	if chunks := s.rangeSplit.chunks(q); len(chunks) > 1 {
+		return s.splitRangeQuery(ctx, c, q, chunks, headers)
+	}
+	return s.fetchRange(ctx, c, q, headers)
+}
+
+func (s *QueryData) fetchRange(ctx context.Context, c *client.Client, q *models.Query, headers map[string]string) backend.DataResponse {
 	res, err := c.QueryRange(ctx, q)
 	if err != nil {
 		return backend.DataResponse{
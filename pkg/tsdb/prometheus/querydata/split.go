@@ -0,0 +1,218 @@
+package querydata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/util/maputil"
+)
+
+// defaultRangeSplitConcurrency is the number of chunks of a split range query fetched in parallel when the data
+// source does not configure it
+const defaultRangeSplitConcurrency = 4
+
+// maxRangeSplitChunks is the maximum number of chunks of a split range query. Longer chunks than the split interval
+// are used for ranges that would be split into more chunks.
+const maxRangeSplitChunks = 50
+
+// rangeSplit configures the splitting of long range queries into time chunks fetched in parallel. Range queries are not
+// split if the interval is zero.
+type rangeSplit struct {
+	interval    time.Duration
+	concurrency int
+}
+
+// parseRangeSplit reads the range split configuration from the rangeSplitInterval and rangeSplitConcurrency settings
+// of the data source
+func parseRangeSplit(jsonData map[string]any) (rangeSplit, error) {
+	split := rangeSplit{concurrency: defaultRangeSplitConcurrency}
+
+	interval, err := maputil.GetStringOptional(jsonData, "rangeSplitInterval")
+	if err != nil {
+		return split, err
+	}
+	if interval != "" {
+		split.interval, err = intervalv2.ParseIntervalStringToTimeDuration(interval)
+		if err != nil {
+			return split, fmt.Errorf("invalid range split interval %q: %w", interval, err)
+		}
+	}
+
+	if concurrency, ok := jsonData["rangeSplitConcurrency"]; ok && concurrency != nil {
+		value, ok := concurrency.(float64)
+		if !ok || value < 1 {
+			return split, fmt.Errorf("invalid range split concurrency %v, it must be a positive number", concurrency)
+		}
+		split.concurrency = int(value)
+	}
+
+	return split, nil
+}
+
+// chunks splits the time range of the query into consecutive chunks of the split interval. The chunks are aligned to
+// the step of the query and do not overlap, so together they return the same points as the whole range. The expression
+// is interpolated once for the whole range, so $__range and $__interval keep the values of the whole range and the
+// step stays the same in each chunk. The query is returned as is if its range is not longer than the split interval.
+// The range is split into at most maxRangeSplitChunks chunks.
+func (r rangeSplit) chunks(q *models.Query) []*models.Query {
+	if r.interval <= 0 || q.Step <= 0 {
+		return []*models.Query{q}
+	}
+
+	// chunks hold a whole number of steps
+	size := r.interval.Truncate(q.Step)
+	if size < q.Step {
+		size = q.Step
+	}
+
+	tr := q.TimeRange()
+	length := tr.End.Sub(tr.Start)
+	if length < size {
+		return []*models.Query{q}
+	}
+
+	// grow the chunks to a whole number of steps that splits the range into at most maxRangeSplitChunks chunks
+	if length/size+1 > maxRangeSplitChunks {
+		size = (length / (maxRangeSplitChunks - 1)).Truncate(q.Step) + q.Step
+	}
+
+	chunks := make([]*models.Query, 0, length/size+1)
+	for start := tr.Start; !start.After(tr.End); start = start.Add(size) {
+		end := start.Add(size - q.Step)
+		if end.After(tr.End) {
+			end = tr.End
+		}
+
+		chunk := *q
+		chunk.Start = start
+		chunk.End = end
+		chunks = append(chunks, &chunk)
+	}
+	return chunks
+}
+
+// splitRangeQuery fetches the chunks of a range query with the concurrency of the data source and merges their series.
+// The first failing chunk cancels the others.
+func (s *QueryData) splitRangeQuery(ctx context.Context, c *client.Client, q *models.Query, chunks []*models.Query, headers map[string]string) backend.DataResponse {
+	s.log.FromContext(ctx).Debug("Splitting range query", "query", q.Expr, "chunks", len(chunks), "concurrency", s.rangeSplit.concurrency)
+
+	responses := make([]backend.DataResponse, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.rangeSplit.concurrency)
+	for i, chunk := range chunks {
+		i, chunk := i, chunk
+		g.Go(func() error {
+			responses[i] = s.fetchRange(gctx, c, chunk, headers)
+			return responses[i].Error
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return backend.DataResponse{
+			Error: err,
+		}
+	}
+
+	return mergeChunks(q, responses)
+}
+
+// mergeChunks merges the frames of the chunks of a range query into a single frame per series. The chunks are in time
+// order, so the rows of each series stay sorted by time. The notices of the frames that are merged into others or
+// dropped are kept on the first frame.
+func mergeChunks(q *models.Query, responses []backend.DataResponse) backend.DataResponse {
+	frames := data.Frames{}
+	series := make(map[string]*data.Frame)
+	var notices []data.Notice
+
+	for _, res := range responses {
+		for _, frame := range res.Frames {
+			// frames without fields only carry the metadata of empty results
+			if len(frame.Fields) == 0 {
+				notices = appendNotices(notices, frame)
+				continue
+			}
+
+			key := seriesKey(frame)
+			if merged, ok := series[key]; ok {
+				appendRows(merged, frame)
+				notices = appendNotices(notices, frame)
+				continue
+			}
+			series[key] = frame
+			frames = append(frames, frame)
+		}
+	}
+
+	// Add frame to attach metadata
+	if len(frames) == 0 {
+		frames = append(frames, data.NewFrame(""))
+	}
+
+	for i, frame := range frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.ExecutedQueryString = ""
+		if i == 0 {
+			frame.Meta.ExecutedQueryString = executedQueryString(q)
+			for _, notice := range notices {
+				frame.Meta.Notices = appendNotice(frame.Meta.Notices, notice)
+			}
+		}
+	}
+
+	return backend.DataResponse{
+		Frames: frames,
+	}
+}
+
+// seriesKey identifies the series of a frame by its name and the names and labels of its value fields
+func seriesKey(frame *data.Frame) string {
+	var key strings.Builder
+	key.WriteString(frame.Name)
+	for _, field := range frame.Fields[1:] {
+		key.WriteString("\x00")
+		key.WriteString(field.Name)
+		key.WriteString(field.Labels.String())
+	}
+	return key.String()
+}
+
+// appendNotices appends the notices of a frame that are not in the list yet, chunks often return the same notices
+func appendNotices(notices []data.Notice, frame *data.Frame) []data.Notice {
+	if frame.Meta == nil {
+		return notices
+	}
+	for _, notice := range frame.Meta.Notices {
+		notices = appendNotice(notices, notice)
+	}
+	return notices
+}
+
+// appendNotice appends a notice if it is not in the list yet
+func appendNotice(notices []data.Notice, notice data.Notice) []data.Notice {
+	for _, n := range notices {
+		if n == notice {
+			return notices
+		}
+	}
+	return append(notices, notice)
+}
+
+// appendRows appends the rows of a frame to the frame of the same series
+func appendRows(dst *data.Frame, src *data.Frame) {
+	for i, field := range src.Fields {
+		for row := 0; row < field.Len(); row++ {
+			dst.Fields[i].Append(field.At(row))
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package querydata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+)
+
+func TestParseRangeSplit(t *testing.T) {
+	t.Run("does not split range queries by default", func(t *testing.T) {
+		split, err := parseRangeSplit(map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, rangeSplit{concurrency: defaultRangeSplitConcurrency}, split)
+	})
+
+	t.Run("reads the interval and the concurrency", func(t *testing.T) {
+		split, err := parseRangeSplit(map[string]any{"rangeSplitInterval": "1d", "rangeSplitConcurrency": float64(8)})
+		require.NoError(t, err)
+		assert.Equal(t, rangeSplit{interval: 24 * time.Hour, concurrency: 8}, split)
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		_, err := parseRangeSplit(map[string]any{"rangeSplitInterval": "daily"})
+		require.Error(t, err)
+
+		_, err = parseRangeSplit(map[string]any{"rangeSplitConcurrency": float64(0)})
+		require.Error(t, err)
+	})
+}
+
+func TestRangeSplitChunks(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := &models.Query{
+		Expr:       "rate(up[$__range])",
+		Step:       time.Hour,
+		Start:      start.Add(10 * time.Minute),
+		End:        start.Add(50*time.Hour + 10*time.Minute),
+		RangeQuery: true,
+	}
+
+	t.Run("splits the range into step aligned chunks", func(t *testing.T) {
+		chunks := rangeSplit{interval: 24*time.Hour + 30*time.Minute}.chunks(query)
+		require.Len(t, chunks, 3)
+
+		expected := []models.TimeRange{
+			{Start: start, End: start.Add(23 * time.Hour), Step: time.Hour},
+			{Start: start.Add(24 * time.Hour), End: start.Add(47 * time.Hour), Step: time.Hour},
+			{Start: start.Add(48 * time.Hour), End: start.Add(50 * time.Hour), Step: time.Hour},
+		}
+		for i, chunk := range chunks {
+			assert.Equal(t, expected[i], chunk.TimeRange())
+			// interpolated for the whole range
+			assert.Equal(t, query.Expr, chunk.Expr)
+		}
+	})
+
+	t.Run("does not split ranges shorter than the interval", func(t *testing.T) {
+		chunks := rangeSplit{interval: 7 * 24 * time.Hour}.chunks(query)
+		assert.Equal(t, []*models.Query{query}, chunks)
+	})
+
+	t.Run("does not split without an interval", func(t *testing.T) {
+		chunks := rangeSplit{}.chunks(query)
+		assert.Equal(t, []*models.Query{query}, chunks)
+	})
+
+	t.Run("chunks hold at least one step", func(t *testing.T) {
+		short := *query
+		short.End = start.Add(5*time.Hour + 10*time.Minute)
+		chunks := rangeSplit{interval: time.Minute}.chunks(&short)
+		require.Len(t, chunks, 6)
+		assert.Equal(t, chunks[0].TimeRange().Start, chunks[0].TimeRange().End)
+	})
+
+	t.Run("limits the number of chunks", func(t *testing.T) {
+		long := *query
+		long.Step = time.Minute
+		chunks := rangeSplit{interval: time.Minute}.chunks(&long)
+		require.LessOrEqual(t, len(chunks), maxRangeSplitChunks)
+
+		// the chunks still cover the whole range without overlapping
+		tr := long.TimeRange()
+		assert.Equal(t, tr.Start, chunks[0].TimeRange().Start)
+		assert.Equal(t, tr.End, chunks[len(chunks)-1].TimeRange().End)
+		for i := 1; i < len(chunks); i++ {
+			assert.Equal(t, chunks[i-1].TimeRange().End.Add(long.Step), chunks[i].TimeRange().Start)
+		}
+	})
+}
+
+func TestSplitRangeQuery(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := &models.Query{
+		Expr:       "up",
+		Step:       time.Hour,
+		Start:      start,
+		End:        start.Add(5 * time.Hour),
+		RangeQuery: true,
+	}
+
+	newQueryData := func(concurrency int) *QueryData {
+		return &QueryData{
+			tracer:          tracing.DefaultTracer(),
+			log:             log.New(),
+			exemplarSampler: exemplar.NewNoOpSampler,
+			rangeSplit:      rangeSplit{interval: 2 * time.Hour, concurrency: concurrency},
+		}
+	}
+
+	t.Run("merges the chunks into a frame per series", func(t *testing.T) {
+		doer := &fakeRangeDoer{seriesBFrom: start.Add(2 * time.Hour).Unix()}
+		s := newQueryData(2)
+
+		res := s.rangeQuery(context.Background(), client.NewClient(doer, http.MethodGet, "http://localhost:9090"), query, nil)
+		require.NoError(t, res.Error)
+		assert.Len(t, doer.starts, 3)
+
+		require.Len(t, res.Frames, 2)
+		a, b := res.Frames[0], res.Frames[1]
+		assert.Equal(t, data.Labels{"job": "a"}, a.Fields[1].Labels)
+		require.Equal(t, 6, a.Rows())
+		for i := 0; i < a.Rows(); i++ {
+			assert.Equal(t, start.Add(time.Duration(i)*time.Hour), a.Fields[0].At(i))
+		}
+		assert.Equal(t, data.Labels{"job": "b"}, b.Fields[1].Labels)
+		require.Equal(t, 4, b.Rows())
+		assert.Equal(t, start.Add(2*time.Hour), b.Fields[0].At(0))
+		assert.Equal(t, "Expr: up\nStep: 1h0m0s", res.Frames[0].Meta.ExecutedQueryString)
+		assert.Empty(t, res.Frames[1].Meta.ExecutedQueryString)
+	})
+
+	t.Run("keeps the notices of empty and merged chunks", func(t *testing.T) {
+		notice := func(text string) *data.FrameMeta {
+			return &data.FrameMeta{Notices: []data.Notice{{Severity: data.NoticeSeverityWarning, Text: text}}}
+		}
+		series := func(meta *data.FrameMeta) *data.Frame {
+			return data.NewFrame("",
+				data.NewField("Time", nil, []time.Time{start}),
+				data.NewField("Value", data.Labels{"job": "a"}, []float64{1}),
+			).SetMeta(meta)
+		}
+		responses := []backend.DataResponse{
+			{Frames: data.Frames{data.NewFrame("").SetMeta(notice("empty"))}},
+			{Frames: data.Frames{series(nil)}},
+			{Frames: data.Frames{series(notice("merged"))}},
+			{Frames: data.Frames{series(notice("merged"))}},
+		}
+
+		res := mergeChunks(query, responses)
+		require.Len(t, res.Frames, 1)
+		assert.Equal(t, 3, res.Frames[0].Rows())
+		assert.Equal(t, []data.Notice{
+			{Severity: data.NoticeSeverityWarning, Text: "empty"},
+			{Severity: data.NoticeSeverityWarning, Text: "merged"},
+		}, res.Frames[0].Meta.Notices)
+	})
+
+	t.Run("keeps the notices of empty results", func(t *testing.T) {
+		responses := []backend.DataResponse{
+			{Frames: data.Frames{data.NewFrame("").SetMeta(&data.FrameMeta{Notices: []data.Notice{{Text: "no data"}}})}},
+			{Frames: data.Frames{data.NewFrame("")}},
+		}
+
+		res := mergeChunks(query, responses)
+		require.Len(t, res.Frames, 1)
+		assert.Equal(t, []data.Notice{{Text: "no data"}}, res.Frames[0].Meta.Notices)
+	})
+
+	t.Run("fails if a chunk fails", func(t *testing.T) {
+		doer := &fakeRangeDoer{failAt: start.Add(2 * time.Hour).Unix(), seriesBFrom: math.MaxInt64}
+		s := newQueryData(1)
+
+		res := s.rangeQuery(context.Background(), client.NewClient(doer, http.MethodGet, "http://localhost:9090"), query, nil)
+		require.Error(t, res.Error)
+		assert.Empty(t, res.Frames)
+	})
+}
+
+// fakeRangeDoer answers range queries with a point per step for series a, and for series b from seriesBFrom
+type fakeRangeDoer struct {
+	mu          sync.Mutex
+	starts      []int64
+	failAt      int64
+	seriesBFrom int64
+}
+
+func (d *fakeRangeDoer) Do(req *http.Request) (*http.Response, error) {
+	var start, end, step int64
+	if _, err := fmt.Sscan(req.URL.Query().Get("start"), &start); err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Sscan(req.URL.Query().Get("end"), &end); err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Sscan(req.URL.Query().Get("step"), &step); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.starts = append(d.starts, start)
+	d.mu.Unlock()
+
+	if start == d.failAt {
+		return nil, fmt.Errorf("chunk failed")
+	}
+
+	values := make([]string, 0)
+	for ts := start; ts <= end; ts += step {
+		values = append(values, fmt.Sprintf(`[%d, "1"]`, ts))
+	}
+	series := []string{fmt.Sprintf(`{"metric": {"job": "a"}, "values": [%s]}`, strings.Join(values, ","))}
+	if start >= d.seriesBFrom {
+		series = append(series, fmt.Sprintf(`{"metric": {"job": "b"}, "values": [%s]}`, strings.Join(values, ",")))
+	}
+
+	body := fmt.Sprintf(`{"status": "success", "data": {"resultType": "matrix", "result": [%s]}}`, strings.Join(series, ","))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
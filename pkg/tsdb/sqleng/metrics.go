@@ -0,0 +1,24 @@
+package sqleng
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queriesTruncated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "sql_datasource_queries_truncated_total",
+		Help:      "Number of SQL data source queries whose results were truncated to the row limit",
+	})
+	queriesTimedOut = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "sql_datasource_queries_timed_out_total",
+		Help:      "Number of SQL data source queries that exceeded the query timeout",
+	})
+	queriesCancelled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "sql_datasource_queries_cancelled_total",
+		Help:      "Number of SQL data source queries cancelled because the request was aborted",
+	})
+)
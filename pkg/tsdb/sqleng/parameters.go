@@ -0,0 +1,193 @@
+package sqleng
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// variableRegex matches the $var, ${var} and [[var]] syntaxes of template variables
+var variableRegex = regexp.MustCompile(`\$(\w+)|\$\{(\w+)\}|\[\[(\w+)\]\]`)
+
+// markerRegex matches the markers of values bound to parameters
+var markerRegex = regexp.MustCompile("\x00(\\d+)\x00")
+
+// quotedRegex matches the string literals, the quoted identifiers and the comments of a query. Quotes are escaped by
+// doubling them, as in standard SQL.
+var quotedRegex = regexp.MustCompile(`'(?:[^']|'')*'|"(?:[^"]|"")*"|` + "`[^`]*`" + `|--[^\n]*|/\*(?s:.*?)\*/`)
+
+// macroRegex matches macro calls with their arguments
+var macroRegex = regexp.MustCompile(`\$__\w+\(([^\)]*)\)`)
+
+// SQLParameterizedMacroEngine interpolates macros binding their values as parameters of a prepared statement instead
+// of interpolating the values in the query.
+type SQLParameterizedMacroEngine interface {
+	SQLMacroEngine
+	// Placeholder returns the placeholder of the n-th parameter of a query, starting at 1
+	Placeholder(n int) string
+	// InterpolateParameters interpolates the macros of the query and binds their values to the parameters
+	InterpolateParameters(query *backend.DataQuery, timeRange backend.TimeRange, sql string, params *QueryParameters) (string, error)
+}
+
+// QueryParameters are the parameters bound to a parameterized query. A nil *QueryParameters is valid and stands for
+// a query that is not parameterized.
+//
+// Values are bound to markers which are replaced with the placeholders of the dialect once the whole query is
+// interpolated, so that the parameters are numbered in the order they appear in the query whatever the order they
+// were bound in.
+type QueryParameters struct {
+	placeholder func(n int) string
+	values      []any
+	args        []any
+}
+
+func NewQueryParameters(placeholder func(n int) string) *QueryParameters {
+	return &QueryParameters{placeholder: placeholder}
+}
+
+// Bind binds the value as a parameter and returns its marker
+func (p *QueryParameters) Bind(value any) string {
+	p.values = append(p.values, value)
+	return fmt.Sprintf("\x00%d\x00", len(p.values)-1)
+}
+
+// BindList binds each value as a parameter and returns their markers separated by commas
+func (p *QueryParameters) BindList(values []any) string {
+	markers := make([]string, 0, len(values))
+	for _, value := range values {
+		markers = append(markers, p.Bind(value))
+	}
+	return strings.Join(markers, ",")
+}
+
+// Value binds the value as a parameter and returns its placeholder if the query is parameterized, or returns the
+// literal of the value otherwise
+func (p *QueryParameters) Value(value any, literal string) string {
+	if p == nil {
+		return literal
+	}
+	return p.Bind(value)
+}
+
+// Placeholders replaces the markers of the bound values in the query with the placeholders of the dialect, and sets
+// the arguments of the query in the order of the placeholders
+func (p *QueryParameters) Placeholders(sql string) string {
+	p.args = make([]any, 0, len(p.values))
+	return markerRegex.ReplaceAllStringFunc(sql, func(marker string) string {
+		i, _ := strconv.Atoi(markerRegex.FindStringSubmatch(marker)[1])
+		p.args = append(p.args, p.values[i])
+		return p.placeholder(len(p.args))
+	})
+}
+
+// Args returns the arguments of the query in the order of their placeholders
+func (p *QueryParameters) Args() []any {
+	if p == nil {
+		return nil
+	}
+	return p.args
+}
+
+// bindVariables replaces the references to template variables in the query with parameters holding their values. Each
+// value of multi-value variables is bound to its own parameter. References to unknown variables are kept, as well as
+// the references in comments. Variables cannot be bound inside string literals and quoted identifiers, so references
+// to known variables inside of them are rejected.
+func bindVariables(sql string, variables map[string][]string, params *QueryParameters) (string, error) {
+	// macros need the values of their arguments in the query
+	for _, args := range macroRegex.FindAllStringSubmatch(sql, -1) {
+		if name := findVariable(args[1], variables); name != "" {
+			return "", fmt.Errorf("template variable %s cannot be used in the arguments of macros of parameterized queries", name)
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range quotedRegex.FindAllStringIndex(sql, -1) {
+		code, err := bindCode(sql[last:loc[0]], variables, params)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(code)
+
+		quoted := sql[loc[0]:loc[1]]
+		isComment := strings.HasPrefix(quoted, "--") || strings.HasPrefix(quoted, "/*")
+		if name := findVariable(quoted, variables); name != "" && !isComment {
+			return "", fmt.Errorf("template variable %s cannot be used in string literals or quoted identifiers of parameterized queries", name)
+		}
+		b.WriteString(quoted)
+		last = loc[1]
+	}
+
+	code, err := bindCode(sql[last:], variables, params)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(code)
+
+	return b.String(), nil
+}
+
+// bindCode binds the template variables of a part of a query outside of string literals, quoted identifiers and
+// comments
+func bindCode(sql string, variables map[string][]string, params *QueryParameters) (string, error) {
+	var bindErr error
+	sql = variableRegex.ReplaceAllStringFunc(sql, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		values, ok := variables[groups[1]+groups[2]+groups[3]]
+		if !ok {
+			return match
+		}
+		if len(values) == 0 {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("template variable %s has no value", match)
+			}
+			return match
+		}
+
+		args := make([]any, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+		}
+		return params.BindList(args)
+	})
+	if bindErr != nil {
+		return "", bindErr
+	}
+
+	return sql, nil
+}
+
+// findVariable returns the first reference to a known template variable in the string, or an empty string
+func findVariable(s string, variables map[string][]string) string {
+	for _, groups := range variableRegex.FindAllStringSubmatch(s, -1) {
+		if _, ok := variables[groups[1]+groups[2]+groups[3]]; ok {
+			return groups[0]
+		}
+	}
+	return ""
+}
+
+// interpolateParameters binds the template variables of the query and interpolates its macros as parameters. It fails if
+// the macro engine of the data source does not support parameterized queries.
+func (e *DataSourceHandler) interpolateParameters(query *backend.DataQuery, timeRange backend.TimeRange, sql string,
+	variables map[string][]string) (string, *QueryParameters, error) {
+	macroEngine, ok := e.macroEngine.(SQLParameterizedMacroEngine)
+	if !ok {
+		return sql, nil, fmt.Errorf("parameterized queries are not supported by this data source")
+	}
+
+	params := NewQueryParameters(macroEngine.Placeholder)
+	sql, err := bindVariables(sql, variables, params)
+	if err != nil {
+		return sql, nil, err
+	}
+
+	sql, err = macroEngine.InterpolateParameters(query, timeRange, sql, params)
+	if err != nil {
+		return sql, nil, err
+	}
+	return params.Placeholders(sql), params, nil
+}
@@ -0,0 +1,172 @@
+package sqleng
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestBindVariables(t *testing.T) {
+	variables := map[string][]string{
+		"host": {"a"},
+		"env":  {"dev", "o'brien"},
+		"none": {},
+	}
+
+	testCases := []struct {
+		name         string
+		sql          string
+		expectedSQL  string
+		expectedArgs []any
+		expectedErr  string
+	}{
+		{
+			name:         "binds the syntaxes of template variables",
+			sql:          "WHERE host = $host OR host = ${host} OR host = [[host]]",
+			expectedSQL:  "WHERE host = $1 OR host = $2 OR host = $3",
+			expectedArgs: []any{"a", "a", "a"},
+		},
+		{
+			name:         "expands multi-value variables into parameter lists",
+			sql:          "WHERE env IN ($env) AND host = $host",
+			expectedSQL:  "WHERE env IN ($1,$2) AND host = $3",
+			expectedArgs: []any{"dev", "o'brien", "a"},
+		},
+		{
+			name:         "keeps unknown variables and macros",
+			sql:          "WHERE $__timeFilter(time) AND region = $region AND $__interval_ms > 0",
+			expectedSQL:  "WHERE $__timeFilter(time) AND region = $region AND $__interval_ms > 0",
+			expectedArgs: []any{},
+		},
+		{
+			name:        "rejects variables in the arguments of macros",
+			sql:         "SELECT $__timeGroup(time, $host)",
+			expectedErr: "cannot be used in the arguments of macros",
+		},
+		{
+			name:         "keeps quoted variables of other data sources and variables in comments",
+			sql:          "SELECT '$region', \"$zone\" -- $host\nFROM t /* ${env} */ WHERE host = $host AND note = 'it''s'",
+			expectedSQL:  "SELECT '$region', \"$zone\" -- $host\nFROM t /* ${env} */ WHERE host = $1 AND note = 'it''s'",
+			expectedArgs: []any{"a"},
+		},
+		{
+			name:        "rejects variables in string literals",
+			sql:         "WHERE name LIKE '%$host%'",
+			expectedErr: "cannot be used in string literals",
+		},
+		{
+			name:        "rejects variables in quoted identifiers",
+			sql:         "SELECT * FROM `${env}_metrics`",
+			expectedErr: "cannot be used in string literals",
+		},
+		{
+			name:        "rejects variables without values",
+			sql:         "WHERE host = $none",
+			expectedErr: "has no value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := NewQueryParameters(func(n int) string { return fmt.Sprintf("$%d", n) })
+			sql, err := bindVariables(tc.sql, variables, params)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedSQL, params.Placeholders(sql))
+			assert.Equal(t, tc.expectedArgs, params.Args())
+		})
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	t.Run("numbers the parameters in the order they appear in the query", func(t *testing.T) {
+		params := NewQueryParameters(func(n int) string { return fmt.Sprintf("@p%d", n) })
+		second := params.Bind("second")
+		first := params.BindList([]any{"first", 1})
+
+		assert.Equal(t, "@p1,@p2 @p3", params.Placeholders(first+" "+second))
+		assert.Equal(t, []any{"first", 1, "second"}, params.Args())
+	})
+
+	t.Run("interpolates literals if the query is not parameterized", func(t *testing.T) {
+		var params *QueryParameters
+
+		assert.Equal(t, "10", params.Value(10, "10"))
+		assert.Nil(t, params.Args())
+	})
+}
+
+func TestParameterizedQueries(t *testing.T) {
+	newHandler := func(t *testing.T, macroEngine SQLMacroEngine) *DataSourceHandler {
+		t.Helper()
+
+		config := DataPluginConfiguration{
+			DriverName:       "sqlite3",
+			ConnectionString: filepath.Join(t.TempDir(), "parameters.db"),
+			RowLimit:         100,
+		}
+		handler, err := NewQueryDataHandler(setting.NewCfg(), config, &testQueryResultTransformer{}, macroEngine, log.New("test"))
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+
+		_, err = handler.engine.Exec("CREATE TABLE hosts (name TEXT, cpu INTEGER)")
+		require.NoError(t, err)
+		_, err = handler.engine.Exec("INSERT INTO hosts (name, cpu) VALUES ('a', 10), ('b', 20), ('o''brien', 30)")
+		require.NoError(t, err)
+		return handler
+	}
+
+	query := func(t *testing.T, handler *DataSourceHandler, rawSQL string) backend.DataResponse {
+		t.Helper()
+
+		res, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(fmt.Sprintf(
+				`{"rawSql": %q, "format": "table", "parameterized": true, "variables": {"host": ["b", "o'brien"]}}`, rawSQL))}},
+		})
+		require.NoError(t, err)
+		return res.Responses["A"]
+	}
+
+	t.Run("binds variables and macros as parameters", func(t *testing.T) {
+		handler := newHandler(t, &testParameterizedMacroEngine{})
+
+		res := query(t, handler, "SELECT name FROM hosts WHERE name IN ($host) AND cpu > $__minCpu() ORDER BY name")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		assert.Equal(t, 1, res.Frames[0].Rows())
+		assert.Equal(t, "o'brien", *res.Frames[0].Fields[0].At(0).(*string))
+		assert.Equal(t, "SELECT name FROM hosts WHERE name IN (?,?) AND cpu > ? ORDER BY name", res.Frames[0].Meta.ExecutedQueryString)
+	})
+
+	t.Run("fails if the data source does not support parameterized queries", func(t *testing.T) {
+		handler := newHandler(t, &testMacroEngine{})
+
+		res := query(t, handler, "SELECT name FROM hosts WHERE name IN ($host)")
+		require.ErrorContains(t, res.Error, "parameterized queries are not supported")
+	})
+}
+
+type testParameterizedMacroEngine struct {
+	testMacroEngine
+}
+
+func (m *testParameterizedMacroEngine) Placeholder(int) string {
+	return "?"
+}
+
+func (m *testParameterizedMacroEngine) InterpolateParameters(_ *backend.DataQuery, _ backend.TimeRange, sql string, params *QueryParameters) (string, error) {
+	return strings.ReplaceAll(sql, "$__minCpu()", params.Value(20, "20")), nil
+}
@@ -0,0 +1,223 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+)
+
+// schemaCacheTTL is how long the discovered schema is cached
+const schemaCacheTTL = time.Minute
+
+// SQLSchemaDialect provides the queries discovering the schema of a database with their arguments. A dialect returns an
+// empty query if the database does not support the discovered object, for example schemas in MySQL.
+//
+// The queries of databases, schemas and tables return the names of the objects. The query of columns returns the name
+// and the type of the columns. The query of indexes returns the name of the index, whether it is unique and the name of
+// a column of the index, one row per column in the order of the index.
+type SQLSchemaDialect interface {
+	DatabasesQuery() (string, []any)
+	SchemasQuery(database string) (string, []any)
+	TablesQuery(database string, schema string) (string, []any)
+	ColumnsQuery(database string, schema string, table string) (string, []any)
+	IndexesQuery(database string, schema string, table string) (string, []any)
+}
+
+// Column is a column of a table
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Index is an index of a table
+type Index struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Columns []string `json:"columns"`
+}
+
+// schemaRequest holds the parameters of a schema discovery request
+type schemaRequest struct {
+	database string
+	schema   string
+	table    string
+}
+
+// CallResource serves the schema discovery resources: databases, schemas, tables, columns and indexes
+func (e *DataSourceHandler) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return e.resourceHandler.CallResource(ctx, req, sender)
+}
+
+func (e *DataSourceHandler) registerSchemaRoutes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/databases", e.schemaHandler(false, func(ctx context.Context, r schemaRequest) (any, error) {
+		query, args := e.schemaDialect.DatabasesQuery()
+		return e.queryNames(ctx, query, args)
+	}))
+	mux.HandleFunc("/schemas", e.schemaHandler(false, func(ctx context.Context, r schemaRequest) (any, error) {
+		query, args := e.schemaDialect.SchemasQuery(r.database)
+		return e.queryNames(ctx, query, args)
+	}))
+	mux.HandleFunc("/tables", e.schemaHandler(false, func(ctx context.Context, r schemaRequest) (any, error) {
+		query, args := e.schemaDialect.TablesQuery(r.database, r.schema)
+		return e.queryNames(ctx, query, args)
+	}))
+	mux.HandleFunc("/columns", e.schemaHandler(true, func(ctx context.Context, r schemaRequest) (any, error) {
+		query, args := e.schemaDialect.ColumnsQuery(r.database, r.schema, r.table)
+		return e.queryColumns(ctx, query, args)
+	}))
+	mux.HandleFunc("/indexes", e.schemaHandler(true, func(ctx context.Context, r schemaRequest) (any, error) {
+		query, args := e.schemaDialect.IndexesQuery(r.database, r.schema, r.table)
+		return e.queryIndexes(ctx, query, args)
+	}))
+	return mux
+}
+
+// schemaHandler validates a schema discovery request, enforces that only the database of the data source is discovered
+// and caches the discovered objects
+func (e *DataSourceHandler) schemaHandler(requiresTable bool, discover func(ctx context.Context, r schemaRequest) (any, error)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		logger := e.log.FromContext(req.Context())
+
+		if req.Method != http.MethodGet {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if e.schemaDialect == nil {
+			http.Error(rw, "schema discovery is not supported", http.StatusNotFound)
+			return
+		}
+
+		r := schemaRequest{
+			database: req.URL.Query().Get("database"),
+			schema:   req.URL.Query().Get("schema"),
+			table:    req.URL.Query().Get("table"),
+		}
+
+		if r.database == "" {
+			r.database = e.dsInfo.Database
+		}
+
+		// the data source user may be able to read other databases, but the data source is configured for a single one
+		if e.dsInfo.Database != "" && r.database != e.dsInfo.Database {
+			http.Error(rw, fmt.Sprintf("access to database %s is not allowed", r.database), http.StatusForbidden)
+			return
+		}
+
+		if requiresTable && r.table == "" {
+			http.Error(rw, "table is required", http.StatusBadRequest)
+			return
+		}
+
+		key := fmt.Sprintf("%s:%s:%s:%s", req.URL.Path, r.database, r.schema, r.table)
+		result, ok := e.schemaCache.Get(key)
+		if !ok {
+			var err error
+			result, err = discover(req.Context(), r)
+			if err != nil {
+				logger.Error("Failed to discover schema", "path", req.URL.Path, "error", err)
+				http.Error(rw, e.TransformQueryError(logger, err).Error(), http.StatusInternalServerError)
+				return
+			}
+			e.schemaCache.Set(key, result, schemaCacheTTL)
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			logger.Error("Failed to marshal schema", "error", err)
+			http.Error(rw, "failed to marshal schema", http.StatusInternalServerError)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		if _, err := rw.Write(body); err != nil {
+			logger.Error("Failed to write response", "error", err)
+		}
+	}
+}
+
+// queryNames runs a query returning names
+func (e *DataSourceHandler) queryNames(ctx context.Context, query string, args []any) ([]string, error) {
+	names := make([]string, 0)
+	err := e.querySchema(ctx, query, args, func(scan func(dest ...any) error) error {
+		var name string
+		if err := scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	return names, err
+}
+
+// queryColumns runs a query returning the name and the type of columns
+func (e *DataSourceHandler) queryColumns(ctx context.Context, query string, args []any) ([]Column, error) {
+	columns := make([]Column, 0)
+	err := e.querySchema(ctx, query, args, func(scan func(dest ...any) error) error {
+		var column Column
+		if err := scan(&column.Name, &column.Type); err != nil {
+			return err
+		}
+		columns = append(columns, column)
+		return nil
+	})
+	return columns, err
+}
+
+// queryIndexes runs a query returning the name, the uniqueness and a column of indexes, and groups the columns by index
+func (e *DataSourceHandler) queryIndexes(ctx context.Context, query string, args []any) ([]Index, error) {
+	indexes := make([]Index, 0)
+	err := e.querySchema(ctx, query, args, func(scan func(dest ...any) error) error {
+		var name, column string
+		var unique bool
+		if err := scan(&name, &unique, &column); err != nil {
+			return err
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, Index{Name: name, Unique: unique, Columns: []string{}})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+		return nil
+	})
+	return indexes, err
+}
+
+// querySchema runs a schema discovery query with bound arguments and calls handleRow for each row. Nothing is queried
+// if the query is empty.
+func (e *DataSourceHandler) querySchema(ctx context.Context, query string, args []any, handleRow func(scan func(dest ...any) error) error) error {
+	if query == "" {
+		return nil
+	}
+
+	session := e.engine.NewSession()
+	defer session.Close()
+
+	rows, err := session.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			e.log.Warn("Failed to close rows", "err", err)
+		}
+	}()
+
+	for rows.Next() {
+		if err := handleRow(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func newSchemaCache() *localcache.CacheService {
+	return localcache.New(schemaCacheTTL, 2*schemaCacheTTL)
+}
@@ -0,0 +1,148 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSchemaResources(t *testing.T) {
+	newHandler := func(t *testing.T, dialect SQLSchemaDialect, database string) *DataSourceHandler {
+		t.Helper()
+
+		config := DataPluginConfiguration{
+			DriverName:       "sqlite3",
+			ConnectionString: filepath.Join(t.TempDir(), "schema.db"),
+			DSInfo:           DataSourceInfo{Database: database},
+			SchemaDialect:    dialect,
+		}
+		handler, err := NewQueryDataHandler(setting.NewCfg(), config, &testQueryResultTransformer{}, nil, log.New("test"))
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+
+		_, err = handler.engine.Exec("CREATE TABLE metrics (time INTEGER, host TEXT, value REAL)")
+		require.NoError(t, err)
+		_, err = handler.engine.Exec("CREATE UNIQUE INDEX metrics_time_host ON metrics (time, host)")
+		require.NoError(t, err)
+		_, err = handler.engine.Exec("CREATE TABLE alerts (id INTEGER)")
+		require.NoError(t, err)
+		return handler
+	}
+
+	callResource := func(t *testing.T, handler *DataSourceHandler, method string, url string) *backend.CallResourceResponse {
+		t.Helper()
+
+		path, _, _ := strings.Cut(url, "?")
+		sender := &fakeSender{}
+		err := handler.CallResource(context.Background(), &backend.CallResourceRequest{Method: method, Path: path, URL: url}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("discovers the schema", func(t *testing.T) {
+		handler := newHandler(t, sqliteSchemaDialect{}, "main")
+
+		testCases := []struct {
+			path     string
+			expected any
+		}{
+			{path: "databases", expected: []string{"main"}},
+			{path: "schemas", expected: []string{}},
+			{path: "tables", expected: []string{"alerts", "metrics"}},
+			{path: "columns?table=metrics", expected: []Column{{Name: "time", Type: "INTEGER"}, {Name: "host", Type: "TEXT"}, {Name: "value", Type: "REAL"}}},
+			{path: "indexes?table=metrics", expected: []Index{{Name: "metrics_time_host", Unique: true, Columns: []string{"time", "host"}}}},
+		}
+
+		for _, tc := range testCases {
+			res := callResource(t, handler, http.MethodGet, tc.path)
+			require.Equal(t, http.StatusOK, res.Status, string(res.Body))
+
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(res.Body), tc.path)
+		}
+	})
+
+	t.Run("caches the discovered schema", func(t *testing.T) {
+		handler := newHandler(t, sqliteSchemaDialect{}, "")
+
+		res := callResource(t, handler, http.MethodGet, "tables")
+		require.Equal(t, http.StatusOK, res.Status)
+
+		_, err := handler.engine.Exec("CREATE TABLE events (id INTEGER)")
+		require.NoError(t, err)
+
+		res = callResource(t, handler, http.MethodGet, "tables")
+		require.Equal(t, http.StatusOK, res.Status)
+		assert.JSONEq(t, `["alerts", "metrics"]`, string(res.Body))
+	})
+
+	t.Run("rejects databases other than the database of the data source", func(t *testing.T) {
+		handler := newHandler(t, sqliteSchemaDialect{}, "main")
+
+		res := callResource(t, handler, http.MethodGet, "tables?database=other")
+		assert.Equal(t, http.StatusForbidden, res.Status)
+	})
+
+	t.Run("requires the table of columns and indexes", func(t *testing.T) {
+		handler := newHandler(t, sqliteSchemaDialect{}, "main")
+
+		assert.Equal(t, http.StatusBadRequest, callResource(t, handler, http.MethodGet, "columns").Status)
+		assert.Equal(t, http.StatusBadRequest, callResource(t, handler, http.MethodGet, "indexes").Status)
+	})
+
+	t.Run("only serves GET requests", func(t *testing.T) {
+		handler := newHandler(t, sqliteSchemaDialect{}, "main")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, callResource(t, handler, http.MethodPost, "tables").Status)
+	})
+
+	t.Run("does not serve the schema without a dialect", func(t *testing.T) {
+		handler := newHandler(t, nil, "main")
+
+		assert.Equal(t, http.StatusNotFound, callResource(t, handler, http.MethodGet, "tables").Status)
+	})
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
+
+type sqliteSchemaDialect struct{}
+
+func (sqliteSchemaDialect) DatabasesQuery() (string, []any) {
+	return "SELECT name FROM pragma_database_list ORDER BY seq", nil
+}
+
+func (sqliteSchemaDialect) SchemasQuery(string) (string, []any) {
+	return "", nil
+}
+
+func (sqliteSchemaDialect) TablesQuery(string, string) (string, []any) {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name", nil
+}
+
+func (sqliteSchemaDialect) ColumnsQuery(_ string, _ string, table string) (string, []any) {
+	return "SELECT name, type FROM pragma_table_info(?) ORDER BY cid", []any{table}
+}
+
+func (sqliteSchemaDialect) IndexesQuery(_ string, _ string, table string) (string, []any) {
+	return `SELECT il.name, il."unique", ii.name FROM pragma_index_list(?) il JOIN pragma_index_info(il.name) ii ORDER BY il.name, ii.seqno`, []any{table}
+}
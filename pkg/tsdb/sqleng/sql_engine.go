@@ -14,11 +14,12 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
-	"xorm.io/core"
 	"xorm.io/xorm"
 
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
@@ -76,6 +77,8 @@ type JsonData struct {
 	SecureDSProxyUsername   string `json:"secureSocksProxyUsername"`
 	AllowCleartextPasswords bool   `json:"allowCleartextPasswords"`
 	AuthenticationType      string `json:"authenticationType"`
+	QueryTimeout            int    `json:"queryTimeout"`
+	MaxRows                 int64  `json:"maxRows"`
 }
 
 type DataSourceInfo struct {
@@ -103,6 +106,11 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	// SchemaDialect provides the queries of the schema discovery resources, they are not served if it is nil
+	SchemaDialect SQLSchemaDialect
+	// StatementTimeout applies the query timeout of the data source in the database, queries are only cancelled by the
+	// client if it is nil
+	StatementTimeout SQLStatementTimeout
 }
 
 type DataSourceHandler struct {
@@ -114,7 +122,12 @@ type DataSourceHandler struct {
 	log                    log.Logger
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
+	queryTimeout           time.Duration
+	statementTimeout       SQLStatementTimeout
 	userError              string
+	schemaDialect          SQLSchemaDialect
+	schemaCache            *localcache.CacheService
+	resourceHandler        backend.CallResourceHandler
 }
 
 type QueryJson struct {
@@ -124,6 +137,9 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// Parameterized binds the template variables and the values of macros as parameters of the query
+	Parameterized bool                `json:"parameterized"`
+	Variables     map[string][]string `json:"variables"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -154,8 +170,13 @@ func NewQueryDataHandler(cfg *setting.Cfg, config DataPluginConfiguration, query
 		log:                    log,
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
+		queryTimeout:           time.Duration(config.DSInfo.JsonData.QueryTimeout) * time.Second,
+		statementTimeout:       config.StatementTimeout,
 		userError:              cfg.UserFacingDefaultError,
+		schemaDialect:          config.SchemaDialect,
+		schemaCache:            newSchemaCache(),
 	}
+	queryDataHandler.resourceHandler = httpadapter.New(queryDataHandler.registerSchemaRoutes())
 
 	if len(config.TimeColumnNames) > 0 {
 		queryDataHandler.timeColumnNames = config.TimeColumnNames
@@ -165,6 +186,11 @@ func NewQueryDataHandler(cfg *setting.Cfg, config DataPluginConfiguration, query
 		queryDataHandler.metricColumnTypes = config.MetricColumnTypes
 	}
 
+	// the row limit of the data source can only lower the row limit of the instance
+	if maxRows := config.DSInfo.JsonData.MaxRows; maxRows > 0 && (queryDataHandler.rowLimit <= 0 || maxRows < queryDataHandler.rowLimit) {
+		queryDataHandler.rowLimit = maxRows
+	}
+
 	engine, err := NewXormEngine(config.DriverName, config.ConnectionString)
 	if err != nil {
 		return nil, err
@@ -199,6 +225,7 @@ func (e *DataSourceHandler) Ping() error {
 
 func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
+	result.Responses = make(map[string]backend.DataResponse)
 	ch := make(chan DBDataResponse, len(req.Queries))
 	var wg sync.WaitGroup
 	// Execute each query in a goroutine and wait for them to finish afterwards
@@ -215,6 +242,13 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 			continue
 		}
 
+		// don't start queries of aborted requests
+		if ctx.Err() != nil {
+			queriesCancelled.Inc()
+			result.Responses[query.RefID] = backend.DataResponse{Error: ErrQueryCancelled.Errorf("query cancelled: %w", ctx.Err())}
+			continue
+		}
+
 		wg.Add(1)
 		go e.executeQuery(query, &wg, ctx, ch, queryjson)
 	}
@@ -223,7 +257,6 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 	// Read results from channels
 	close(ch)
-	result.Responses = make(map[string]backend.DataResponse)
 	for queryResult := range ch {
 		result.Responses[queryResult.refID] = queryResult.dataResponse
 	}
@@ -279,21 +312,29 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	}
 
 	// data source specific substitutions
-	interpolatedQuery, err = e.macroEngine.Interpolate(&query, timeRange, interpolatedQuery)
+	var params *QueryParameters
+	if queryJson.Parameterized {
+		interpolatedQuery, params, err = e.interpolateParameters(&query, timeRange, interpolatedQuery, queryJson.Variables)
+	} else {
+		interpolatedQuery, err = e.macroEngine.Interpolate(&query, timeRange, interpolatedQuery)
+	}
 	if err != nil {
 		errAppendDebug("interpolation failed", e.TransformQueryError(logger, err), interpolatedQuery)
 		return
 	}
 
-	session := e.engine.NewSession()
-	defer session.Close()
-	db := session.DB()
+	if e.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		queryContext, cancel = context.WithTimeout(queryContext, e.clientTimeout())
+		defer cancel()
+	}
 
-	rows, err := db.QueryContext(queryContext, interpolatedQuery)
+	rows, release, err := e.query(queryContext, interpolatedQuery, params.Args()...)
 	if err != nil {
-		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery)
+		errAppendDebug("db query error", e.transformQueryError(logger, queryContext, err), interpolatedQuery)
 		return
 	}
+	defer release()
 	defer func() {
 		if err := rows.Close(); err != nil {
 			logger.Warn("Failed to close rows", "err", err)
@@ -308,12 +349,17 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	frame, err := sqlutil.FrameFromRows(rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
 	if err != nil {
-		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
+		errAppendDebug("convert frame from rows error", e.transformQueryError(logger, queryContext, err), interpolatedQuery)
 		return
 	}
 
+	// the frame has a notice if rows were left out
+	if int64(frame.Rows()) == e.rowLimit && frame.Meta != nil && len(frame.Meta.Notices) > 0 {
+		queriesTruncated.Inc()
+	}
+
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
 	}
@@ -418,7 +464,7 @@ var Interpolate = func(query backend.DataQuery, timeRange backend.TimeRange, tim
 }
 
 func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext context.Context,
-	rows *core.Rows, interpolatedQuery string) (*dataQueryModel, error) {
+	rows *sql.Rows, interpolatedQuery string) (*dataQueryModel, error) {
 	columnNames, err := rows.Columns()
 	if err != nil {
 		return nil, err
@@ -525,7 +571,7 @@ type dataQueryModel struct {
 	timeIndex         int
 	timeEndIndex      int
 	metricIndex       int
-	rows              *core.Rows
+	rows              *sql.Rows
 	metricPrefix      bool
 	queryContext      context.Context
 }
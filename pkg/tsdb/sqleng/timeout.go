@@ -0,0 +1,98 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// clientTimeoutGrace is how much longer than the query timeout the client waits for the database to stop the query
+const clientTimeoutGrace = time.Second
+
+var (
+	ErrQueryTimeout   = errutil.Timeout("sqleng.queryTimeout")
+	ErrQueryCancelled = errutil.ClientClosedRequest("sqleng.queryCancelled")
+)
+
+// SQLStatementTimeout applies the query timeout of a data source in the database, so that the database stops queries
+// running for too long instead of only the client giving up on them.
+type SQLStatementTimeout interface {
+	// StatementTimeout returns the query with the timeout applied, and the statements setting and resetting the timeout
+	// of the connection running the query. The statements are empty if the query itself applies the timeout.
+	StatementTimeout(query string, timeout time.Duration) (timedQuery string, set string, reset string)
+	// IsStatementTimeout returns true if the database stopped the query because of the timeout
+	IsStatementTimeout(err error) bool
+}
+
+// clientTimeout returns how long the client waits for a query. With a statement timeout, the database stops the query
+// once the timeout is reached and the client only cancels it if the database did not. Without one, the client cancels
+// the query once the timeout is reached, which relies on the driver to stop it in the database.
+func (e *DataSourceHandler) clientTimeout() time.Duration {
+	if e.statementTimeout == nil {
+		return e.queryTimeout
+	}
+	return e.queryTimeout + clientTimeoutGrace
+}
+
+// query runs the query with its arguments and the timeout of the data source applied in the database. The returned
+// function releases the connection running the query and must be called once the rows are closed.
+func (e *DataSourceHandler) query(ctx context.Context, query string, args ...any) (*sql.Rows, func(), error) {
+	db := e.engine.DB().DB
+	if e.queryTimeout <= 0 || e.statementTimeout == nil {
+		rows, err := db.QueryContext(ctx, query, args...)
+		return rows, func() {}, err
+	}
+
+	query, set, reset := e.statementTimeout.StatementTimeout(query, e.queryTimeout)
+	if set == "" {
+		rows, err := db.QueryContext(ctx, query, args...)
+		return rows, func() {}, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() {
+		// the connection goes back to the pool, so it is discarded if its timeout could not be reset
+		if _, err := conn.ExecContext(context.Background(), reset); err != nil {
+			e.log.Warn("Failed to reset statement timeout, discarding connection", "err", err)
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		if err := conn.Close(); err != nil {
+			e.log.Warn("Failed to close connection", "err", err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, set); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return rows, release, nil
+}
+
+// transformQueryError reports the queries that timed out or were cancelled, and transforms the errors of other queries
+func (e *DataSourceHandler) transformQueryError(logger log.Logger, ctx context.Context, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		queriesCancelled.Inc()
+		return ErrQueryCancelled.Errorf("query cancelled: %w", err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || (e.statementTimeout != nil && e.statementTimeout.IsStatementTimeout(err)):
+		queriesTimedOut.Inc()
+		return ErrQueryTimeout.Errorf("query exceeded the timeout of %s: %w", e.queryTimeout, err)
+	default:
+		return e.TransformQueryError(logger, err)
+	}
+}
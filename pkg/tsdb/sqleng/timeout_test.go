@@ -0,0 +1,149 @@
+package sqleng
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestQueryLimits(t *testing.T) {
+	newHandler := func(t *testing.T, config DataPluginConfiguration) *DataSourceHandler {
+		t.Helper()
+
+		config.DriverName = "sqlite3"
+		config.ConnectionString = filepath.Join(t.TempDir(), "limits.db")
+		handler, err := NewQueryDataHandler(setting.NewCfg(), config, &testQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+
+		_, err = handler.engine.Exec("CREATE TABLE hosts (name TEXT)")
+		require.NoError(t, err)
+		_, err = handler.engine.Exec("INSERT INTO hosts (name) VALUES ('a'), ('b'), ('c')")
+		require.NoError(t, err)
+		return handler
+	}
+
+	query := func(ctx context.Context, t *testing.T, handler *DataSourceHandler) backend.DataResponse {
+		t.Helper()
+
+		res, err := handler.QueryData(ctx, &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(`{"rawSql": "SELECT name FROM hosts ORDER BY name", "format": "table"}`)}},
+		})
+		require.NoError(t, err)
+		return res.Responses["A"]
+	}
+
+	t.Run("the row limit of the data source lowers the row limit", func(t *testing.T) {
+		handler := newHandler(t, DataPluginConfiguration{RowLimit: 100, DSInfo: DataSourceInfo{JsonData: JsonData{MaxRows: 2}}})
+		assert.Equal(t, int64(2), handler.rowLimit)
+
+		handler = newHandler(t, DataPluginConfiguration{RowLimit: 100, DSInfo: DataSourceInfo{JsonData: JsonData{MaxRows: 1000}}})
+		assert.Equal(t, int64(100), handler.rowLimit)
+	})
+
+	t.Run("truncates the results to the row limit with a notice", func(t *testing.T) {
+		handler := newHandler(t, DataPluginConfiguration{RowLimit: 100, DSInfo: DataSourceInfo{JsonData: JsonData{MaxRows: 2}}})
+
+		res := query(context.Background(), t, handler)
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		assert.Equal(t, 2, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Meta.Notices, 1)
+	})
+
+	t.Run("does not run the queries of cancelled requests", func(t *testing.T) {
+		handler := newHandler(t, DataPluginConfiguration{RowLimit: 100})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res := query(ctx, t, handler)
+		require.ErrorIs(t, res.Error, ErrQueryCancelled)
+	})
+
+	t.Run("sets and resets the statement timeout of the connection", func(t *testing.T) {
+		statementTimeout := &testStatementTimeout{}
+		handler := newHandler(t, DataPluginConfiguration{
+			RowLimit:         100,
+			DSInfo:           DataSourceInfo{JsonData: JsonData{QueryTimeout: 10}},
+			StatementTimeout: statementTimeout,
+		})
+
+		res := query(context.Background(), t, handler)
+		require.NoError(t, res.Error)
+		assert.Equal(t, 3, res.Frames[0].Rows())
+		assert.Equal(t, 10*time.Second, statementTimeout.timeout)
+	})
+}
+
+func TestClientTimeout(t *testing.T) {
+	t.Run("the client waits for the database to stop the query", func(t *testing.T) {
+		handler := &DataSourceHandler{queryTimeout: 10 * time.Second, statementTimeout: &testStatementTimeout{}}
+		assert.Equal(t, 10*time.Second+clientTimeoutGrace, handler.clientTimeout())
+	})
+
+	t.Run("the client cancels the query without a statement timeout", func(t *testing.T) {
+		handler := &DataSourceHandler{queryTimeout: 10 * time.Second}
+		assert.Equal(t, 10*time.Second, handler.clientTimeout())
+	})
+}
+
+func TestTransformQueryError(t *testing.T) {
+	handler := &DataSourceHandler{
+		log:                    log.New("test"),
+		queryResultTransformer: &testQueryResultTransformer{},
+		queryTimeout:           time.Second,
+		statementTimeout:       &testStatementTimeout{},
+	}
+	err := errors.New("query failed")
+
+	t.Run("queries stopped by the database timed out", func(t *testing.T) {
+		assert.ErrorIs(t, handler.transformQueryError(handler.log, context.Background(), errStatementTimeout), ErrQueryTimeout)
+	})
+
+	t.Run("queries exceeding the deadline timed out", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		assert.ErrorIs(t, handler.transformQueryError(handler.log, ctx, err), ErrQueryTimeout)
+	})
+
+	t.Run("queries of aborted requests are cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, handler.transformQueryError(handler.log, ctx, errStatementTimeout), ErrQueryCancelled)
+	})
+
+	t.Run("other errors are transformed", func(t *testing.T) {
+		assert.Equal(t, err, handler.transformQueryError(handler.log, context.Background(), err))
+	})
+}
+
+var errStatementTimeout = errors.New("statement timeout")
+
+type testStatementTimeout struct {
+	timeout time.Duration
+}
+
+func (s *testStatementTimeout) StatementTimeout(query string, timeout time.Duration) (string, string, string) {
+	s.timeout = timeout
+	return query, "PRAGMA busy_timeout = 10000", "PRAGMA busy_timeout = 0"
+}
+
+func (s *testStatementTimeout) IsStatementTimeout(err error) bool {
+	return errors.Is(err, errStatementTimeout)
+}
+
+type testMacroEngine struct{}
+
+func (m *testMacroEngine) Interpolate(_ *backend.DataQuery, _ backend.TimeRange, sql string) (string, error) {
+	return sql, nil
+}
@@ -0,0 +1,83 @@
+import React, { ChangeEvent, FormEvent } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, InlineSwitch, Input, Select } from '@grafana/ui';
+
+import { AnomalyAlgorithm, anomalyAlgorithms, ExpressionQuery } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const Anomaly = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
+  const algorithm = anomalyAlgorithms.find((o) => o.value === query.algorithm);
+  const isSeasonal = query.algorithm === AnomalyAlgorithm.Seasonal;
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectAlgorithm = (value: SelectableValue<AnomalyAlgorithm>) => {
+    onChange({ ...query, algorithm: value.value });
+  };
+
+  const onWindowChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, window: event.target.value });
+  };
+
+  const onPeriodChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, period: event.target.value });
+  };
+
+  const onSensitivityChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const value = parseFloat(event.target.value);
+    onChange({ ...query, sensitivity: isNaN(value) ? undefined : value });
+  };
+
+  const onBandsChange = (event: FormEvent<HTMLInputElement>) => {
+    onChange({ ...query, bands: event.currentTarget.checked });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <InlineField label="Algorithm">
+          <Select options={anomalyAlgorithms} value={algorithm} onChange={onSelectAlgorithm} width={30} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        {isSeasonal ? (
+          <InlineField label="Period" labelWidth={labelWidth} tooltip="The length of a season, e.g. 1d or 1w">
+            <Input onChange={onPeriodChange} value={query.period} width={15} />
+          </InlineField>
+        ) : (
+          <InlineField
+            label="Window"
+            labelWidth={labelWidth}
+            tooltip="The duration of the window preceding each point that the band is calculated from, e.g. 30m or 1h"
+          >
+            <Input onChange={onWindowChange} value={query.window} width={15} />
+          </InlineField>
+        )}
+        <InlineField
+          label="Sensitivity"
+          tooltip="The number of standard deviations the band extends from the expected value. Defaults to 3."
+        >
+          <Input type="number" onChange={onSensitivityChange} value={query.sensitivity} placeholder="3" width={15} />
+        </InlineField>
+        <InlineField
+          label="Bands"
+          tooltip="Return the bounds of the band as series with a band label. Turn off in alert rules."
+        >
+          <InlineSwitch value={query.bands ?? true} onChange={onBandsChange} />
+        </InlineField>
+      </InlineFieldRow>
+    </>
+  );
+};
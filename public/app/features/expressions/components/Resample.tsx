@@ -1,9 +1,15 @@
 import React, { ChangeEvent } from 'react';
 
 import { SelectableValue } from '@grafana/data';
-import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+import { InlineField, InlineFieldRow, Input, Select, TimeZonePicker } from '@grafana/ui';
 
-import { downsamplingTypes, ExpressionQuery, upsamplingTypes } from '../types';
+import {
+  downsamplingTypes,
+  ExpressionQuery,
+  ResampleAlignment,
+  resampleAlignments,
+  upsamplingTypes,
+} from '../types';
 
 interface Props {
   refIds: Array<SelectableValue<string>>;
@@ -15,6 +21,7 @@ interface Props {
 export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
   const downsampler = downsamplingTypes.find((o) => o.value === query.downsampler);
   const upsampler = upsamplingTypes.find((o) => o.value === query.upsampler);
+  const alignment = resampleAlignments.find((o) => o.value === (query.alignment ?? ResampleAlignment.From));
 
   const onWindowChange = (event: ChangeEvent<HTMLInputElement>) => {
     onChange({ ...query, window: event.target.value });
@@ -32,6 +39,14 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
     onChange({ ...query, upsampler: value.value });
   };
 
+  const onSelectAlignment = (value: SelectableValue<ResampleAlignment>) => {
+    onChange({ ...query, alignment: value.value });
+  };
+
+  const onTimeZoneChange = (timezone?: string) => {
+    onChange({ ...query, timezone });
+  };
+
   return (
     <>
       <InlineFieldRow>
@@ -50,6 +65,16 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
           <Select options={upsamplingTypes} value={upsampler} onChange={onSelectUpsampler} width={25} />
         </InlineField>
       </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Align to" labelWidth={labelWidth}>
+          <Select options={resampleAlignments} value={alignment} onChange={onSelectAlignment} width={15} />
+        </InlineField>
+        {query.alignment === ResampleAlignment.Calendar && (
+          <InlineField label="Time zone">
+            <TimeZonePicker value={query.timezone} onChange={onTimeZoneChange} width={25} />
+          </InlineField>
+        )}
+      </InlineFieldRow>
     </>
   );
 };
@@ -0,0 +1,37 @@
+import React from 'react';
+
+import { CodeEditor } from '@grafana/ui';
+
+import { ExpressionQuery } from '../types';
+
+interface Props {
+  query: ExpressionQuery;
+  onChange: (query: ExpressionQuery) => void;
+  onRunQuery?: () => void;
+}
+
+const initialQuery = `SELECT *
+FROM A
+LIMIT 10`;
+
+export const SqlExpr = ({ onChange, query, onRunQuery }: Props) => {
+  const onEditorChange = (expression: string) => {
+    onChange({ ...query, expression });
+  };
+
+  return (
+    <CodeEditor
+      language="sql"
+      value={query.expression || initialQuery}
+      onBlur={(expression) => {
+        onEditorChange(expression);
+        onRunQuery?.();
+      }}
+      onSave={onEditorChange}
+      height={200}
+      width="100%"
+      showLineNumbers={true}
+      showMiniMap={false}
+    />
+  );
+};
@@ -1,4 +1,5 @@
 import { DataQuery, ReducerID, SelectableValue } from '@grafana/data';
+import { config } from '@grafana/runtime';
 
 import { EvalFunction } from '../alerting/state/alertDef';
 
@@ -13,6 +14,8 @@ export enum ExpressionQueryType {
   resample = 'resample',
   classic = 'classic_conditions',
   threshold = 'threshold',
+  sql = 'sql',
+  anomaly = 'anomaly',
 }
 
 export const getExpressionLabel = (type: ExpressionQueryType) => {
@@ -27,6 +30,10 @@ export const getExpressionLabel = (type: ExpressionQueryType) => {
       return 'Classic condition';
     case ExpressionQueryType.threshold:
       return 'Threshold';
+    case ExpressionQueryType.sql:
+      return 'SQL';
+    case ExpressionQueryType.anomaly:
+      return 'Anomaly detection';
   }
 };
 
@@ -59,7 +66,19 @@ export const expressionTypes: Array<SelectableValue<ExpressionQueryType>> = [
     description:
       'Takes one or more time series returned from a query or an expression and checks if any of the series match the threshold condition.',
   },
-];
+  {
+    value: ExpressionQueryType.anomaly,
+    label: 'Anomaly detection',
+    description:
+      'Takes one or more time series returned from a query or an expression and returns a series that is 1 where a value is outside of its expected band and 0 otherwise.',
+  },
+  {
+    value: ExpressionQueryType.sql,
+    label: 'SQL',
+    description:
+      'Runs a SQL SELECT statement over the results of queries and expressions, which are available as tables named by their refId.',
+  },
+].filter((expr) => expr.value !== ExpressionQueryType.sql || config.featureToggles.sqlExpressions);
 
 export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.min, label: 'Min', description: 'Get the minimum value' },
@@ -68,6 +87,21 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: 'p90', label: '90th percentile', description: 'Get the 90th percentile of the values' },
+  { value: 'p95', label: '95th percentile', description: 'Get the 95th percentile of the values' },
+  { value: 'p99', label: '99th percentile', description: 'Get the 99th percentile of the values' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the standard deviation of the values' },
+  { value: ReducerID.variance, label: 'Variance', description: 'Get the variance of the values' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum value' },
+  {
+    value: 'increase',
+    label: 'Increase',
+    description: 'Get the increase over the time range, accounting for counter resets',
+  },
+  { value: 'rate', label: 'Rate', description: 'Get the per-second rate of increase over the time range' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of non-null values' },
 ];
 
 export enum ReducerMode {
@@ -100,12 +134,57 @@ export const downsamplingTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.max, label: 'Max', description: 'Fill with the maximum value' },
   { value: ReducerID.mean, label: 'Mean', description: 'Fill with the average value' },
   { value: ReducerID.sum, label: 'Sum', description: 'Fill with the sum of all values' },
+  { value: ReducerID.first, label: 'First', description: 'Fill with the first value' },
+  { value: 'median', label: 'Median', description: 'Fill with the median value' },
+  { value: ReducerID.count, label: 'Count', description: 'Fill with the number of values' },
+  { value: 'p90', label: '90th percentile', description: 'Fill with the 90th percentile of the values' },
+  { value: 'p95', label: '95th percentile', description: 'Fill with the 95th percentile of the values' },
+  { value: 'p99', label: '99th percentile', description: 'Fill with the 99th percentile of the values' },
 ];
 
 export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'Interpolate linearly between the known values' },
+];
+
+export enum ResampleAlignment {
+  From = 'from',
+  Calendar = 'calendar',
+}
+
+export const resampleAlignments: Array<SelectableValue<ResampleAlignment>> = [
+  { value: ResampleAlignment.From, label: 'From', description: 'Align buckets to the start of the time range' },
+  {
+    value: ResampleAlignment.Calendar,
+    label: 'Calendar',
+    description: 'Align buckets to calendar boundaries, e.g. the start of an hour or a day',
+  },
+];
+
+export enum AnomalyAlgorithm {
+  RollingStdDev = 'rolling_stddev',
+  MAD = 'mad',
+  Seasonal = 'seasonal',
+}
+
+export const anomalyAlgorithms: Array<SelectableValue<AnomalyAlgorithm>> = [
+  {
+    value: AnomalyAlgorithm.RollingStdDev,
+    label: 'Rolling standard deviation',
+    description: 'Mean ± sensitivity × standard deviation of the values in the preceding window',
+  },
+  {
+    value: AnomalyAlgorithm.MAD,
+    label: 'Median absolute deviation',
+    description: 'Median ± sensitivity × median absolute deviation of the values in the preceding window',
+  },
+  {
+    value: AnomalyAlgorithm.Seasonal,
+    label: 'Seasonal',
+    description: 'Trend and seasonality with the given period ± sensitivity × standard deviation of the residuals',
+  },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -126,6 +205,12 @@ export interface ExpressionQuery extends DataQuery {
   window?: string;
   downsampler?: string;
   upsampler?: string;
+  alignment?: ResampleAlignment;
+  timezone?: string;
+  algorithm?: AnomalyAlgorithm;
+  period?: string;
+  sensitivity?: number;
+  bands?: boolean;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
 }
@@ -165,4 +250,11 @@ export type ReducerType =
   | 'diff_abs'
   | 'percent_diff'
   | 'percent_diff_abs'
-  | 'count_non_null';
+  | 'count_non_null'
+  | 'first'
+  | 'stddev'
+  | 'variance'
+  | 'range'
+  | 'increase'
+  | 'rate'
+  | `p${number}`;
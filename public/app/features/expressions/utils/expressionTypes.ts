@@ -1,7 +1,7 @@
 import { ReducerID } from '@grafana/data';
 
 import { EvalFunction } from '../../alerting/state/alertDef';
-import { ClassicCondition, ExpressionQuery, ExpressionQueryType } from '../types';
+import { AnomalyAlgorithm, ClassicCondition, ExpressionQuery, ExpressionQueryType } from '../types';
 
 export const getDefaults = (query: ExpressionQuery) => {
   switch (query.type) {
@@ -24,6 +24,18 @@ export const getDefaults = (query: ExpressionQuery) => {
       query.reducer = undefined;
       break;
 
+    case ExpressionQueryType.anomaly:
+      if (!query.algorithm) {
+        query.algorithm = AnomalyAlgorithm.RollingStdDev;
+      }
+
+      if (!query.window) {
+        query.window = '1h';
+      }
+
+      query.reducer = undefined;
+      break;
+
     case ExpressionQueryType.math:
       query.expression = undefined;
       break;
@@ -129,6 +129,28 @@ export function QueryHeader({
           options={QUERY_FORMAT_OPTIONS}
         />
 
+        {editorMode === EditorMode.Code && (
+          <InlineSwitch
+            id={`sql-parameterized-${uuidv4()}}`}
+            label="Parameterized"
+            transparent={true}
+            showLabel={true}
+            value={!!query.parameterized}
+            onChange={(ev) => {
+              if (!(ev.target instanceof HTMLInputElement)) {
+                return;
+              }
+
+              reportInteraction('grafana_sql_parameterized_toggled', {
+                datasource: query.datasource?.type,
+                displayed: ev.target.checked,
+              });
+
+              onChange({ ...query, parameterized: ev.target.checked });
+            }}
+          />
+        )}
+
         {editorMode === EditorMode.Builder && (
           <>
             <InlineSwitch
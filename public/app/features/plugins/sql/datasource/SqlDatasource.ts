@@ -38,6 +38,9 @@ import migrateAnnotation from '../utils/migration';
 
 import { isSqlDatasourceDatabaseSelectionFeatureFlagEnabled } from './../components/QueryEditorFeatureFlag.utils';
 
+// matches the $var, ${var} and [[var]] syntaxes of template variables the backend binds as parameters
+const PARAMETER_VARIABLE_REGEX = /\$(\w+)|\$\{(\w+)\}|\[\[(\w+)\]\]/g;
+
 export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLOptions> {
   id: number;
   responseParser: ResponseParser;
@@ -120,7 +123,19 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
   applyTemplateVariables(
     target: SQLQuery,
     scopedVars: ScopedVars
-  ): Record<string, string | DataSourceRef | SQLQuery['format']> {
+  ): Record<string, string | boolean | Record<string, string[]> | DataSourceRef | SQLQuery['format']> {
+    // the backend binds the values of the template variables as parameters of parameterized queries
+    if (target.parameterized) {
+      return {
+        refId: target.refId,
+        datasource: this.getRef(),
+        rawSql: target.rawSql ?? '',
+        format: target.format,
+        parameterized: true,
+        variables: this.getQueryVariables(scopedVars, target.rawSql),
+      };
+    }
+
     return {
       refId: target.refId,
       datasource: this.getRef(),
@@ -129,6 +144,28 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
     };
   }
 
+  /**
+   * Returns the values of the dashboard template variables referenced in the query by name. Global variables are
+   * interpolated by the backend.
+   */
+  getQueryVariables(scopedVars: ScopedVars, rawSql = ''): Record<string, string[]> {
+    const variables: Record<string, string[]> = {};
+    for (const match of rawSql.matchAll(PARAMETER_VARIABLE_REGEX)) {
+      const name = match[1] ?? match[2] ?? match[3];
+      if (name.startsWith('__') || name in variables || !this.templateSrv.containsTemplate(match[0])) {
+        continue;
+      }
+
+      let values: string[] = [];
+      this.templateSrv.replace(match[0], scopedVars, (value: string | string[]) => {
+        values = Array.isArray(value) ? value.map(String) : [String(value)];
+        return '';
+      });
+      variables[name] = values;
+    }
+    return variables;
+  }
+
   query(request: DataQueryRequest<SQLQuery>): Observable<DataQueryResponse> {
     // This logic reenables the previous SQL behavior regarding what databases are available for the user to query.
     if (isSqlDatasourceDatabaseSelectionFeatureFlagEnabled()) {
@@ -59,6 +59,8 @@ export interface SQLQuery extends DataQuery {
   sql?: SQLExpression;
   editorMode?: EditorMode;
   rawQuery?: boolean;
+  /** Runs the query as a prepared statement, binding the template variables and time macros as parameters */
+  parameterized?: boolean;
 }
 
 export interface NameValue {
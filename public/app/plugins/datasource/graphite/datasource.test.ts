@@ -419,6 +419,14 @@ describe('graphiteDatasource', () => {
         requestOptions = options;
         return of(createFetchResponse(['backend_01', 'backend_02']));
       });
+      jest.spyOn(ctx.ds, 'getResource').mockImplementation((path, params, options) => {
+        requestOptions = { ...options, method: 'GET', url: path, params };
+        return Promise.resolve(['backend_01', 'backend_02']);
+      });
+      jest.spyOn(ctx.ds, 'postResource').mockImplementation((path, data, options) => {
+        requestOptions = { ...options, method: 'POST', url: path, data };
+        return Promise.resolve(['backend_01', 'backend_02']);
+      });
     });
 
     it('should generate tags query', () => {
@@ -426,7 +434,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
     });
@@ -436,7 +444,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
@@ -446,7 +454,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
@@ -456,7 +464,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
@@ -467,7 +475,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual(['server=~backend*']);
       expect(results).not.toBe(null);
@@ -478,7 +486,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
@@ -489,7 +497,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual(['server=~backend*']);
       expect(results).not.toBe(null);
@@ -506,7 +514,7 @@ describe('graphiteDatasource', () => {
       ctx.ds.metricFindQuery('[[foo]]').then((data: any) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('metrics/find');
       expect(requestOptions.method).toEqual('POST');
       expect(requestOptions.headers).toHaveProperty('Content-Type', 'application/x-www-form-urlencoded');
       expect(requestOptions.data).toMatch(`query=bar`);
@@ -518,7 +526,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('metrics/find');
       expect(requestOptions.params).toEqual({});
       expect(requestOptions.data).toEqual('query=app.backend*');
       expect(results).not.toBe(null);
@@ -529,7 +537,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('metrics/find');
       expect(requestOptions.params).toEqual({});
       expect(requestOptions.data).toEqual('query=app.*');
       expect(results).not.toBe(null);
@@ -540,7 +548,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/expand');
+      expect(requestOptions.url).toBe('metrics/expand');
       expect(requestOptions.params?.query).toBe('*.servers.*');
       expect(results).not.toBe(null);
     });
@@ -550,7 +558,7 @@ describe('graphiteDatasource', () => {
       ctx.ds.metricFindQuery(stringQuery).then((data) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('metrics/find');
       expect(results).not.toBe(null);
 
       const objectQuery = {
@@ -560,7 +568,7 @@ describe('graphiteDatasource', () => {
         datasource: ctx.ds,
       };
       const data = await ctx.ds.metricFindQuery(objectQuery);
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('metrics/find');
       expect(data).toBeTruthy();
     });
 
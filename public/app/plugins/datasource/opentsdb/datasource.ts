@@ -13,26 +13,25 @@ import {
   map as _map,
   toPairs,
 } from 'lodash';
-import { lastValueFrom, merge, Observable, of } from 'rxjs';
+import { from, lastValueFrom, merge, Observable, of } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
 import {
   AnnotationEvent,
   DataQueryRequest,
   DataQueryResponse,
-  DataSourceApi,
   dateMath,
   ScopedVars,
   toDataFrame,
 } from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { AnnotationEditor } from './components/AnnotationEditor';
 import { prepareAnnotation } from './migrations';
 import { OpenTsdbFilter, OpenTsdbOptions, OpenTsdbQuery } from './types';
 
-export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenTsdbOptions> {
+export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuery, OpenTsdbOptions> {
   type: any;
   url: any;
   name: any;
@@ -260,11 +259,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _performSuggestQuery(query: string, type: string): Observable<any> {
-    return this._get('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
-      map((result: any) => {
-        return result.data;
-      })
-    );
+    return this._get('suggest', { type, q: query, max: this.lookupLimit });
   }
 
   _performMetricKeyValueLookup(metric: string, keys: any): Observable<any[]> {
@@ -284,9 +279,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
     const m = metric + '{' + keysQuery + '}';
 
-    return this._get('/api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
+    return this._get('search/lookup', { m: m, limit: this.lookupLimit }).pipe(
       map((result: any) => {
-        result = result.data.results;
+        result = result.results;
         const tagvs: any[] = [];
         each(result, (r) => {
           if (tagvs.indexOf(r.tags[key]) === -1) {
@@ -303,9 +298,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
-    return this._get('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
+    return this._get('search/lookup', { m: metric, limit: 1000 }).pipe(
       map((result: any) => {
-        result = result.data.results;
+        result = result.results;
         const tagks: any[] = [];
         each(result, (r) => {
           each(r.tags, (tagv, tagk) => {
@@ -320,18 +315,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _get(
-    relativeUrl: string,
+    resource: string,
     params?: { type?: string; q?: string; max?: number; m?: any; limit?: number }
-  ): Observable<FetchResponse> {
-    const options = {
-      method: 'GET',
-      url: this.url + relativeUrl,
-      params: params,
-    };
-
-    this._addCredentialOptions(options);
-
-    return getBackendSrv().fetch(options);
+  ): Observable<any> {
+    return from(this.getResource(resource, params));
   }
 
   _addCredentialOptions(options: any) {
@@ -397,26 +384,16 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     return Promise.resolve([]);
   }
 
-  testDatasource() {
-    return lastValueFrom(
-      this._performSuggestQuery('cpu', 'metrics').pipe(
-        map(() => {
-          return { status: 'success', message: 'Data source is working' };
-        })
-      )
-    );
-  }
-
   getAggregators() {
     if (this.aggregatorsPromise) {
       return this.aggregatorsPromise;
     }
 
     this.aggregatorsPromise = lastValueFrom(
-      this._get('/api/aggregators').pipe(
+      this._get('aggregators').pipe(
         map((result: any) => {
-          if (result.data && isArray(result.data)) {
-            return result.data.sort();
+          if (result && isArray(result)) {
+            return result.sort();
           }
           return [];
         })
@@ -431,10 +408,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     }
 
     this.filterTypesPromise = lastValueFrom(
-      this._get('/api/config/filters').pipe(
+      this._get('config/filters').pipe(
         map((result: any) => {
-          if (result.data) {
-            return Object.keys(result.data).sort();
+          if (result) {
+            return Object.keys(result).sort();
           }
           return [];
         })
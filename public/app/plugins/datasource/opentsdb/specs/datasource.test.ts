@@ -36,88 +36,89 @@ describe('opentsdb', () => {
     } as unknown as TemplateSrv;
 
     const ds = new OpenTsDatasource(instanceSettings, templateSrv);
+    const getResourceMock = jest.spyOn(ds, 'getResource').mockResolvedValue(data);
 
-    return { ds, templateSrv, fetchMock };
+    return { ds, templateSrv, fetchMock, getResourceMock };
   }
 
   describe('When performing metricFindQuery', () => {
     it('metrics() should generate api suggest query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('metrics(pew)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
-      expect(fetchMock.mock.calls[0][0].params?.type).toBe('metrics');
-      expect(fetchMock.mock.calls[0][0].params?.q).toBe('pew');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('suggest');
+      expect(getResourceMock.mock.calls[0][1]?.type).toBe('metrics');
+      expect(getResourceMock.mock.calls[0][1]?.q).toBe('pew');
       expect(results).not.toBe(null);
     });
 
     it('tag_names(cpu) should generate lookup query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('tag_names(cpu)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
-      expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('search/lookup');
+      expect(getResourceMock.mock.calls[0][1]?.m).toBe('cpu');
       expect(results).not.toBe(null);
     });
 
     it('tag_values(cpu, test) should generate lookup query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('tag_values(cpu, hostname)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
-      expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*}');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('search/lookup');
+      expect(getResourceMock.mock.calls[0][1]?.m).toBe('cpu{hostname=*}');
       expect(results).not.toBe(null);
     });
 
     it('tag_values(cpu, test) should generate lookup query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
-      expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env}');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('search/lookup');
+      expect(getResourceMock.mock.calls[0][1]?.m).toBe('cpu{hostname=*,env=$env}');
       expect(results).not.toBe(null);
     });
 
     it('tag_values(cpu, test) should generate lookup query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env, region=$region)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
-      expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env,region=$region}');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('search/lookup');
+      expect(getResourceMock.mock.calls[0][1]?.m).toBe('cpu{hostname=*,env=$env,region=$region}');
       expect(results).not.toBe(null);
     });
 
     it('suggest_tagk() should generate api suggest query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('suggest_tagk(foo)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
-      expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagk');
-      expect(fetchMock.mock.calls[0][0].params?.q).toBe('foo');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('suggest');
+      expect(getResourceMock.mock.calls[0][1]?.type).toBe('tagk');
+      expect(getResourceMock.mock.calls[0][1]?.q).toBe('foo');
       expect(results).not.toBe(null);
     });
 
     it('suggest_tagv() should generate api suggest query', async () => {
-      const { ds, fetchMock } = getTestcontext();
+      const { ds, getResourceMock } = getTestcontext();
 
       const results = await ds.metricFindQuery('suggest_tagv(bar)');
 
-      expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
-      expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagv');
-      expect(fetchMock.mock.calls[0][0].params?.q).toBe('bar');
+      expect(getResourceMock).toHaveBeenCalledTimes(1);
+      expect(getResourceMock.mock.calls[0][0]).toBe('suggest');
+      expect(getResourceMock.mock.calls[0][1]?.type).toBe('tagv');
+      expect(getResourceMock.mock.calls[0][1]?.q).toBe('bar');
       expect(results).not.toBe(null);
     });
   });
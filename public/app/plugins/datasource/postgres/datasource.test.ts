@@ -760,6 +760,27 @@ describe('PostgreSQLDatasource', () => {
     });
   });
 
+  describe('When applying template variables to a parameterized query', () => {
+    it('should send the values of the template variables instead of interpolating them', () => {
+      const values: Record<string, string | string[]> = { host: ['a', 'b'], env: 'dev' };
+      const templateSrv = {
+        containsTemplate: (text: string) => ['$host', '${env}'].includes(text),
+        replace: (text: string, _: unknown, format: (value: string | string[]) => string) =>
+          format(values[text.replace(/[${}]/g, '')]),
+      };
+      const { ds } = setupTestContext({}, undefined, templateSrv);
+      const rawSql = 'WHERE $__timeFilter(time) AND host IN ($host) AND env = ${env} AND $__interval_ms > 0';
+
+      expect(ds.applyTemplateVariables({ refId: 'A', rawSql, parameterized: true }, {})).toEqual(
+        expect.objectContaining({
+          rawSql,
+          parameterized: true,
+          variables: { host: ['a', 'b'], env: ['dev'] },
+        })
+      );
+    });
+  });
+
   describe('targetContainsTemplate', () => {
     it('given query that contains template variable it should return true', () => {
       const rawSql = `SELECT